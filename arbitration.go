@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNotPrimary indicates a Set was rejected by master arbitration because
+// another controller with a higher election ID is primary for the role
+var ErrNotPrimary = errors.New("not primary")
+
+// arbitration holds the master arbitration role and election ID of a client
+type arbitration struct {
+	role       string
+	electionID atomic.Uint64
+}
+
+// WithMasterArbitration attaches the gNMI MasterArbitration extension with
+// role and electionID to every Set of the client
+//
+// With master arbitration, the target accepts Set requests only from the
+// controller with the highest election ID for a role (the primary) and rejects
+// the others with PERMISSION_DENIED, reported as a GnmiError matching
+// ErrNotPrimary. This prevents split-brain writes of active/standby
+// controllers. An empty role selects the default role.
+//
+// Each client keeps its own election ID, so the option can be shared across
+// targets (e.g., via Manager ClientOptions). Use BumpElectionID or
+// SetElectionID when a controller takes over. A MasterArbitration request
+// modifier overrides the client election ID for a single Set.
+//
+// Example:
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.WithMasterArbitration("controller", electionID),
+//	)
+//	_, err = client.Set(ctx, ops)
+//	if errors.Is(err, gnmi.ErrNotPrimary) {
+//	    // Another controller is primary; stand by
+//	}
+func WithMasterArbitration(role string, electionID uint64) func(*Client) {
+	return func(c *Client) {
+		c.arbitration = &arbitration{role: role}
+		c.arbitration.electionID.Store(electionID)
+	}
+}
+
+// ElectionID returns the current master arbitration election ID
+//
+// Returns 0 if master arbitration is not enabled (see WithMasterArbitration).
+func (c *Client) ElectionID() uint64 {
+	if c.arbitration == nil {
+		return 0
+	}
+	return c.arbitration.electionID.Load()
+}
+
+// SetElectionID sets the master arbitration election ID of subsequent Set operations
+//
+// Thread-safe: Set operations in flight keep the election ID they were sent with.
+//
+// Returns an error if master arbitration is not enabled.
+func (c *Client) SetElectionID(id uint64) error {
+	if c.arbitration == nil {
+		return fmt.Errorf("master arbitration not enabled (see WithMasterArbitration)")
+	}
+	c.arbitration.electionID.Store(id)
+	return nil
+}
+
+// BumpElectionID increments the master arbitration election ID
+//
+// Call it when the controller takes over as primary: the target accepts the
+// higher election ID and rejects Sets of the previous primary with
+// PERMISSION_DENIED. The new election ID must be higher than the election ID
+// of every other controller of the role, so controllers typically bump past
+// the highest ID they know of (see SetElectionID).
+//
+// Example:
+//
+//	// Standby controller taking over
+//	id, err := client.BumpElectionID()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	log.Printf("primary with election ID %d", id)
+//
+// Returns the new election ID, or an error if master arbitration is not enabled.
+func (c *Client) BumpElectionID() (uint64, error) {
+	if c.arbitration == nil {
+		return 0, fmt.Errorf("master arbitration not enabled (see WithMasterArbitration)")
+	}
+	return c.arbitration.electionID.Add(1), nil
+}
+
+// applyArbitration attaches the client MasterArbitration extension to a Set
+// request unless the request carries one
+//
+// Returns true if the request carries a MasterArbitration extension.
+func (c *Client) applyArbitration(req *Req) bool {
+	if req.Extensions.MasterArbitration() != nil {
+		return true
+	}
+	if c.arbitration == nil {
+		return false
+	}
+	req.Extensions = append(req.Extensions[:len(req.Extensions):len(req.Extensions)],
+		newMasterArbitration(c.arbitration.role, c.arbitration.electionID.Load()))
+	return true
+}
+
+// isArbitrationError reports whether err rejects a Set with master arbitration
+func isArbitrationError(arbitrated bool, err error) bool {
+	return arbitrated && status.Code(err) == codes.PermissionDenied
+}
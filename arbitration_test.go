@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+// TestMasterArbitration_ElectionID tests the election ID accessors
+func TestMasterArbitration_ElectionID(t *testing.T) {
+	plain, err := NewClient("192.168.1.1")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if id := plain.ElectionID(); id != 0 {
+		t.Errorf("ElectionID() without arbitration = %d, want 0", id)
+	}
+	if err := plain.SetElectionID(1); err == nil {
+		t.Errorf("SetElectionID() without arbitration expected error")
+	}
+	if _, err := plain.BumpElectionID(); err == nil {
+		t.Errorf("BumpElectionID() without arbitration expected error")
+	}
+
+	// Clients sharing the option keep their own election ID
+	opt := WithMasterArbitration("controller", 10)
+	a, _ := NewClient("192.168.1.1", opt)
+	b, _ := NewClient("192.168.1.2", opt)
+	if id, err := a.BumpElectionID(); err != nil || id != 11 {
+		t.Errorf("BumpElectionID() = %d, %v, want 11", id, err)
+	}
+	if id := b.ElectionID(); id != 10 {
+		t.Errorf("ElectionID() of other client = %d, want 10", id)
+	}
+	if err := b.SetElectionID(20); err != nil || b.ElectionID() != 20 {
+		t.Errorf("SetElectionID() = %v, ElectionID() = %d, want 20", err, b.ElectionID())
+	}
+}
+
+// TestIntegration_MasterArbitration tests takeover between two controllers against the fake server
+func TestIntegration_MasterArbitration(t *testing.T) {
+	primary, srv := newIntegrationClient(t, WithMasterArbitration("controller", 10))
+	standby, err := NewClient(srv.Addr(), TLS(false), Username("admin"), Password("secret"), MaxRetries(0),
+		WithMasterArbitration("controller", 9))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = standby.Close() })
+
+	ctx := context.Background()
+	ops := []SetOperation{Update("/system/config/hostname", `"router2"`)}
+
+	if _, err := primary.Set(ctx, ops); err != nil {
+		t.Fatalf("primary Set() error = %v", err)
+	}
+	ma := Extensions(srv.SetRequests()[0].GetExtension()).MasterArbitration()
+	if ma.GetRole().GetId() != "controller" || ma.GetElectionId().GetLow() != 10 {
+		t.Errorf("sent arbitration = %v, want controller/10", ma)
+	}
+
+	_, err = standby.Set(ctx, ops)
+	if !errors.Is(err, ErrNotPrimary) || OperationCode(err) != codes.PermissionDenied {
+		t.Fatalf("standby Set() error = %v, want ErrNotPrimary", err)
+	}
+
+	// Standby takes over
+	if err := standby.SetElectionID(primary.ElectionID()); err != nil {
+		t.Fatalf("SetElectionID() error = %v", err)
+	}
+	if _, err := standby.BumpElectionID(); err != nil {
+		t.Fatalf("BumpElectionID() error = %v", err)
+	}
+	if _, err := standby.Set(ctx, ops); err != nil {
+		t.Fatalf("standby Set() after takeover error = %v", err)
+	}
+	if _, err := primary.Set(ctx, ops); !errors.Is(err, ErrNotPrimary) {
+		t.Errorf("former primary Set() error = %v, want ErrNotPrimary", err)
+	}
+	if id := srv.PrimaryElectionID("controller"); id != 11 {
+		t.Errorf("PrimaryElectionID() = %d, want 11", id)
+	}
+
+	// Request modifier overrides the client election ID
+	if _, err := primary.Set(ctx, ops, MasterArbitration("controller", 12)); err != nil {
+		t.Errorf("Set() with request election ID error = %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// Default circuit breaker configuration values
+const (
+	DefaultFailureThreshold = 5
+	DefaultOpenTimeout      = 30 * time.Second
+)
+
+// ErrCircuitOpen indicates the operation was rejected because the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets all operations through (normal operation)
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen rejects all operations with ErrCircuitOpen
+	CircuitOpen
+
+	// CircuitHalfOpen rejects operations while a probe checks the target
+	CircuitHalfOpen
+)
+
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreaker stops operations on a target that is down
+//
+// The breaker opens after FailureThreshold consecutive transport failures
+// (connection failures, Unavailable, DeadlineExceeded). While open, Get, Set,
+// Subscribe, and Capabilities fail immediately with a GnmiError matching
+// ErrCircuitOpen instead of spending retries and timeouts, and running retry
+// loops stop early. After OpenTimeout, the next operation half-opens the
+// breaker and probes the target with a Capabilities RPC (like Ping): on
+// success the breaker closes and the operation proceeds, otherwise it opens
+// again. Any response from the target, including gRPC errors other than
+// Unavailable and DeadlineExceeded, resets the failure count.
+//
+// A breaker is created per client by the WithCircuitBreaker option.
+// Transitions are logged and reported to the OnStateChange callback.
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive transport failures that opens the breaker
+	FailureThreshold int
+
+	// OpenTimeout is the time the breaker stays open before a probe is allowed
+	OpenTimeout time.Duration
+
+	// onStateChange is called on every state transition (nil if not set)
+	onStateChange func(target string, from, to CircuitState)
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// FailureThreshold sets the number of consecutive transport failures that opens the breaker (default: 5)
+func FailureThreshold(n int) func(*CircuitBreaker) {
+	return func(b *CircuitBreaker) {
+		b.FailureThreshold = n
+	}
+}
+
+// OpenTimeout sets the time the breaker stays open before probing the target (default: 30s)
+func OpenTimeout(d time.Duration) func(*CircuitBreaker) {
+	return func(b *CircuitBreaker) {
+		b.OpenTimeout = d
+	}
+}
+
+// OnStateChange sets a callback invoked on every circuit breaker state transition
+//
+// The callback runs synchronously on the goroutine of the operation that
+// caused the transition, possibly while the client holds its lock. It must
+// not block or call methods of the client.
+//
+// Example:
+//
+//	gnmi.WithCircuitBreaker(
+//	    gnmi.OnStateChange(func(target string, from, to gnmi.CircuitState) {
+//	        metrics.CircuitState.WithLabelValues(target).Set(float64(to))
+//	    }),
+//	)
+func OnStateChange(fn func(target string, from, to CircuitState)) func(*CircuitBreaker) {
+	return func(b *CircuitBreaker) {
+		b.onStateChange = fn
+	}
+}
+
+// allow checks whether an operation may proceed
+//
+// Returns probe=true if the breaker was half-opened and the caller must probe
+// the target, or an error wrapping ErrCircuitOpen if the operation is rejected.
+func (b *CircuitBreaker) allow(now time.Time) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if remaining := b.OpenTimeout - now.Sub(b.openedAt); remaining > 0 {
+			return false, fmt.Errorf("%w: retry in %s", ErrCircuitOpen, remaining.Round(time.Millisecond))
+		}
+		b.state = CircuitHalfOpen
+		return true, nil
+	case CircuitHalfOpen:
+		return false, fmt.Errorf("%w: probe in progress", ErrCircuitOpen)
+	default:
+		return false, nil
+	}
+}
+
+// record updates the breaker with the outcome of an operation
+//
+// Returns the state before and after.
+func (b *CircuitBreaker) record(failure bool, now time.Time) (CircuitState, CircuitState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from := b.state
+	if !failure {
+		b.failures = 0
+		b.state = CircuitClosed
+		return from, b.state
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= max(b.FailureThreshold, 1) {
+		b.state = CircuitOpen
+		b.openedAt = now
+	}
+	return from, b.state
+}
+
+// current returns the current state
+func (b *CircuitBreaker) current() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// WithCircuitBreaker enables a circuit breaker for the client
+//
+// Each client gets its own breaker, so the option can be shared across
+// targets (e.g., via Manager ClientOptions). See CircuitBreaker.
+//
+// Example:
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.WithCircuitBreaker(
+//	        gnmi.FailureThreshold(3),
+//	        gnmi.OpenTimeout(time.Minute),
+//	    ),
+//	)
+func WithCircuitBreaker(opts ...func(*CircuitBreaker)) func(*Client) {
+	return func(c *Client) {
+		b := &CircuitBreaker{
+			FailureThreshold: DefaultFailureThreshold,
+			OpenTimeout:      DefaultOpenTimeout,
+		}
+		for _, opt := range opts {
+			opt(b)
+		}
+		c.breaker = b
+	}
+}
+
+// CircuitState returns the state of the client's circuit breaker
+//
+// Returns CircuitClosed if no circuit breaker is configured.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.current()
+}
+
+// checkCircuit rejects operations while the circuit breaker is open
+//
+// If the open timeout has expired, the target is probed with a Capabilities
+// RPC first; the breaker closes if the probe succeeds.
+//
+// Returns nil if the operation may proceed, or an error wrapping ErrCircuitOpen.
+func (c *Client) checkCircuit(ctx context.Context) error {
+	if c.breaker == nil {
+		return nil
+	}
+
+	probe, err := c.breaker.allow(time.Now())
+	if err != nil {
+		return err
+	}
+	if !probe {
+		return nil
+	}
+	c.circuitTransition(ctx, CircuitOpen, CircuitHalfOpen)
+
+	// A probe that did not complete (e.g., ctx canceled) keeps the breaker open
+	_, probeErr := c.requestCapabilities(ctx, time.Now())
+	failed := probeErr != nil && (ctx.Err() != nil || isCircuitFailure(c, probeErr))
+	from, to := c.breaker.record(failed, time.Now())
+	c.circuitTransition(ctx, from, to)
+	if failed {
+		return fmt.Errorf("%w: probe failed: %w", ErrCircuitOpen, probeErr)
+	}
+	return nil
+}
+
+// recordOutcome updates the circuit breaker with the result of an RPC or
+// connection attempt
+//
+// Connection failures and transport errors (see isTransportError) count as
+// failures; any other response from the target counts as success. Errors
+// caused by ctx, a closed client, or an invalid request are ignored.
+func (c *Client) recordOutcome(ctx context.Context, err error) {
+	if c.breaker == nil {
+		return
+	}
+
+	if err != nil && (ctx.Err() != nil || errors.Is(err, ErrClosed) || errors.Is(err, ErrValidation)) {
+		return
+	}
+
+	from, to := c.breaker.record(err != nil && isCircuitFailure(c, err), time.Now())
+	c.circuitTransition(ctx, from, to)
+}
+
+// isCircuitFailure reports whether err is a connection failure or transport error
+//
+// Errors without a gRPC status come from establishing the connection.
+func isCircuitFailure(c *Client, err error) bool {
+	_, isGRPC := status.FromError(err)
+	return !isGRPC || c.isTransportError(err)
+}
+
+// circuitClosed reports whether operations may continue (no breaker or breaker closed)
+func (c *Client) circuitClosed() bool {
+	return c.breaker == nil || c.breaker.current() == CircuitClosed
+}
+
+// circuitTransition logs a state transition and reports it to the callback
+func (c *Client) circuitTransition(ctx context.Context, from, to CircuitState) {
+	if from == to {
+		return
+	}
+
+	if to == CircuitOpen {
+		c.logger.Warn(ctx, "gNMI circuit breaker opened",
+			"target", c.Target,
+			"from", from.String(),
+			"openTimeout", c.breaker.OpenTimeout.String())
+	} else {
+		c.logger.Info(ctx, "gNMI circuit breaker state changed",
+			"target", c.Target,
+			"from", from.String(),
+			"to", to.String())
+	}
+
+	if c.breaker.onStateChange != nil {
+		c.breaker.onStateChange(c.Target, from, to)
+	}
+}
@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+	"google.golang.org/grpc/codes"
+)
+
+// TestCircuitBreaker_States tests the state machine of the circuit breaker
+func TestCircuitBreaker_States(t *testing.T) {
+	b := &CircuitBreaker{FailureThreshold: 2, OpenTimeout: time.Minute}
+	now := time.Now()
+
+	if probe, err := b.allow(now); probe || err != nil {
+		t.Fatalf("allow() closed = %v, %v", probe, err)
+	}
+
+	// A success resets the consecutive failure count
+	b.record(true, now)
+	b.record(false, now)
+	if _, to := b.record(true, now); to != CircuitClosed {
+		t.Fatalf("state after non-consecutive failures = %v, want closed", to)
+	}
+	if from, to := b.record(true, now); from != CircuitClosed || to != CircuitOpen {
+		t.Fatalf("transition = %v -> %v, want closed -> open", from, to)
+	}
+
+	if _, err := b.allow(now.Add(time.Second)); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() open error = %v, want ErrCircuitOpen", err)
+	}
+
+	// After the open timeout, one caller probes while others are rejected
+	later := now.Add(time.Minute)
+	if probe, err := b.allow(later); !probe || err != nil {
+		t.Fatalf("allow() after timeout = %v, %v, want probe", probe, err)
+	}
+	if _, err := b.allow(later); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() half-open error = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed probe reopens the breaker for another timeout
+	if from, to := b.record(true, later); from != CircuitHalfOpen || to != CircuitOpen {
+		t.Errorf("transition = %v -> %v, want half-open -> open", from, to)
+	}
+	if _, err := b.allow(later.Add(time.Second)); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() reopened error = %v, want ErrCircuitOpen", err)
+	}
+
+	if probe, _ := b.allow(later.Add(time.Minute)); !probe {
+		t.Fatalf("allow() did not probe after second timeout")
+	}
+	if from, to := b.record(false, later); from != CircuitHalfOpen || to != CircuitClosed {
+		t.Errorf("transition = %v -> %v, want half-open -> closed", from, to)
+	}
+
+	if s := CircuitHalfOpen.String(); s != "half-open" {
+		t.Errorf("String() = %q, want half-open", s)
+	}
+}
+
+// transitionRecorder collects circuit breaker transitions
+type transitionRecorder struct {
+	mu          sync.Mutex
+	transitions []string
+}
+
+// record implements the OnStateChange callback
+func (r *transitionRecorder) record(_ string, from, to CircuitState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transitions = append(r.transitions, from.String()+"->"+to.String())
+}
+
+// get returns the recorded transitions
+func (r *transitionRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.transitions...)
+}
+
+// TestIntegration_CircuitBreaker tests opening, fast failure, and probing against the fake server
+func TestIntegration_CircuitBreaker(t *testing.T) {
+	rec := &transitionRecorder{}
+	client, srv := newIntegrationClient(t,
+		MaxRetries(5),
+		WithCircuitBreaker(FailureThreshold(2), OpenTimeout(100*time.Millisecond), OnStateChange(rec.record)),
+	)
+	ctx := context.Background()
+	paths := []string{"/system/config"}
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	// Permanent errors mean the target responds and do not count
+	srv.FailNext(gnmitest.RPCGet, codes.InvalidArgument, 3)
+	for i := 0; i < 3; i++ {
+		_, _ = client.Get(ctx, paths)
+	}
+	if state := client.CircuitState(); state != CircuitClosed {
+		t.Fatalf("CircuitState() after permanent errors = %v, want closed", state)
+	}
+
+	// Transport failures open the breaker and stop the retry loop
+	srv.FailNext(gnmitest.RPCGet, codes.Unavailable, 2)
+	getCalls := srv.Calls(gnmitest.RPCGet)
+	if _, err := client.Get(ctx, paths); err == nil {
+		t.Fatalf("Get() expected error")
+	}
+	if calls := srv.Calls(gnmitest.RPCGet) - getCalls; calls != 2 {
+		t.Errorf("Get() calls = %d, want 2 (retries stopped by open breaker)", calls)
+	}
+	if state := client.CircuitState(); state != CircuitOpen {
+		t.Fatalf("CircuitState() = %v, want open", state)
+	}
+
+	// Open: operations fail fast without reaching the target
+	getCalls = srv.Calls(gnmitest.RPCGet)
+	_, err := client.Get(ctx, paths)
+	var gnmiErr *GnmiError
+	if !errors.Is(err, ErrCircuitOpen) || !errors.As(err, &gnmiErr) || gnmiErr.Operation != "Get" {
+		t.Errorf("Get() error = %v, want GnmiError matching ErrCircuitOpen", err)
+	}
+	if _, err := client.Set(ctx, []SetOperation{Delete("/system/config/hostname")}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Set() error = %v, want ErrCircuitOpen", err)
+	}
+	if err := client.Ping(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Ping() error = %v, want ErrCircuitOpen", err)
+	}
+	if calls := srv.Calls(gnmitest.RPCGet) - getCalls; calls != 0 {
+		t.Errorf("Get() calls while open = %d, want 0", calls)
+	}
+
+	// Failed probe: the breaker opens again
+	time.Sleep(150 * time.Millisecond)
+	srv.FailNext(gnmitest.RPCCapabilities, codes.Unavailable, 1)
+	if _, err := client.Get(ctx, paths); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Get() error after failed probe = %v, want ErrCircuitOpen", err)
+	}
+	if state := client.CircuitState(); state != CircuitOpen {
+		t.Errorf("CircuitState() after failed probe = %v, want open", state)
+	}
+
+	// Successful probe: the breaker closes and the operation proceeds
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.Get(ctx, paths); err != nil {
+		t.Fatalf("Get() after successful probe error = %v", err)
+	}
+	if state := client.CircuitState(); state != CircuitClosed {
+		t.Errorf("CircuitState() = %v, want closed", state)
+	}
+
+	want := []string{
+		"closed->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->closed",
+	}
+	if got := rec.get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("transitions = %v, want %v", got, want)
+	}
+}
+
+// TestIntegration_CircuitBreakerConnectionFailure tests that connection failures open the breaker
+func TestIntegration_CircuitBreakerConnectionFailure(t *testing.T) {
+	client, srv := newIntegrationClient(t,
+		MaxRetries(0),
+		ConnectTimeout(200*time.Millisecond),
+		WithCircuitBreaker(FailureThreshold(1), OpenTimeout(time.Hour)),
+	)
+	srv.Close()
+
+	if _, err := client.Get(context.Background(), []string{"/system/config"}); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Get() error = %v, want connection failure", err)
+	}
+	if state := client.CircuitState(); state != CircuitOpen {
+		t.Fatalf("CircuitState() = %v, want open", state)
+	}
+
+	start := time.Now()
+	if _, err := client.Get(context.Background(), []string{"/system/config"}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Get() error = %v, want ErrCircuitOpen", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Get() took %v, want fast failure", elapsed)
+	}
+}
@@ -6,21 +6,21 @@ package gnmi
 import (
 	"bytes"
 	"context"
-	"crypto/rand"
-	"encoding/binary"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"math"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/openconfig/gnmic/pkg/api"
 	target "github.com/openconfig/gnmic/pkg/api/target"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 )
 
@@ -69,6 +69,27 @@ type Client struct {
 	// connected tracks if connection has been established (lazy)
 	connected bool
 
+	// closed is set by Close; the client cannot be reconnected afterwards
+	closed bool
+
+	// pool manages idle eviction and connection limits (nil if not pooled)
+	pool *Pool
+
+	// breaker fails operations fast while the target is down (nil if disabled)
+	breaker *CircuitBreaker
+
+	// arbitration attaches MasterArbitration to every Set (nil if disabled)
+	arbitration *arbitration
+
+	// limiter throttles operations (nil if neither rate nor concurrency is limited)
+	limiter *limiter
+
+	// lastUsed is the time of the last operation (nanoseconds since Unix epoch)
+	lastUsed atomic.Int64
+
+	// inflight counts running operations and open subscriptions
+	inflight atomic.Int32
+
 	// RWMutex to synchronize access to mutable state
 	mu sync.RWMutex
 
@@ -78,11 +99,27 @@ type Client struct {
 	username string // unexported for security
 	password string // unexported for security
 
+	// credentials are attached to every request (nil if not configured)
+	credentials credentials.PerRPCCredentials
+
+	// credentialProvider supplies username and password at connect time (nil if not configured)
+	credentialProvider       CredentialProvider
+	refreshOnUnauthenticated bool
+
 	// TLS configuration
 	tlsCert string // unexported for security
 	tlsKey  string // unexported for security
 	tlsCA   string // unexported for security
 
+	// In-memory TLS material and TLS overrides (see tls.go)
+	tlsCertPEM              []byte // unexported for security
+	tlsKeyPEM               []byte // unexported for security
+	tlsCAPEM                []byte
+	tlsConfig               *tls.Config
+	tlsGetClientCertificate func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
+	tlsServerName           string
+	tlsMinVersion           uint16
+
 	// TLS options
 	UseTLS             bool
 	VerifyCertificate  bool
@@ -98,9 +135,18 @@ type Client struct {
 	BackoffMaxDelay    time.Duration
 	BackoffDelayFactor float64
 
+	// RetryPolicy overrides MaxRetries and the backoff settings (nil uses them)
+	RetryPolicy RetryPolicy
+
 	// Capability tracking (gNMI capabilities from CapabilityResponse)
 	capabilities []string
 
+	// metrics receives operation measurements (nil if not configured)
+	metrics Metrics
+
+	// tracing creates OpenTelemetry spans for operations (nil if disabled)
+	tracing *Tracing
+
 	// Logging configuration
 	logger            Logger
 	prettyPrintLogs   bool
@@ -141,19 +187,20 @@ type Client struct {
 func NewClient(target string, opts ...func(*Client)) (*Client, error) {
 	// Create client with default values
 	client := &Client{
-		Target:             target,
-		Port:               DefaultPort,
-		UseTLS:             DefaultUseTLS,
-		VerifyCertificate:  DefaultVerifyCertificate,
-		ConnectTimeout:     DefaultConnectTimeout,
-		OperationTimeout:   DefaultOperationTimeout,
-		MaxRetries:         DefaultMaxRetries,
-		BackoffMinDelay:    DefaultBackoffMinDelay,
-		BackoffMaxDelay:    DefaultBackoffMaxDelay,
-		BackoffDelayFactor: DefaultBackoffDelayFactor,
-		logger:             &NoOpLogger{},
-		prettyPrintLogs:    DefaultPrettyPrintLogs,
-		redactionPatterns:  defaultRedactionPatterns,
+		Target:                   target,
+		Port:                     DefaultPort,
+		UseTLS:                   DefaultUseTLS,
+		VerifyCertificate:        DefaultVerifyCertificate,
+		ConnectTimeout:           DefaultConnectTimeout,
+		OperationTimeout:         DefaultOperationTimeout,
+		MaxRetries:               DefaultMaxRetries,
+		BackoffMinDelay:          DefaultBackoffMinDelay,
+		BackoffMaxDelay:          DefaultBackoffMaxDelay,
+		BackoffDelayFactor:       DefaultBackoffDelayFactor,
+		refreshOnUnauthenticated: true,
+		logger:                   &NoOpLogger{},
+		prettyPrintLogs:          DefaultPrettyPrintLogs,
+		redactionPatterns:        defaultRedactionPatterns,
 	}
 
 	// Apply functional options
@@ -174,6 +221,10 @@ func NewClient(target string, opts ...func(*Client)) (*Client, error) {
 		return nil, err
 	}
 
+	if client.pool != nil {
+		client.pool.add(client)
+	}
+
 	// Log client creation (connection happens lazily)
 	client.logger.Info(context.Background(), "gNMI client created",
 		"target", client.Target,
@@ -208,15 +259,8 @@ func NewClient(target string, opts ...func(*Client)) (*Client, error) {
 //	// Automatically reconnects on next use
 //	_, err = client.Get(ctx, paths)
 //
-// Example - Connection pooling with idle timeout:
-//
-//	// Disconnect idle connections periodically
-//	ticker := time.NewTicker(5 * time.Minute)
-//	go func() {
-//	    for range ticker.C {
-//	        client.Disconnect() // Release idle connection
-//	    }
-//	}()
+// For automatic idle disconnection and health checking, use a Pool
+// (see ConnectionPool) instead of calling Disconnect periodically.
 //
 // Example - Long-running application pattern:
 //
@@ -248,6 +292,9 @@ func (c *Client) Disconnect() error {
 	// Reset connected flag
 	// Target remains valid and can be reconnected via ensureConnected()
 	c.connected = false
+	if c.pool != nil {
+		c.pool.release(c)
+	}
 
 	c.logger.Info(context.Background(), "gNMI connection disconnected",
 		"target", c.Target,
@@ -291,6 +338,10 @@ func (c *Client) Close() error {
 	target := c.target
 	c.target = nil
 	c.connected = false
+	c.closed = true
+	if c.pool != nil {
+		c.pool.remove(c)
+	}
 
 	err := target.Close()
 	if err != nil {
@@ -356,7 +407,8 @@ func (c *Client) ServerCapabilities() []string {
 func (c *Client) HasCredentials() bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.username != "" || c.password != "" || c.tlsCert != ""
+	return c.username != "" || c.password != "" || c.tlsCert != "" || c.tlsCertPEM != nil ||
+		c.tlsGetClientCertificate != nil || c.credentials != nil || c.credentialProvider != nil
 }
 
 // Backoff calculates the backoff delay for retry attempt using exponential backoff with jitter
@@ -374,52 +426,22 @@ func (c *Client) HasCredentials() bool {
 //
 // Returns the duration to wait before retrying.
 func (c *Client) Backoff(attempt int) time.Duration {
-	// Calculate base delay: minDelay * (factor ^ attempt)
-	delay := float64(c.BackoffMinDelay) * math.Pow(c.BackoffDelayFactor, float64(attempt))
-
-	// Check for overflow and cap at max delay
-	if math.IsInf(delay, 1) || delay > float64(c.BackoffMaxDelay) {
-		delay = float64(c.BackoffMaxDelay)
-	}
-
-	baseDelay := delay // Store base delay for logging
-
-	// Add cryptographically secure jitter (0-10% of delay) to prevent thundering herd
-	jitterMax := int64(delay * 0.1)
-	var jitterVal int64
-	if jitterMax > 0 {
-		var jitterBytes [8]byte
-		if _, err := rand.Read(jitterBytes[:]); err == nil {
-			// Convert bytes to int64, masking to prevent overflow
-			// Mask off sign bit to ensure positive value within int64 range
-			//nolint:gosec // G115: False positive - explicitly masked to prevent overflow
-			jitterVal = int64(binary.BigEndian.Uint64(jitterBytes[:]) & 0x7FFFFFFFFFFFFFFF)
-			jitterVal = jitterVal % jitterMax
-			delay += float64(jitterVal)
-		} else {
-			// Fallback to timestamp-based jitter if crypto/rand fails
-			// This is not cryptographically secure but prevents thundering herd
-			timestamp := time.Now().UnixNano()
-			jitterVal = (timestamp%jitterMax + jitterMax) % jitterMax // Ensure positive
-			delay += float64(jitterVal)
-
-			c.logger.Warn(context.Background(), "crypto/rand failed, using timestamp-based jitter",
-				"error", err.Error(),
-				"attempt", attempt,
-				"jitter_ms", time.Duration(jitterVal).Milliseconds())
-		}
+	delay, baseDelay, jitter, err := exponentialDelay(c.BackoffMinDelay, c.BackoffMaxDelay, c.BackoffDelayFactor, attempt)
+	if err != nil {
+		c.logger.Warn(context.Background(), "crypto/rand failed, using timestamp-based jitter",
+			"error", err.Error(),
+			"attempt", attempt,
+			"jitter_ms", jitter.Milliseconds())
 	}
 
-	finalDelay := time.Duration(delay)
-
 	// Log backoff calculation at Debug level
 	c.logger.Debug(context.Background(), "Backoff calculated",
 		"attempt", attempt,
-		"base_delay_ms", time.Duration(baseDelay).Milliseconds(),
-		"jitter_ms", time.Duration(jitterVal).Milliseconds(),
-		"final_delay_ms", finalDelay.Milliseconds())
+		"base_delay_ms", baseDelay.Milliseconds(),
+		"jitter_ms", jitter.Milliseconds(),
+		"final_delay_ms", delay.Milliseconds())
 
-	return finalDelay
+	return delay
 }
 
 // prepareJSONForLogging redacts sensitive data and formats JSON for logging
@@ -634,6 +656,7 @@ func (c *Client) checkTransientErrorModels(errs []ErrorModel) bool {
 //   - Positive retry params (MaxRetries >= 0, BackoffMinDelay > 0, BackoffMaxDelay > BackoffMinDelay)
 //   - BackoffDelayFactor >= 1.0
 //   - TLS certificate file paths exist (if provided)
+//   - TLS options and in-memory TLS material (see TLSCertPEM, TLSConfig)
 //
 // Returns an error if validation fails.
 func (c *Client) validateConfig() error {
@@ -670,6 +693,18 @@ func (c *Client) validateConfig() error {
 		return fmt.Errorf("backoff delay factor must be >= 1.0, got: %f", c.BackoffDelayFactor)
 	}
 
+	// Validate request limits
+	if c.limiter != nil {
+		if err := c.limiter.validate(); err != nil {
+			return err
+		}
+	}
+
+	// Validate per-RPC credentials
+	if err := c.validateCredentials(); err != nil {
+		return err
+	}
+
 	// Warn on insecure TLS configuration
 	if c.UseTLS && c.InsecureSkipVerify {
 		c.logger.Warn(context.Background(), "InsecureSkipVerify enabled - TLS certificate verification disabled",
@@ -717,6 +752,11 @@ func (c *Client) validateConfig() error {
 		}
 	}
 
+	// Validate TLS options and in-memory TLS material
+	if err := c.validateTLS(); err != nil {
+		return err
+	}
+
 	// Warn if credentials are missing (not an error, but may be required by device)
 	if !c.HasCredentials() {
 		c.logger.Warn(context.Background(), "No credentials configured",
@@ -772,6 +812,17 @@ func (c *Client) createTarget() error {
 	targetOpts = append(targetOpts, api.Insecure(!c.UseTLS))
 	targetOpts = append(targetOpts, api.SkipVerify(c.InsecureSkipVerify))
 
+	// Add TLS configuration built from in-memory material and overrides
+	if c.UseTLS {
+		tlsConfig, err := c.buildTLSConfig()
+		if err != nil {
+			return err
+		}
+		if tlsConfig != nil {
+			targetOpts = append(targetOpts, api.TLSConfig(tlsConfig))
+		}
+	}
+
 	// Create target (configuration only, NO connection)
 	t, err := api.NewTarget(targetOpts...)
 	if err != nil {
@@ -792,14 +843,29 @@ func (c *Client) createTarget() error {
 //
 // Thread-safe: acquires client mutex before checking/establishing connection.
 //
-// Returns an error if connection establishment fails.
+// For pooled clients, a connection slot is reserved first (see Pool).
+//
+// Returns an error wrapping ErrNotConnected (and ErrClosed after Close) if
+// there is no target, or the dial error if connection establishment fails.
 func (c *Client) ensureConnected(ctx context.Context) error {
+	if c.pool != nil {
+		if err := c.pool.acquire(ctx, c); err != nil {
+			return err
+		}
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// Check if target exists
 	if c.target == nil {
-		return fmt.Errorf("client not connected")
+		if c.pool != nil {
+			c.pool.release(c)
+		}
+		if c.closed {
+			return fmt.Errorf("%w: %w", ErrNotConnected, ErrClosed)
+		}
+		return ErrNotConnected
 	}
 
 	// Check if already connected
@@ -812,8 +878,25 @@ func (c *Client) ensureConnected(ctx context.Context) error {
 		"target", c.Target,
 		"port", c.Port)
 
-	err := c.target.CreateGNMIClient(ctx)
+	// Recreate the target with the current credentials of the provider
+	if c.credentialProvider != nil {
+		err := c.loadCredentials(ctx)
+		if err == nil {
+			err = c.createTarget()
+		}
+		if err != nil {
+			if c.pool != nil {
+				c.pool.release(c)
+			}
+			return err
+		}
+	}
+
+	err := c.target.CreateGNMIClient(ctx, c.credentialDialOptions()...)
 	if err != nil {
+		if c.pool != nil {
+			c.pool.release(c)
+		}
 		return fmt.Errorf("failed to establish connection: %w", err)
 	}
 
@@ -848,25 +931,67 @@ func (c *Client) ensureConnected(ctx context.Context) error {
 //	for _, cap := range res.Capabilities {
 //	    fmt.Printf("Encoding: %s\n", cap)
 //	}
+//
+// Returns CapabilitiesRes or a *GnmiError if the request fails.
 func (c *Client) Capabilities(ctx context.Context) (CapabilitiesRes, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "Capabilities")
+	res, err := c.doCapabilities(ctx, start)
+	c.observeOperation(ctx, "Capabilities", start, err)
+	endSpan(span, err)
+	return res, err
+}
+
+// doCapabilities implements Capabilities; start is the start time of the operation
+func (c *Client) doCapabilities(ctx context.Context, start time.Time) (CapabilitiesRes, error) {
+	defer c.beginOperation()()
+
 	// Check context cancellation first (before acquiring lock)
 	if err := checkContextCancellation(ctx); err != nil {
 		return CapabilitiesRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, err
+		}, newGnmiError("Capabilities", nil, err, start)
+	}
+
+	// Fail fast while the circuit breaker is open
+	if err := c.checkCircuit(ctx); err != nil {
+		return CapabilitiesRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Capabilities", ErrCircuitOpen, err, start)
 	}
 
+	// Wait for the request limiter
+	release, err := c.waitLimiter(ctx, "Capabilities")
+	if err != nil {
+		return CapabilitiesRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Capabilities", nil, err, start)
+	}
+	defer release()
+
+	res, err := c.requestCapabilities(ctx, start)
+	c.recordOutcome(ctx, err)
+	return res, err
+}
+
+// requestCapabilities performs the Capabilities RPC without circuit breaker checks
+//
+// Used by Capabilities and to probe the target when the circuit breaker is
+// half-open.
+func (c *Client) requestCapabilities(ctx context.Context, start time.Time) (CapabilitiesRes, error) {
 	// Ensure connection is established (lazy connection)
 	if err := c.ensureConnected(ctx); err != nil {
 		return CapabilitiesRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, err
+		}, newGnmiError("Capabilities", ErrNotConnected, fmt.Errorf("connection failed: %w", err), start)
 	}
 
 	// Apply operation timeout
-	ctx, cancel := context.WithTimeout(ctx, c.OperationTimeout)
+	rpcCtx, cancel := context.WithTimeout(ctx, c.OperationTimeout)
 	defer cancel()
 
 	// Log operation start
@@ -875,15 +1000,18 @@ func (c *Client) Capabilities(ctx context.Context) (CapabilitiesRes, error) {
 
 	// Execute request using gnmic target API
 	// Note: gnmic target.Capabilities() takes context and optional extensions
-	resp, err := c.target.Capabilities(ctx)
+	resp, err := c.target.Capabilities(rpcCtx)
+	c.observeBytes(ctx, "Capabilities", nil, resp)
 	if err != nil {
 		c.logger.Error(ctx, "gNMI Capabilities failed",
 			"target", c.Target,
 			"error", err.Error())
+		gnmiErr := newGnmiError("Capabilities", nil, fmt.Errorf("request failed: %w", err), start)
+		gnmiErr.Errors = c.extractErrorDetails(err)
 		return CapabilitiesRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, fmt.Errorf("capabilities request failed: %w", err)
+		}, gnmiErr
 	}
 
 	// Parse response
@@ -941,9 +1069,16 @@ func (c *Client) Ping(ctx context.Context) error {
 // This method closes the existing (broken) connection and establishes a new one.
 // Used when transport errors are detected during Get/Set operations.
 //
-// PRECONDITION: Caller must hold c.mu.Lock() (write lock).
+// For pooled clients, a connection slot is reserved before dialing and
+// released if reconnection fails. c.mu is released while waiting for a slot,
+// so the pool can evict this client and Close is not blocked; if another
+// caller reconnected in the meantime, its connection is reused.
 //
-// Returns an error if reconnection fails.
+// PRECONDITION: Caller must hold c.mu.Lock() (write lock). The lock is held
+// again when reconnect returns.
+//
+// Returns ErrClosed if the client was closed while waiting for a slot, or an
+// error if reconnection fails.
 func (c *Client) reconnect(ctx context.Context) error {
 	c.logger.Warn(ctx, "gNMI reconnecting",
 		"target", c.Target,
@@ -957,25 +1092,65 @@ func (c *Client) reconnect(ctx context.Context) error {
 	// Reset connection flag
 	c.connected = false
 
+	// Reserve a connection slot (released by an earlier failed reconnect or
+	// eviction) without holding c.mu, as ensureConnected does
+	if c.pool != nil {
+		c.mu.Unlock()
+		err := c.pool.acquire(ctx, c)
+		c.mu.Lock()
+		if err != nil {
+			c.observeReconnect(ctx, err)
+			return err
+		}
+		if c.target == nil {
+			c.pool.release(c)
+			return ErrClosed
+		}
+		if c.connected {
+			// Another caller reconnected while this one waited
+			return nil
+		}
+	}
+
+	// Query the credential provider for the current credentials
+	if err := c.loadCredentials(ctx); err != nil {
+		if c.pool != nil {
+			c.pool.release(c)
+		}
+		c.observeReconnect(ctx, err)
+		return err
+	}
+
 	// Recreate target configuration
 	if err := c.createTarget(); err != nil {
 		c.logger.Error(ctx, "gNMI target recreation failed",
 			"target", c.Target,
 			"error", err.Error())
-		return fmt.Errorf("failed to recreate target: %w", err)
+		if c.pool != nil {
+			c.pool.release(c)
+		}
+		err = fmt.Errorf("failed to recreate target: %w", err)
+		c.observeReconnect(ctx, err)
+		return err
 	}
 
 	// Establish new connection
-	err := c.target.CreateGNMIClient(ctx)
+	err := c.target.CreateGNMIClient(ctx, c.credentialDialOptions()...)
 	if err != nil {
 		c.logger.Error(ctx, "gNMI reconnection failed",
 			"target", c.Target,
 			"error", err.Error())
-		return fmt.Errorf("failed to reconnect: %w", err)
+		if c.pool != nil {
+			c.pool.release(c)
+		}
+		err = fmt.Errorf("failed to reconnect: %w", err)
+		c.observeReconnect(ctx, err)
+		return err
 	}
 
 	// Mark as connected
 	c.connected = true
+	c.observeReconnect(ctx, nil)
 
 	c.logger.Info(ctx, "gNMI reconnected",
 		"target", c.Target)
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api"
+)
+
+// Commit is a handle for a commit-confirmed Set transaction
+//
+// A commit-confirmed Set (see CommitConfirmed) is applied by the target, but
+// rolled back automatically unless confirmed within the rollback duration.
+// If the change cuts off the management connection, the device restores the
+// previous configuration without operator intervention.
+//
+// A Commit is returned in SetRes.Commit and can also be obtained for an
+// existing commit ID with Client.Commit. A Commit returned by Set remembers
+// the Target, Prefix, and Origin of the Set, so confirmations reach the same
+// target behind a gNMI gateway.
+type Commit struct {
+	// ID is the commit ID sent in the gNMI commit extension
+	ID string
+
+	client *Client
+	target string
+	prefix string
+	origin string
+}
+
+// CommitConfirmed returns a request modifier that makes Set a commit-confirmed
+// transaction using the gNMI commit extension.
+//
+// The target rolls back the Set unless it is confirmed (Commit.Confirm)
+// within rollback. The Commit handle is returned in SetRes.Commit. The commit
+// ID is generated unless set with CommitID. A commit-confirmed Set is not
+// retried; if it fails, SetRes.Commit can still be used to Cancel it.
+//
+// Example:
+//
+//	res, err := client.Set(ctx, ops, gnmi.CommitConfirmed(5*time.Minute))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	// Verify the device, then keep the change
+//	err = res.Commit.Confirm(ctx)
+func CommitConfirmed(rollback time.Duration) func(*Req) {
+	return func(req *Req) {
+		req.RollbackDuration = rollback
+	}
+}
+
+// CommitID returns a request modifier that sets the ID of a commit-confirmed
+// Set (default: generated).
+//
+// Use it together with CommitConfirmed, e.g. to confirm the commit from
+// another process with Client.Commit.
+func CommitID(id string) func(*Req) {
+	return func(req *Req) {
+		req.CommitID = id
+	}
+}
+
+// Commit returns a handle for the commit-confirmed Set with the given ID
+//
+// Use it to confirm or cancel a commit started by another client or process.
+//
+// Example:
+//
+//	err := client.Commit("change-4711").Cancel(ctx)
+func (c *Client) Commit(id string) *Commit {
+	return &Commit{ID: id, client: c}
+}
+
+// Confirm confirms the commit, so the target keeps the configuration
+//
+// Confirm is sent as a Set request without operations and uses the retry,
+// timeout, and connection handling of Set. The request modifiers (e.g.
+// Timeout, Target) are applied to the request after the Target, Prefix, and
+// Origin remembered from the commit-confirmed Set.
+//
+// Returns an error if the target rejects the confirmation, e.g. because the
+// rollback duration already expired.
+func (c *Commit) Confirm(ctx context.Context, mods ...func(*Req)) error {
+	return c.action(ctx, api.Extension_CommitConfirm(c.ID), mods)
+}
+
+// Cancel cancels the commit, so the target rolls back the configuration immediately
+//
+// Returns an error if the target rejects the cancellation.
+func (c *Commit) Cancel(ctx context.Context, mods ...func(*Req)) error {
+	return c.action(ctx, api.Extension_CommitCancel(c.ID), mods)
+}
+
+// SetRollbackDuration replaces the rollback duration of the commit
+//
+// The target restarts the rollback timer with the new duration, e.g. to extend
+// the time available for verification.
+//
+// Returns an error if the duration is not positive or the target rejects it.
+func (c *Commit) SetRollbackDuration(ctx context.Context, rollback time.Duration, mods ...func(*Req)) error {
+	if rollback <= 0 {
+		return newGnmiError("Set", ErrValidation,
+			fmt.Errorf("rollback duration must be positive, got: %s", rollback), time.Now())
+	}
+	return c.action(ctx, api.Extension_CommitSetRollbackDuration(c.ID, rollback), mods)
+}
+
+// action sends a Set request without operations carrying a commit action
+func (c *Commit) action(ctx context.Context, ext api.GNMIOption, mods []func(*Req)) error {
+	all := make([]func(*Req), 0, len(mods)+2)
+	all = append(all, func(req *Req) {
+		req.Target = c.target
+		req.Prefix = c.prefix
+		req.Origin = c.origin
+	})
+	all = append(all, mods...)
+	all = append(all, func(req *Req) {
+		req.commitAction = ext
+	})
+	_, err := c.client.Set(ctx, nil, all...)
+	return err
+}
+
+// withoutCommitRequest returns mods followed by a modifier that removes the
+// CommitConfirmed and CommitID settings, so the modifiers of a
+// commit-confirmed Set can be reused for its confirmation
+func withoutCommitRequest(mods []func(*Req)) []func(*Req) {
+	return append(mods[:len(mods):len(mods)], func(req *Req) {
+		req.RollbackDuration = 0
+		req.CommitID = ""
+	})
+}
+
+// SetConfirmed applies ops as a commit-confirmed Set and confirms the commit
+// only after check succeeds
+//
+// If check returns an error, the commit is canceled and the target rolls back
+// the configuration. The cancellation is sent with a fresh context bounded by
+// OperationTimeout, so it is attempted even if ctx expired during the check.
+// If the cancellation fails as well (e.g. because the change cut off the
+// connection), the target rolls back when the rollback duration expires.
+// check is typically a Get or Ping verifying that the device is reachable and
+// healthy; it is called with ctx. The request modifiers (e.g. Target,
+// Timeout) are applied to the confirmation and cancellation as well.
+//
+// Example:
+//
+//	res, err := client.SetConfirmed(ctx, ops, 2*time.Minute,
+//	    func(ctx context.Context) error {
+//	        _, err := client.Get(ctx, []string{"/interfaces/interface[name=Gi0/0/0/0]/state/oper-status"})
+//	        return err
+//	    })
+//
+// Returns the SetRes of the commit-confirmed Set and an error if the Set, the
+// health check, or the confirmation failed.
+func (c *Client) SetConfirmed(ctx context.Context, ops []SetOperation, rollback time.Duration, check func(context.Context) error, mods ...func(*Req)) (SetRes, error) {
+	if check == nil {
+		err := fmt.Errorf("health check cannot be nil")
+		return SetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Set", ErrValidation, err, time.Now())
+	}
+
+	res, err := c.Set(ctx, ops, append(mods[:len(mods):len(mods)], CommitConfirmed(rollback))...)
+	if err != nil {
+		return res, err
+	}
+	actionMods := withoutCommitRequest(mods)
+
+	if checkErr := check(ctx); checkErr != nil {
+		c.logger.Warn(ctx, "health check failed, canceling commit",
+			"target", c.Target,
+			"commit", res.Commit.ID,
+			"error", checkErr.Error())
+		err := fmt.Errorf("health check failed, commit %s canceled: %w", res.Commit.ID, checkErr)
+		cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.OperationTimeout)
+		defer cancel()
+		if cancelErr := res.Commit.Cancel(cancelCtx, actionMods...); cancelErr != nil {
+			err = errors.Join(err, fmt.Errorf("cancel failed, target rolls back after %s: %w", rollback, cancelErr))
+		}
+		return res, err
+	}
+
+	if err := res.Commit.Confirm(ctx, actionMods...); err != nil {
+		return res, fmt.Errorf("confirm commit %s: %w", res.Commit.ID, err)
+	}
+	return res, nil
+}
+
+// validateCommit validates the commit-confirmed request modifiers
+func validateCommit(req *Req) error {
+	if req.RollbackDuration < 0 {
+		return fmt.Errorf("rollback duration cannot be negative, got: %s", req.RollbackDuration)
+	}
+	if req.CommitID != "" && req.RollbackDuration == 0 {
+		return fmt.Errorf("commit ID requires a rollback duration (CommitConfirmed)")
+	}
+	if req.commitAction != nil && (req.RollbackDuration != 0 || req.CommitID != "") {
+		return fmt.Errorf("commit confirm, cancel, and rollback duration requests cannot be combined with CommitConfirmed or CommitID")
+	}
+	return nil
+}
+
+// newCommitID returns a random commit ID
+//
+// Falls back to a timestamp-based ID if crypto/rand fails.
+func newCommitID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "go-gnmi-" + strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return "go-gnmi-" + hex.EncodeToString(b)
+}
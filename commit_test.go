@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+	"google.golang.org/grpc/codes"
+)
+
+// TestValidateCommit tests validation of the commit-confirmed request modifiers
+func TestValidateCommit(t *testing.T) {
+	tests := []struct {
+		name    string
+		mods    []func(*Req)
+		wantErr string
+	}{
+		{"no commit", nil, ""},
+		{"commit confirmed", []func(*Req){CommitConfirmed(time.Minute)}, ""},
+		{"commit ID", []func(*Req){CommitConfirmed(time.Minute), CommitID("change-1")}, ""},
+		{"negative rollback", []func(*Req){CommitConfirmed(-time.Second)}, "cannot be negative"},
+		{"commit ID without rollback", []func(*Req){CommitID("change-1")}, "requires a rollback duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &Req{}
+			for _, mod := range tt.mods {
+				mod(req)
+			}
+			err := validateCommit(req)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateCommit() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateCommit() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if a, b := newCommitID(), newCommitID(); a == b || !strings.HasPrefix(a, "go-gnmi-") {
+		t.Errorf("newCommitID() = %q, %q, want unique IDs", a, b)
+	}
+}
+
+// TestIntegration_CommitConfirmed tests commit-confirmed Set against the fake server
+func TestIntegration_CommitConfirmed(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+	hostname := func() string {
+		v, _ := srv.JSON("/system/config/hostname")
+		return v
+	}
+	ops := func(name string) []SetOperation {
+		return []SetOperation{Update("/system/config/hostname", `"`+name+`"`)}
+	}
+
+	t.Run("confirm", func(t *testing.T) {
+		res, err := client.Set(ctx, ops("router2"), CommitConfirmed(time.Minute), CommitID("change-1"))
+		if err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if res.Commit == nil || res.Commit.ID != "change-1" || srv.PendingCommit() != "change-1" {
+			t.Fatalf("Commit = %+v, pending %q, want change-1", res.Commit, srv.PendingCommit())
+		}
+		ext := srv.SetRequests()[len(srv.SetRequests())-1].GetExtension()
+		if len(ext) != 1 || ext[0].GetCommit().GetCommit().GetRollbackDuration().AsDuration() != time.Minute {
+			t.Errorf("extension = %v, want commit with 1m rollback", ext)
+		}
+		if err := res.Commit.SetRollbackDuration(ctx, 2*time.Minute); err != nil {
+			t.Fatalf("SetRollbackDuration() error = %v", err)
+		}
+		if err := res.Commit.Confirm(ctx); err != nil {
+			t.Fatalf("Confirm() error = %v", err)
+		}
+		if srv.PendingCommit() != "" || hostname() != `"router2"` {
+			t.Errorf("after confirm: pending %q, hostname %s", srv.PendingCommit(), hostname())
+		}
+		if err := res.Commit.Confirm(ctx); OperationCode(err) != codes.NotFound {
+			t.Errorf("second Confirm() error = %v, want NotFound", err)
+		}
+	})
+
+	t.Run("cancel via ID", func(t *testing.T) {
+		res, err := client.Set(ctx, ops("router3"))
+		if err != nil || res.Commit != nil {
+			t.Fatalf("Set() = %+v, %v, want no commit", res.Commit, err)
+		}
+		res, err = client.Set(ctx, ops("router4"), CommitConfirmed(time.Minute))
+		if err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if err := client.Commit(res.Commit.ID).Cancel(ctx); err != nil {
+			t.Fatalf("Cancel() error = %v", err)
+		}
+		if hostname() != `"router3"` {
+			t.Errorf("hostname after cancel = %s, want \"router3\"", hostname())
+		}
+	})
+
+	t.Run("rollback duration expired", func(t *testing.T) {
+		res, err := client.Set(ctx, ops("router5"), CommitConfirmed(50*time.Millisecond))
+		if err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if !waitFor(t, 2*time.Second, func() bool { return srv.PendingCommit() == "" }) {
+			t.Fatalf("commit not rolled back")
+		}
+		if hostname() != `"router3"` {
+			t.Errorf("hostname after rollback = %s, want \"router3\"", hostname())
+		}
+		if err := res.Commit.Confirm(ctx); err == nil {
+			t.Errorf("Confirm() after rollback expected error")
+		}
+	})
+
+	t.Run("confirm reaches the commit target", func(t *testing.T) {
+		res, err := client.Set(ctx, ops("router6"), CommitConfirmed(time.Minute), Target("router1"), Prefix("/system"))
+		if err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if err := res.Commit.Confirm(ctx); err != nil {
+			t.Fatalf("Confirm() error = %v", err)
+		}
+		reqs := srv.SetRequests()
+		prefix := reqs[len(reqs)-1].GetPrefix()
+		if prefix.GetTarget() != "router1" || pathToString(prefix, nil) != "/system" {
+			t.Errorf("confirm prefix = %v, want target router1 and /system", prefix)
+		}
+	})
+
+	t.Run("commit action with commit request", func(t *testing.T) {
+		err := client.Commit("change-1").Confirm(ctx, CommitConfirmed(time.Minute))
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("Confirm() with CommitConfirmed error = %v, want ErrValidation", err)
+		}
+		if err := client.Commit("change-1").Cancel(ctx, CommitID("change-2")); !errors.Is(err, ErrValidation) {
+			t.Errorf("Cancel() with CommitID error = %v, want ErrValidation", err)
+		}
+	})
+
+	t.Run("invalid rollback duration", func(t *testing.T) {
+		if err := client.Commit("change-1").SetRollbackDuration(ctx, 0); !errors.Is(err, ErrValidation) {
+			t.Errorf("SetRollbackDuration(0) error = %v, want ErrValidation", err)
+		}
+	})
+}
+
+// TestIntegration_CommitConfirmedNoRetry tests that commit-confirmed Sets are not retried
+func TestIntegration_CommitConfirmedNoRetry(t *testing.T) {
+	client, srv := newIntegrationClient(t, MaxRetries(3))
+	ctx := context.Background()
+	ops := []SetOperation{Replace("/system/config/hostname", `"router2"`)}
+
+	t.Run("unavailable", func(t *testing.T) {
+		srv.FailNext(gnmitest.RPCSet, codes.Unavailable, 1)
+		res, err := client.Set(ctx, ops, CommitConfirmed(time.Minute))
+		if OperationCode(err) != codes.Unavailable {
+			t.Fatalf("Set() error = %v, want Unavailable", err)
+		}
+		if n := srv.Calls(gnmitest.RPCSet); n != 1 {
+			t.Errorf("Set calls = %d, want 1", n)
+		}
+		if res.Commit == nil {
+			t.Errorf("Commit = nil, want handle of the failed commit")
+		}
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		srv.SetLatency(gnmitest.RPCSet, time.Second)
+		defer srv.SetLatency(gnmitest.RPCSet, 0)
+		_, err := client.Set(ctx, ops, CommitConfirmed(time.Minute), Timeout(50*time.Millisecond))
+		if OperationCode(err) != codes.DeadlineExceeded {
+			t.Fatalf("Set() error = %v, want DeadlineExceeded", err)
+		}
+		if n := srv.Calls(gnmitest.RPCSet); n != 2 {
+			t.Errorf("Set calls = %d, want 2", n)
+		}
+	})
+
+	t.Run("plain set is retried", func(t *testing.T) {
+		srv.FailNext(gnmitest.RPCSet, codes.Unavailable, 1)
+		if _, err := client.Set(ctx, ops); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	})
+}
+
+// TestIntegration_SetConfirmed tests confirmation after a health check
+func TestIntegration_SetConfirmed(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+	ops := []SetOperation{Update("/system/config/hostname", `"router2"`)}
+
+	t.Run("healthy", func(t *testing.T) {
+		var checked bool
+		_, err := client.SetConfirmed(ctx, ops, time.Minute, func(ctx context.Context) error {
+			checked = srv.PendingCommit() != ""
+			return client.Ping(ctx)
+		})
+		if err != nil {
+			t.Fatalf("SetConfirmed() error = %v", err)
+		}
+		if !checked || srv.PendingCommit() != "" {
+			t.Errorf("check ran during commit = %v, pending %q, want confirmed", checked, srv.PendingCommit())
+		}
+		if v, _ := srv.JSON("/system/config/hostname"); v != `"router2"` {
+			t.Errorf("hostname = %s, want \"router2\"", v)
+		}
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+		checkErr := errors.New("BGP session down")
+		_, err := client.SetConfirmed(ctx, []SetOperation{Update("/system/config/hostname", `"router3"`)}, time.Minute,
+			func(context.Context) error { return checkErr })
+		if !errors.Is(err, checkErr) {
+			t.Fatalf("SetConfirmed() error = %v, want health check error", err)
+		}
+		if v, _ := srv.JSON("/system/config/hostname"); v != `"router2"` || srv.PendingCommit() != "" {
+			t.Errorf("hostname = %s, pending %q, want rolled back", v, srv.PendingCommit())
+		}
+	})
+
+	t.Run("context expired during check", func(t *testing.T) {
+		checkCtx, cancel := context.WithCancel(ctx)
+		_, err := client.SetConfirmed(checkCtx, []SetOperation{Update("/system/config/hostname", `"router4"`)}, time.Minute,
+			func(ctx context.Context) error {
+				cancel()
+				return ctx.Err()
+			})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("SetConfirmed() error = %v, want context canceled", err)
+		}
+		if v, _ := srv.JSON("/system/config/hostname"); v != `"router2"` || srv.PendingCommit() != "" {
+			t.Errorf("hostname = %s, pending %q, want canceled commit", v, srv.PendingCommit())
+		}
+	})
+
+	t.Run("modifiers apply to confirm", func(t *testing.T) {
+		_, err := client.SetConfirmed(ctx, ops, time.Minute, client.Ping, Target("router1"), CommitID("change-7"))
+		if err != nil {
+			t.Fatalf("SetConfirmed() error = %v", err)
+		}
+		reqs := srv.SetRequests()
+		confirm := reqs[len(reqs)-1]
+		if confirm.GetPrefix().GetTarget() != "router1" || confirm.GetExtension()[0].GetCommit().GetConfirm() == nil {
+			t.Errorf("confirm request = %v, want confirm for target router1", confirm)
+		}
+	})
+
+	t.Run("nil check", func(t *testing.T) {
+		if _, err := client.SetConfirmed(ctx, ops, time.Minute, nil); !errors.Is(err, ErrValidation) {
+			t.Errorf("SetConfirmed() error = %v, want ErrValidation", err)
+		}
+	})
+}
@@ -0,0 +1,362 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// CredentialProvider supplies the username and password of a client
+//
+// The provider is queried whenever the client connects or reconnects, so
+// credentials stored in Vault, files, or environment variables can rotate
+// without recreating the client. If the target rejects a request with
+// UNAUTHENTICATED, the provider is queried again and the request is sent once
+// more on a new connection (see RefreshCredentialsOnUnauthenticated).
+//
+// Implementations must be safe for concurrent use. Calls block connection
+// establishment, so they should honor ctx.
+type CredentialProvider interface {
+	// Credentials returns the current username and password
+	Credentials(ctx context.Context) (username, password string, err error)
+}
+
+// CredentialProviderFunc adapts a function to the CredentialProvider interface
+//
+// Example:
+//
+//	provider := gnmi.CredentialProviderFunc(func(ctx context.Context) (string, string, error) {
+//	    secret, err := vault.KVv2("secret").Get(ctx, "network/gnmi")
+//	    if err != nil {
+//	        return "", "", err
+//	    }
+//	    return secret.Data["username"].(string), secret.Data["password"].(string), nil
+//	})
+type CredentialProviderFunc func(ctx context.Context) (username, password string, err error)
+
+// Credentials implements CredentialProvider
+func (f CredentialProviderFunc) Credentials(ctx context.Context) (string, string, error) {
+	return f(ctx)
+}
+
+// EnvCredentials returns a CredentialProvider reading the username and
+// password from environment variables
+//
+// Returns an error from Credentials if a variable is not set.
+func EnvCredentials(usernameVar, passwordVar string) CredentialProvider {
+	return CredentialProviderFunc(func(context.Context) (string, string, error) {
+		username, ok := os.LookupEnv(usernameVar)
+		if !ok {
+			return "", "", fmt.Errorf("environment variable %s not set", usernameVar)
+		}
+		password, ok := os.LookupEnv(passwordVar)
+		if !ok {
+			return "", "", fmt.Errorf("environment variable %s not set", passwordVar)
+		}
+		return username, password, nil
+	})
+}
+
+// FileCredentials returns a CredentialProvider reading the username and
+// password from files, e.g. secrets mounted by Kubernetes or a Vault agent
+//
+// The files are read on every query, so replacing their content rotates the
+// credentials. Leading and trailing whitespace is removed.
+func FileCredentials(usernameFile, passwordFile string) CredentialProvider {
+	return CredentialProviderFunc(func(context.Context) (string, string, error) {
+		username, err := os.ReadFile(usernameFile)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read username file: %w", err)
+		}
+		password, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read password file: %w", err)
+		}
+		return strings.TrimSpace(string(username)), strings.TrimSpace(string(password)), nil
+	})
+}
+
+// WithCredentialProvider sets a CredentialProvider queried for the username
+// and password at connect and reconnect time
+//
+// The provider takes precedence over Username and Password.
+//
+// Example:
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.WithCredentialProvider(gnmi.FileCredentials(
+//	        "/run/secrets/gnmi-username",
+//	        "/run/secrets/gnmi-password",
+//	    )),
+//	)
+func WithCredentialProvider(provider CredentialProvider) func(*Client) {
+	return func(c *Client) {
+		c.credentialProvider = provider
+	}
+}
+
+// RefreshCredentialsOnUnauthenticated enables or disables querying the
+// CredentialProvider again after UNAUTHENTICATED errors (default: true)
+//
+// When enabled, a Get, Set, or Subscribe rejected with UNAUTHENTICATED
+// reconnects with the current credentials of the provider and is sent once
+// more. Disable it for devices that lock accounts after failed logins.
+func RefreshCredentialsOnUnauthenticated(enabled bool) func(*Client) {
+	return func(c *Client) {
+		c.refreshOnUnauthenticated = enabled
+	}
+}
+
+// Credentials sets per-RPC credentials attached to every request
+//
+// Use it for authentication schemes other than username and password, e.g.
+// credentials from google.golang.org/grpc/credentials/oauth or a custom
+// implementation adding device-specific metadata. Credentials are used in
+// addition to Username and Password if both are configured.
+//
+// Credentials that require transport security (RequireTransportSecurity)
+// cannot be used with TLS(false); NewClient returns an error.
+//
+// Example:
+//
+//	creds, err := oauth.NewServiceAccountFromFile("sa.json", scope)
+//	client, err := gnmi.NewClient("gateway.example.com:9339", gnmi.Credentials(creds))
+func Credentials(creds credentials.PerRPCCredentials) func(*Client) {
+	return func(c *Client) {
+		c.credentials = creds
+	}
+}
+
+// BearerToken authenticates every request with a static bearer token
+//
+// The token is sent in the "authorization" metadata as "Bearer <token>".
+// Use TokenSource or TokenFunc for tokens that expire. Tokens require TLS;
+// NewClient returns an error with TLS(false).
+//
+// Example:
+//
+//	client, err := gnmi.NewClient("gateway.example.com:9339",
+//	    gnmi.BearerToken(os.Getenv("GNMI_TOKEN")),
+//	)
+func BearerToken(token string) func(*Client) {
+	return TokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+}
+
+// TokenSource authenticates every request with tokens from an OAuth2 token source
+//
+// Tokens are cached and a new token is requested shortly before the cached
+// one expires. If the target rejects a request with UNAUTHENTICATED, the
+// cached token is discarded and the request is sent once more with a new
+// token. The token type defaults to "Bearer".
+//
+// Example:
+//
+//	cfg := clientcredentials.Config{
+//	    ClientID:     "gnmi-client",
+//	    ClientSecret: secret,
+//	    TokenURL:     "https://sso.example.com/oauth2/token",
+//	}
+//	client, err := gnmi.NewClient("gateway.example.com:9339",
+//	    gnmi.TokenSource(cfg.TokenSource(context.Background())),
+//	)
+func TokenSource(ts oauth2.TokenSource) func(*Client) {
+	return TokenFunc(func(context.Context) (*oauth2.Token, error) {
+		return ts.Token()
+	})
+}
+
+// TokenFunc authenticates every request with tokens returned by fn
+//
+// fn is called with the context of the request when no valid token is
+// cached. Tokens are cached until shortly before their Expiry (forever for a
+// zero Expiry) and refreshed like with TokenSource. fn must be safe for
+// concurrent use; calls are serialized by the client.
+//
+// Example:
+//
+//	client, err := gnmi.NewClient("gateway.example.com:9339",
+//	    gnmi.TokenFunc(func(ctx context.Context) (*oauth2.Token, error) {
+//	        jwt, exp, err := vault.IssueJWT(ctx, "gnmi")
+//	        if err != nil {
+//	            return nil, err
+//	        }
+//	        return &oauth2.Token{AccessToken: jwt, Expiry: exp}, nil
+//	    }),
+//	)
+func TokenFunc(fn func(ctx context.Context) (*oauth2.Token, error)) func(*Client) {
+	return func(c *Client) {
+		c.credentials = &tokenCredentials{fetch: fn}
+	}
+}
+
+// tokenCredentials implements credentials.PerRPCCredentials with a cached,
+// refreshable token
+type tokenCredentials struct {
+	fetch func(ctx context.Context) (*oauth2.Token, error)
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+var _ credentials.PerRPCCredentials = (*tokenCredentials)(nil)
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+//
+// Returns the "authorization" metadata with the cached token, fetching a new
+// token if none is cached or the cached token expired.
+func (t *tokenCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.token.Valid() {
+		token, err := t.fetch(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "failed to obtain token: %v", err)
+		}
+		if token == nil || token.AccessToken == "" {
+			return nil, status.Error(codes.Unauthenticated, "failed to obtain token: empty token")
+		}
+		t.token = token
+	}
+
+	return map[string]string{"authorization": t.token.Type() + " " + t.token.AccessToken}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials
+//
+// Tokens are never sent in clear text; NewClient rejects token credentials
+// with TLS(false).
+func (t *tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// invalidate discards the cached token so the next request fetches a new one
+func (t *tokenCredentials) invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.token = nil
+}
+
+// loadCredentials queries the credential provider and stores the result as
+// the username and password of the client
+//
+// Does nothing if no provider is configured.
+//
+// PRECONDITION: Caller must hold c.mu.Lock() (write lock).
+func (c *Client) loadCredentials(ctx context.Context) error {
+	if c.credentialProvider == nil {
+		return nil
+	}
+
+	username, password, err := c.credentialProvider.Credentials(ctx)
+	if err != nil {
+		c.logger.Error(ctx, "gNMI credential provider failed",
+			"target", c.Target,
+			"error", err.Error())
+		return fmt.Errorf("failed to load credentials: %w", err)
+	}
+	c.username = username
+	c.password = password
+
+	return nil
+}
+
+// shouldRefreshCredentials reports whether err rejected the credentials and
+// the credential provider should be queried again
+func (c *Client) shouldRefreshCredentials(err error) bool {
+	return c.credentialProvider != nil && c.refreshOnUnauthenticated && status.Code(err) == codes.Unauthenticated
+}
+
+// refreshCredentials reconnects with credentials queried again from the
+// credential provider after the target rejected the current ones
+//
+// PRECONDITION: Caller must hold c.mu.Lock() (write lock).
+//
+// Returns ErrClosed if the client was closed, or the reconnection error.
+func (c *Client) refreshCredentials(ctx context.Context) error {
+	if c.target == nil {
+		return ErrClosed
+	}
+
+	c.logger.Warn(ctx, "gNMI credentials rejected, reloading",
+		"target", c.Target)
+
+	return c.reconnect(ctx)
+}
+
+// validateCredentials checks that the per-RPC credentials can be used with
+// the transport configuration
+func (c *Client) validateCredentials() error {
+	if c.credentials != nil && !c.UseTLS && c.credentials.RequireTransportSecurity() {
+		return fmt.Errorf("per-RPC credentials require TLS, but TLS is disabled")
+	}
+	return nil
+}
+
+// credentialDialOptions returns the gRPC dial options attaching the per-RPC
+// credentials to requests (nil if none are configured)
+//
+// Token credentials additionally discard the cached token when the target
+// responds with UNAUTHENTICATED, and unary RPCs are sent once more with a
+// new token.
+func (c *Client) credentialDialOptions() []grpc.DialOption {
+	if c.credentials == nil {
+		return nil
+	}
+
+	opts := []grpc.DialOption{grpc.WithPerRPCCredentials(c.credentials)}
+	tc, ok := c.credentials.(*tokenCredentials)
+	if !ok {
+		return opts
+	}
+
+	unary := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		if status.Code(err) == codes.Unauthenticated && ctx.Err() == nil {
+			tc.invalidate()
+			c.logger.Debug(ctx, "Token rejected, retrying with new token",
+				"target", c.Target,
+				"method", method)
+			err = invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		return err
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		s, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			if status.Code(err) == codes.Unauthenticated {
+				tc.invalidate()
+			}
+			return nil, err
+		}
+		return &tokenStream{ClientStream: s, credentials: tc}, nil
+	}
+
+	return append(opts, grpc.WithChainUnaryInterceptor(unary), grpc.WithChainStreamInterceptor(stream))
+}
+
+// tokenStream discards the cached token when a stream is rejected with UNAUTHENTICATED
+type tokenStream struct {
+	grpc.ClientStream
+	credentials *tokenCredentials
+}
+
+// RecvMsg implements grpc.ClientStream
+func (s *tokenStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if status.Code(err) == codes.Unauthenticated {
+		s.credentials.invalidate()
+	}
+	return err
+}
@@ -0,0 +1,398 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rotatingToken is a token callback returning the current token and counting calls
+type rotatingToken struct {
+	mu      sync.Mutex
+	token   string
+	expiry  time.Time
+	fetches int
+}
+
+func (r *rotatingToken) fetch(context.Context) (*oauth2.Token, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fetches++
+	return &oauth2.Token{AccessToken: r.token, Expiry: r.expiry}, nil
+}
+
+func (r *rotatingToken) set(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.token = token
+}
+
+func (r *rotatingToken) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fetches
+}
+
+// rotatingPassword is a CredentialProvider returning the current password and counting calls
+type rotatingPassword struct {
+	mu       sync.Mutex
+	password string
+	err      error
+	calls    int
+}
+
+func (r *rotatingPassword) Credentials(context.Context) (string, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	return "admin", r.password, r.err
+}
+
+func (r *rotatingPassword) set(password string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.password = password
+	r.err = err
+}
+
+func (r *rotatingPassword) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+// staticCredentials is a custom PerRPCCredentials implementation
+type staticCredentials struct {
+	md         map[string]string
+	requireTLS bool
+}
+
+func (s staticCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return s.md, nil
+}
+
+func (s staticCredentials) RequireTransportSecurity() bool {
+	return s.requireTLS
+}
+
+// newTokenClient starts a fake gNMI server requiring token and returns a client using opts
+func newTokenClient(t *testing.T, token string, opts ...func(*Client)) (*Client, *gnmitest.Server) {
+	t.Helper()
+
+	pki := newTestPKI(t)
+	srv, err := gnmitest.NewServer(gnmitest.Token(token), gnmitest.TLSConfig(&tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		MinVersion:   tls.VersionTLS12,
+	}))
+	if err != nil {
+		t.Fatalf("gnmitest.NewServer() error = %v", err)
+	}
+	t.Cleanup(srv.Close)
+	if err := srv.Load("/system/config", `{"hostname": "router1"}`); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	client, err := NewClient(srv.Addr(), append([]func(*Client){
+		TLSCAPEM(pki.caPEM),
+		TLSServerName("gnmi.test"),
+		OperationTimeout(2 * time.Second),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client, srv
+}
+
+// TestTokenCredentials_GetRequestMetadata tests token caching and expiry
+func TestTokenCredentials_GetRequestMetadata(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cached until expiry", func(t *testing.T) {
+		src := &rotatingToken{token: "t1", expiry: time.Now().Add(time.Hour)}
+		creds := &tokenCredentials{fetch: src.fetch}
+
+		for i := 0; i < 3; i++ {
+			md, err := creds.GetRequestMetadata(ctx)
+			if err != nil {
+				t.Fatalf("GetRequestMetadata() error = %v", err)
+			}
+			if md["authorization"] != "Bearer t1" {
+				t.Errorf("authorization = %q, want %q", md["authorization"], "Bearer t1")
+			}
+		}
+		if n := src.count(); n != 1 {
+			t.Errorf("fetches = %d, want 1", n)
+		}
+
+		creds.invalidate()
+		if _, err := creds.GetRequestMetadata(ctx); err != nil {
+			t.Fatalf("GetRequestMetadata() error = %v", err)
+		}
+		if n := src.count(); n != 2 {
+			t.Errorf("fetches after invalidate = %d, want 2", n)
+		}
+	})
+
+	t.Run("expired token refreshed", func(t *testing.T) {
+		// Tokens expiring within the expiry margin are refreshed on every request
+		src := &rotatingToken{token: "t1", expiry: time.Now().Add(time.Second)}
+		creds := &tokenCredentials{fetch: src.fetch}
+
+		for i := 0; i < 2; i++ {
+			if _, err := creds.GetRequestMetadata(ctx); err != nil {
+				t.Fatalf("GetRequestMetadata() error = %v", err)
+			}
+		}
+		if n := src.count(); n != 2 {
+			t.Errorf("fetches = %d, want 2", n)
+		}
+	})
+
+	t.Run("token type", func(t *testing.T) {
+		creds := &tokenCredentials{fetch: func(context.Context) (*oauth2.Token, error) {
+			return &oauth2.Token{AccessToken: "abc", TokenType: "mac"}, nil
+		}}
+		md, err := creds.GetRequestMetadata(ctx)
+		if err != nil {
+			t.Fatalf("GetRequestMetadata() error = %v", err)
+		}
+		if md["authorization"] != "MAC abc" {
+			t.Errorf("authorization = %q, want %q", md["authorization"], "MAC abc")
+		}
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		creds := &tokenCredentials{fetch: func(context.Context) (*oauth2.Token, error) {
+			return nil, errors.New("idp unavailable")
+		}}
+		if _, err := creds.GetRequestMetadata(ctx); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("GetRequestMetadata() error = %v, want Unauthenticated", err)
+		}
+
+		empty := &tokenCredentials{fetch: func(context.Context) (*oauth2.Token, error) {
+			return &oauth2.Token{}, nil
+		}}
+		if _, err := empty.GetRequestMetadata(ctx); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("GetRequestMetadata() with empty token error = %v, want Unauthenticated", err)
+		}
+	})
+}
+
+// TestCredentials_Validation tests credentials that cannot be used without TLS
+func TestCredentials_Validation(t *testing.T) {
+	_, err := NewClient("192.168.1.1", TLS(false), Credentials(staticCredentials{requireTLS: true}))
+	if err == nil {
+		t.Errorf("NewClient() expected error for credentials requiring TLS")
+	}
+
+	if _, err := NewClient("192.168.1.1", TLS(false), BearerToken("secret-token")); err == nil {
+		t.Errorf("NewClient() expected error for token without TLS")
+	}
+
+	client, err := NewClient("192.168.1.1", Credentials(staticCredentials{requireTLS: true}))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if !client.HasCredentials() {
+		t.Errorf("HasCredentials() = false, want true")
+	}
+}
+
+// TestIntegration_TokenAuthentication tests token authentication against the fake server
+func TestIntegration_TokenAuthentication(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("bearer token", func(t *testing.T) {
+		client, _ := newTokenClient(t, "secret-token", BearerToken("secret-token"))
+		if _, err := client.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		client, _ := newTokenClient(t, "secret-token", BearerToken("wrong"), MaxRetries(0))
+		_, err := client.Get(ctx, []string{"/system/config/hostname"})
+		if OperationCode(err) != codes.Unauthenticated {
+			t.Errorf("Get() error = %v, want Unauthenticated", err)
+		}
+	})
+
+	t.Run("custom credentials", func(t *testing.T) {
+		creds := staticCredentials{md: map[string]string{"authorization": "Bearer secret-token"}}
+		client, _ := newTokenClient(t, "secret-token", Credentials(creds))
+		if err := client.Ping(ctx); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+	})
+
+	t.Run("rotated token", func(t *testing.T) {
+		src := &rotatingToken{token: "t1"}
+		client, srv := newTokenClient(t, "t1", TokenFunc(src.fetch))
+
+		if _, err := client.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+
+		// The target rejects the cached token; the client fetches a new one
+		srv.SetToken("t2")
+		src.set("t2")
+		if _, err := client.Set(ctx, []SetOperation{Update("/system/config", `{"hostname": "r2"}`)}); err != nil {
+			t.Fatalf("Set() after rotation error = %v", err)
+		}
+		if n := src.count(); n != 2 {
+			t.Errorf("fetches = %d, want 2", n)
+		}
+
+		stream, err := client.Subscribe(testContext(t), []Subscription{OnChange("/system/config/hostname")})
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		defer stream.Close()
+		waitForSync(t, stream)
+	})
+}
+
+// TestCredentialProviders tests the environment and file credential providers
+func TestCredentialProviders(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("GNMI_TEST_USERNAME", "admin")
+		t.Setenv("GNMI_TEST_PASSWORD", "secret")
+
+		username, password, err := EnvCredentials("GNMI_TEST_USERNAME", "GNMI_TEST_PASSWORD").Credentials(ctx)
+		if err != nil || username != "admin" || password != "secret" {
+			t.Errorf("Credentials() = %q, %q, %v, want admin, secret, nil", username, password, err)
+		}
+		if _, _, err := EnvCredentials("GNMI_TEST_USERNAME", "GNMI_TEST_UNSET").Credentials(ctx); err == nil {
+			t.Errorf("Credentials() expected error for unset variable")
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		dir := t.TempDir()
+		usernameFile := filepath.Join(dir, "username")
+		passwordFile := filepath.Join(dir, "password")
+		if err := os.WriteFile(usernameFile, []byte("admin\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(passwordFile, []byte("secret\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		provider := FileCredentials(usernameFile, passwordFile)
+		username, password, err := provider.Credentials(ctx)
+		if err != nil || username != "admin" || password != "secret" {
+			t.Errorf("Credentials() = %q, %q, %v, want admin, secret, nil", username, password, err)
+		}
+
+		// Replacing the file rotates the password
+		if err := os.WriteFile(passwordFile, []byte("rotated"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, password, _ := provider.Credentials(ctx); password != "rotated" {
+			t.Errorf("password after rotation = %q, want rotated", password)
+		}
+
+		if _, _, err := FileCredentials(usernameFile, filepath.Join(dir, "missing")).Credentials(ctx); err == nil {
+			t.Errorf("Credentials() expected error for missing file")
+		}
+	})
+}
+
+// TestIntegration_CredentialProvider tests credential rotation against the fake server
+func TestIntegration_CredentialProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rotation", func(t *testing.T) {
+		provider := &rotatingPassword{password: "secret"}
+		client, srv := newIntegrationClient(t, Password(""), WithCredentialProvider(provider))
+
+		if _, err := client.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if n := provider.count(); n != 1 {
+			t.Errorf("provider calls after connect = %d, want 1", n)
+		}
+
+		// The rotated password is picked up after the target rejects the old one
+		srv.SetCredentials("admin", "rotated")
+		provider.set("rotated", nil)
+		if _, err := client.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+			t.Fatalf("Get() after rotation error = %v", err)
+		}
+		if n := provider.count(); n != 2 {
+			t.Errorf("provider calls after rotation = %d, want 2", n)
+		}
+
+		srv.SetCredentials("admin", "rotated2")
+		provider.set("rotated2", nil)
+		if _, err := client.Set(ctx, []SetOperation{Update("/system/config", `{"hostname": "r2"}`)}); err != nil {
+			t.Fatalf("Set() after rotation error = %v", err)
+		}
+
+		srv.SetCredentials("admin", "rotated3")
+		provider.set("rotated3", nil)
+		stream, err := client.Subscribe(testContext(t), []Subscription{OnChange("/system/config/hostname")})
+		if err != nil {
+			t.Fatalf("Subscribe() after rotation error = %v", err)
+		}
+		defer stream.Close()
+		waitForSync(t, stream)
+
+		// Reconnects query the provider again
+		if err := client.Disconnect(); err != nil {
+			t.Fatalf("Disconnect() error = %v", err)
+		}
+		calls := provider.count()
+		if err := client.Ping(ctx); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+		if n := provider.count(); n != calls+1 {
+			t.Errorf("provider calls after reconnect = %d, want %d", n, calls+1)
+		}
+	})
+
+	t.Run("refresh disabled", func(t *testing.T) {
+		provider := &rotatingPassword{password: "secret"}
+		client, srv := newIntegrationClient(t, WithCredentialProvider(provider), RefreshCredentialsOnUnauthenticated(false))
+
+		if err := client.Ping(ctx); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+		srv.SetCredentials("admin", "rotated")
+		provider.set("rotated", nil)
+		_, err := client.Get(ctx, []string{"/system/config/hostname"})
+		if OperationCode(err) != codes.Unauthenticated {
+			t.Errorf("Get() error = %v, want Unauthenticated", err)
+		}
+		if n := provider.count(); n != 1 {
+			t.Errorf("provider calls = %d, want 1", n)
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		provider := &rotatingPassword{err: errors.New("vault sealed")}
+		client, _ := newIntegrationClient(t, WithCredentialProvider(provider))
+
+		err := client.Ping(ctx)
+		if !errors.Is(err, ErrNotConnected) {
+			t.Errorf("Ping() error = %v, want ErrNotConnected", err)
+		}
+	})
+}
@@ -43,9 +43,10 @@
 //	    log.Fatal(err)
 //	}
 //
-//	// Parse response using gjson
-//	value := res.GetValue("notification.0.update.0.val").String()
-//	fmt.Println("Value:", value)
+//	// Decoded values keyed by path
+//	for path, value := range res.Values() {
+//	    fmt.Printf("%s = %v\n", path, value)
+//	}
 //
 // # JSON Manipulation
 //
@@ -90,6 +91,7 @@
 //   - Get: Retrieve configuration and state data
 //   - Set: Update, replace, or delete configuration
 //   - Capabilities: Discover supported encodings and gNMI version
+//   - Subscribe: Stream telemetry in STREAM, ONCE, or POLL mode
 //
 // # References
 //
@@ -4,12 +4,45 @@
 package gnmi
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sentinel errors matched by GnmiError via errors.Is
+var (
+	// ErrNotConnected indicates the client has no connection and none could be established
+	ErrNotConnected = errors.New("client not connected")
+
+	// ErrValidation indicates the request was rejected before it was sent
+	ErrValidation = errors.New("invalid request")
+
+	// ErrClosed indicates the client or subscription was closed
+	ErrClosed = errors.New("client closed")
 )
 
 // GnmiError represents a structured gNMI error with operation context
+//
+// All Client operations return a *GnmiError on failure. It matches the
+// sentinel errors ErrNotConnected, ErrValidation, and ErrClosed via errors.Is
+// and unwraps to the underlying error (gRPC status error, context error, or
+// validation error).
+//
+// Example:
+//
+//	res, err := client.Get(ctx, paths)
+//	var gnmiErr *gnmi.GnmiError
+//	if errors.As(err, &gnmiErr) {
+//	    log.Printf("%s failed with %s after %d retries (%s)",
+//	        gnmiErr.Operation, gnmiErr.Code, gnmiErr.Retries, gnmiErr.Elapsed)
+//	}
+//	if errors.Is(err, gnmi.ErrValidation) {
+//	    // Fix the request, retrying will not help
+//	}
 type GnmiError struct {
 	// Operation name that failed
 	Operation string
@@ -28,6 +61,48 @@ type GnmiError struct {
 
 	// IsTransient indicates if the error is transient and was retried
 	IsTransient bool
+
+	// Code is the gRPC status code of the failure
+	//
+	// Context errors map to codes.Canceled and codes.DeadlineExceeded;
+	// failures that did not come from gRPC (e.g., validation) have codes.OK.
+	Code codes.Code
+
+	// Elapsed is the time spent on the operation, including retries
+	Elapsed time.Duration
+
+	// Err is the underlying error
+	Err error
+
+	// kind is the sentinel error matched by Is (nil if none)
+	kind error
+}
+
+// newGnmiError creates a GnmiError for a failed operation
+//
+// kind is the sentinel error the result matches (nil if none) and err the
+// underlying error, whose message becomes the error message. The gRPC status
+// code is taken from err. The elapsed time is measured from start.
+func newGnmiError(operation string, kind, err error, start time.Time) *GnmiError {
+	gnmiErr := &GnmiError{
+		Operation: operation,
+		Message:   err.Error(),
+		Elapsed:   time.Since(start),
+		Err:       err,
+		kind:      kind,
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	switch {
+	case errors.As(err, &grpcErr):
+		gnmiErr.Code = grpcErr.GRPCStatus().Code()
+	case errors.Is(err, context.Canceled):
+		gnmiErr.Code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		gnmiErr.Code = codes.DeadlineExceeded
+	}
+
+	return gnmiErr
 }
 
 // Error implements the error interface
@@ -38,6 +113,18 @@ func (e *GnmiError) Error() string {
 	return fmt.Sprintf("gnmi: %s failed: %s", e.Operation, e.Message)
 }
 
+// Unwrap returns the underlying error
+func (e *GnmiError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches the sentinel error target
+//
+// Sentinels wrapped by the underlying error are matched through Unwrap.
+func (e *GnmiError) Is(target error) bool {
+	return e.kind != nil && e.kind == target
+}
+
 // DetailedError returns the full error message including internal details
 //
 // This should only be used in secure logging contexts where sensitive information
@@ -96,6 +183,10 @@ type TransientError struct {
 // transient, they should be detected and handled explicitly rather than retrying all
 // Internal errors.
 //
+// A Set may be partially applied when it fails with Aborted. Use a RetryPolicy
+// such as IdempotentOnly to avoid repeating non-idempotent Sets, or set Codes on
+// ExponentialBackoff or DecorrelatedJitter to retry other codes per client.
+//
 // Based on gRPC status codes from google.golang.org/grpc/codes
 var TransientErrors = []TransientError{
 	// Service temporarily unavailable
@@ -4,8 +4,11 @@
 package gnmi
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -482,3 +485,115 @@ func BenchmarkCheckTransientErrorModels(b *testing.B) {
 		})
 	}
 }
+
+// TestNewGnmiError tests status code extraction and unwrapping of GnmiError
+func TestNewGnmiError(t *testing.T) {
+	grpcErr := status.Error(codes.NotFound, "path not found")
+
+	tests := []struct {
+		name     string
+		kind     error
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "gRPC error", err: fmt.Errorf("request failed: %w", grpcErr), wantCode: codes.NotFound},
+		{name: "canceled", err: context.Canceled, wantCode: codes.Canceled},
+		{name: "deadline", err: fmt.Errorf("backoff: %w", context.DeadlineExceeded), wantCode: codes.DeadlineExceeded},
+		{name: "validation", kind: ErrValidation, err: errors.New("paths cannot be empty"), wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now().Add(-time.Second)
+			var err error = newGnmiError("Get", tt.kind, tt.err, start)
+
+			var gnmiErr *GnmiError
+			if !errors.As(err, &gnmiErr) {
+				t.Fatalf("errors.As() failed for %T", err)
+			}
+			if gnmiErr.Code != tt.wantCode {
+				t.Errorf("Code = %v, want %v", gnmiErr.Code, tt.wantCode)
+			}
+			if gnmiErr.Elapsed < time.Second {
+				t.Errorf("Elapsed = %v, want at least 1s", gnmiErr.Elapsed)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("errors.Is(err, cause) = false")
+			}
+			if got := errors.Is(err, ErrValidation); got != (tt.kind == ErrValidation) {
+				t.Errorf("errors.Is(err, ErrValidation) = %v", got)
+			}
+			if errors.Is(err, ErrNotConnected) {
+				t.Errorf("errors.Is(err, ErrNotConnected) = true")
+			}
+		})
+	}
+}
+
+// TestOperationErrors_Sentinels tests that operations return GnmiErrors matching the sentinel errors
+func TestOperationErrors_Sentinels(t *testing.T) {
+	ctx := context.Background()
+	notConnected := &Client{logger: &NoOpLogger{}}
+	closed := &Client{logger: &NoOpLogger{}, closed: true}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantOp   string
+		sentinel error
+	}{
+		{
+			name:     "get validation",
+			call:     func() error { _, err := notConnected.Get(ctx, nil); return err },
+			wantOp:   "Get",
+			sentinel: ErrValidation,
+		},
+		{
+			name:     "set validation",
+			call:     func() error { _, err := notConnected.Set(ctx, nil); return err },
+			wantOp:   "Set",
+			sentinel: ErrValidation,
+		},
+		{
+			name:     "subscribe validation",
+			call:     func() error { _, err := notConnected.Subscribe(ctx, nil); return err },
+			wantOp:   "Subscribe",
+			sentinel: ErrValidation,
+		},
+		{
+			name:     "get not connected",
+			call:     func() error { _, err := notConnected.Get(ctx, []string{"/system"}); return err },
+			wantOp:   "Get",
+			sentinel: ErrNotConnected,
+		},
+		{
+			name:     "capabilities not connected",
+			call:     func() error { _, err := notConnected.Capabilities(ctx); return err },
+			wantOp:   "Capabilities",
+			sentinel: ErrNotConnected,
+		},
+		{
+			name:     "set after close",
+			call:     func() error { _, err := closed.Set(ctx, []SetOperation{Delete("/system")}); return err },
+			wantOp:   "Set",
+			sentinel: ErrClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+
+			var gnmiErr *GnmiError
+			if !errors.As(err, &gnmiErr) {
+				t.Fatalf("error = %v (%T), want *GnmiError", err, err)
+			}
+			if gnmiErr.Operation != tt.wantOp {
+				t.Errorf("Operation = %q, want %q", gnmiErr.Operation, tt.wantOp)
+			}
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.sentinel)
+			}
+		})
+	}
+}
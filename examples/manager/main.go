@@ -0,0 +1,92 @@
+//nolint:errcheck,gosec // Example code prioritizes readability over error handling
+
+// Package main demonstrates managing many targets with go-gnmi.
+//
+// This example shows:
+//   - Registering targets with a Manager and shared client options
+//   - Fan-out Get with bounded parallelism
+//   - Fan-out Set with per-target error reporting
+//
+// Usage:
+//
+//	export GNMI_TARGETS=router1=192.168.1.1:57400,router2=192.168.1.2:57400
+//	export GNMI_USERNAME=admin
+//	export GNMI_PASSWORD=secret
+//	go run main.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/netascode/go-gnmi"
+)
+
+func main() {
+	// Load inventory and credentials from environment
+	targets := getEnv("GNMI_TARGETS", "router1=192.168.1.1:57400,router2=192.168.1.2:57400")
+	username := getEnv("GNMI_USERNAME", "admin")
+	password := getEnv("GNMI_PASSWORD", "secret")
+
+	// Options shared by all clients
+	mgr := gnmi.NewManager(
+		gnmi.MaxParallel(20),
+		gnmi.ClientOptions(
+			gnmi.Username(username),
+			gnmi.Password(password),
+			gnmi.TLS(true),
+			gnmi.VerifyCertificate(false), // WARNING: Disables TLS verification - TESTING ONLY
+			gnmi.OperationTimeout(30*time.Second),
+		),
+	)
+	defer mgr.Close()
+
+	for _, entry := range strings.Split(targets, ",") {
+		name, addr, ok := strings.Cut(entry, "=")
+		if !ok {
+			log.Fatalf("invalid target %q (want name=address)", entry)
+		}
+		if err := mgr.Add(name, addr); err != nil {
+			log.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	fmt.Printf("Managing %d targets: %v\n", len(mgr.Names()), mgr.Names())
+
+	ctx := context.Background()
+
+	// Example 1: Get from all targets
+	fmt.Println("\n=== Fan-out Get ===")
+	getReport := mgr.GetAll(ctx, []string{"/system/config/hostname"})
+	for _, name := range mgr.Names() {
+		if res, ok := getReport.Results[name]; ok {
+			fmt.Printf("  %s: %v\n", name, res.Values())
+		} else {
+			fmt.Printf("  %s: failed: %v\n", name, getReport.Errors[name])
+		}
+	}
+
+	// Example 2: Set on all targets
+	fmt.Println("\n=== Fan-out Set ===")
+	setReport := mgr.SetAll(ctx, []gnmi.SetOperation{
+		gnmi.Update("/system/config", `{"login-banner": "Authorized access only"}`),
+	})
+	fmt.Printf("  %d succeeded, %d failed\n", len(setReport.Results), len(setReport.Errors))
+	for _, name := range setReport.Failed() {
+		fmt.Printf("  %s: %v\n", name, setReport.Errors[name])
+	}
+
+	fmt.Println("\n=== Examples Complete ===")
+}
+
+// getEnv retrieves environment variable or returns default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
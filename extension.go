@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/protobuf/proto"
+)
+
+// Extensions is a list of gNMI extensions sent with a request or returned in a response
+//
+// The accessors return the first extension of each well-known type, or nil
+// if the list does not contain one.
+type Extensions []*gnmi_ext.Extension
+
+// History returns the History extension
+func (e Extensions) History() *gnmi_ext.History {
+	for _, ext := range e {
+		if h := ext.GetHistory(); h != nil {
+			return h
+		}
+	}
+	return nil
+}
+
+// Depth returns the Depth extension
+func (e Extensions) Depth() *gnmi_ext.Depth {
+	for _, ext := range e {
+		if d := ext.GetDepth(); d != nil {
+			return d
+		}
+	}
+	return nil
+}
+
+// MasterArbitration returns the MasterArbitration extension
+func (e Extensions) MasterArbitration() *gnmi_ext.MasterArbitration {
+	for _, ext := range e {
+		if ma := ext.GetMasterArbitration(); ma != nil {
+			return ma
+		}
+	}
+	return nil
+}
+
+// Commit returns the Commit extension
+func (e Extensions) Commit() *gnmi_ext.Commit {
+	for _, ext := range e {
+		if c := ext.GetCommit(); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// Registered returns the payload of the registered extension with the given ID
+//
+// Returns false if the list does not contain the extension.
+func (e Extensions) Registered(id gnmi_ext.ExtensionID) ([]byte, bool) {
+	for _, ext := range e {
+		if r := ext.GetRegisteredExt(); r != nil && r.GetId() == id {
+			return r.GetMsg(), true
+		}
+	}
+	return nil, false
+}
+
+// UnmarshalRegistered decodes the payload of the registered extension with
+// the given ID into msg
+//
+// Example:
+//
+//	var info vendorpb.CommitInfo
+//	ok, err := res.Extensions.UnmarshalRegistered(vendorExtensionID, &info)
+//
+// Returns false if the list does not contain the extension, and an error if
+// the payload cannot be decoded.
+func (e Extensions) UnmarshalRegistered(id gnmi_ext.ExtensionID, msg proto.Message) (bool, error) {
+	data, ok := e.Registered(id)
+	if !ok {
+		return false, nil
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return true, fmt.Errorf("failed to decode registered extension %d: %w", id, err)
+	}
+	return true, nil
+}
+
+// Extension returns a request modifier that attaches gNMI extensions to Get,
+// Set, and Subscribe requests.
+//
+// Any extension can be attached, e.g. one built by a vendor package. The
+// modifiers HistorySnapshot, HistoryRange, Depth, MasterArbitration, and
+// RegisteredExtension build the common ones.
+//
+// Example:
+//
+//	res, err := client.Get(ctx, paths, gnmi.Extension(&gnmi_ext.Extension{
+//	    Ext: &gnmi_ext.Extension_Depth{Depth: &gnmi_ext.Depth{Level: 1}},
+//	}))
+func Extension(exts ...*gnmi_ext.Extension) func(*Req) {
+	return func(req *Req) {
+		req.Extensions = append(req.Extensions, exts...)
+	}
+}
+
+// RegisteredExtension returns a request modifier that attaches a registered
+// extension with the given ID and payload.
+func RegisteredExtension(id gnmi_ext.ExtensionID, msg []byte) func(*Req) {
+	return Extension(&gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{RegisteredExt: &gnmi_ext.RegisteredExtension{Id: id, Msg: msg}},
+	})
+}
+
+// HistorySnapshot returns a request modifier that requests the data at a
+// point in time (History extension) for Subscribe.
+func HistorySnapshot(t time.Time) func(*Req) {
+	return Extension(&gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_History{History: &gnmi_ext.History{
+			Request: &gnmi_ext.History_SnapshotTime{SnapshotTime: t.UnixNano()},
+		}},
+	})
+}
+
+// HistoryRange returns a request modifier that requests the changes within a
+// time range (History extension) for Subscribe.
+func HistoryRange(start, end time.Time) func(*Req) {
+	return Extension(&gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_History{History: &gnmi_ext.History{
+			Request: &gnmi_ext.History_Range{Range: &gnmi_ext.TimeRange{
+				Start: start.UnixNano(),
+				End:   end.UnixNano(),
+			}},
+		}},
+	})
+}
+
+// Depth returns a request modifier that limits the depth of the returned
+// data tree (Depth extension) for Get and Subscribe.
+//
+// Level 1 returns only the direct children of the requested paths.
+func Depth(level uint32) func(*Req) {
+	return Extension(&gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_Depth{Depth: &gnmi_ext.Depth{Level: level}},
+	})
+}
+
+// MasterArbitration returns a request modifier that attaches the
+// MasterArbitration extension with the given role and election ID to Set.
+//
+// An empty role selects the default role. The election ID is sent as the low
+// 64 bits of the 128-bit gNMI election ID.
+func MasterArbitration(role string, electionID uint64) func(*Req) {
+	return Extension(newMasterArbitration(role, electionID))
+}
+
+// newMasterArbitration builds a MasterArbitration extension
+func newMasterArbitration(role string, electionID uint64) *gnmi_ext.Extension {
+	ma := &gnmi_ext.MasterArbitration{ElectionId: &gnmi_ext.Uint128{Low: electionID}}
+	if role != "" {
+		ma.Role = &gnmi_ext.Role{Id: role}
+	}
+	return &gnmi_ext.Extension{Ext: &gnmi_ext.Extension_MasterArbitration{MasterArbitration: ma}}
+}
+
+// validateExtensions validates the extensions of a request for operation
+// (Get, Set, or Subscribe)
+//
+// Rejects empty extensions, registered extensions without ID, History outside
+// Subscribe, Depth on Set, MasterArbitration and Commit outside Set, and
+// Commit combined with CommitConfirmed.
+//
+// Returns an error if validation fails.
+func validateExtensions(operation string, req *Req) error {
+	for i, ext := range req.Extensions {
+		if ext.GetExt() == nil {
+			return fmt.Errorf("extension at index %d cannot be empty", i)
+		}
+
+		switch e := ext.GetExt().(type) {
+		case *gnmi_ext.Extension_RegisteredExt:
+			if e.RegisteredExt.GetId() == gnmi_ext.ExtensionID_EID_UNSET {
+				return fmt.Errorf("registered extension at index %d requires an ID", i)
+			}
+		case *gnmi_ext.Extension_History:
+			if operation != "Subscribe" {
+				return fmt.Errorf("history extension at index %d is only supported by Subscribe", i)
+			}
+		case *gnmi_ext.Extension_Depth:
+			if operation == "Set" {
+				return fmt.Errorf("extension at index %d is not supported by Set", i)
+			}
+		case *gnmi_ext.Extension_MasterArbitration:
+			if operation != "Set" {
+				return fmt.Errorf("master arbitration extension at index %d is only supported by Set", i)
+			}
+		case *gnmi_ext.Extension_Commit:
+			if operation != "Set" {
+				return fmt.Errorf("commit extension at index %d is only supported by Set", i)
+			}
+			if req.RollbackDuration > 0 || req.commitAction != nil {
+				return fmt.Errorf("commit extension at index %d conflicts with CommitConfirmed", i)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestExtensions tests the accessors of extension lists
+func TestExtensions(t *testing.T) {
+	payload, err := proto.Marshal(&gnmi_ext.Depth{Level: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &Req{}
+	for _, mod := range []func(*Req){
+		Depth(2),
+		HistorySnapshot(time.Unix(0, 42)),
+		MasterArbitration("standby", 7),
+		RegisteredExtension(gnmi_ext.ExtensionID_EID_EXPERIMENTAL, payload),
+	} {
+		mod(req)
+	}
+	exts := req.Extensions
+
+	if d := exts.Depth(); d.GetLevel() != 2 {
+		t.Errorf("Depth() = %v, want level 2", d)
+	}
+	if h := exts.History(); h.GetSnapshotTime() != 42 {
+		t.Errorf("History() = %v, want snapshot 42", h)
+	}
+	if ma := exts.MasterArbitration(); ma.GetRole().GetId() != "standby" || ma.GetElectionId().GetLow() != 7 {
+		t.Errorf("MasterArbitration() = %v, want standby/7", ma)
+	}
+	if c := exts.Commit(); c != nil {
+		t.Errorf("Commit() = %v, want nil", c)
+	}
+
+	var decoded gnmi_ext.Depth
+	if ok, err := exts.UnmarshalRegistered(gnmi_ext.ExtensionID_EID_EXPERIMENTAL, &decoded); !ok || err != nil || decoded.GetLevel() != 3 {
+		t.Errorf("UnmarshalRegistered() = %v, %v, level %d, want level 3", ok, err, decoded.GetLevel())
+	}
+	if _, ok := exts.Registered(gnmi_ext.ExtensionID(1000)); ok {
+		t.Errorf("Registered() unknown ID found")
+	}
+	broken := Extensions{{Ext: &gnmi_ext.Extension_RegisteredExt{RegisteredExt: &gnmi_ext.RegisteredExtension{Id: 5, Msg: []byte{0xff}}}}}
+	if ok, err := broken.UnmarshalRegistered(5, &decoded); !ok || err == nil {
+		t.Errorf("UnmarshalRegistered() invalid payload = %v, %v, want error", ok, err)
+	}
+
+	// Default role is sent without role
+	if ma := newMasterArbitration("", 1).GetMasterArbitration(); ma.GetRole() != nil {
+		t.Errorf("default role = %v, want nil", ma.GetRole())
+	}
+}
+
+// TestValidateExtensions tests validation of request extensions per operation
+func TestValidateExtensions(t *testing.T) {
+	commit := &gnmi_ext.Extension{Ext: &gnmi_ext.Extension_Commit{Commit: &gnmi_ext.Commit{Id: "c1"}}}
+	tests := []struct {
+		name      string
+		operation string
+		mods      []func(*Req)
+		wantErr   string
+	}{
+		{"depth on Get", "Get", []func(*Req){Depth(1)}, ""},
+		{"history on Subscribe", "Subscribe", []func(*Req){HistoryRange(time.Unix(0, 0), time.Now())}, ""},
+		{"arbitration on Set", "Set", []func(*Req){MasterArbitration("", 1)}, ""},
+		{"commit on Set", "Set", []func(*Req){Extension(commit)}, ""},
+		{"empty extension", "Get", []func(*Req){Extension(&gnmi_ext.Extension{})}, "cannot be empty"},
+		{"nil extension", "Get", []func(*Req){Extension(nil)}, "cannot be empty"},
+		{"registered without ID", "Get", []func(*Req){RegisteredExtension(gnmi_ext.ExtensionID_EID_UNSET, nil)}, "requires an ID"},
+		{"depth on Set", "Set", []func(*Req){Depth(1)}, "not supported by Set"},
+		{"history on Get", "Get", []func(*Req){HistorySnapshot(time.Now())}, "only supported by Subscribe"},
+		{"history on Set", "Set", []func(*Req){HistoryRange(time.Unix(0, 0), time.Now())}, "only supported by Subscribe"},
+		{"arbitration on Get", "Get", []func(*Req){MasterArbitration("", 1)}, "only supported by Set"},
+		{"arbitration on Subscribe", "Subscribe", []func(*Req){MasterArbitration("", 1)}, "only supported by Set"},
+		{"commit on Get", "Get", []func(*Req){Extension(commit)}, "only supported by Set"},
+		{"commit with CommitConfirmed", "Set", []func(*Req){Extension(commit), CommitConfirmed(time.Minute)}, "conflicts with CommitConfirmed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &Req{}
+			for _, mod := range tt.mods {
+				mod(req)
+			}
+			err := validateExtensions(tt.operation, req)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateExtensions() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateExtensions() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestIntegration_Extensions tests request and response extensions against the fake server
+func TestIntegration_Extensions(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+	registered := &gnmi_ext.Extension{Ext: &gnmi_ext.Extension_RegisteredExt{
+		RegisteredExt: &gnmi_ext.RegisteredExtension{Id: gnmi_ext.ExtensionID_EID_EXPERIMENTAL, Msg: []byte("ok")},
+	}}
+	for _, rpc := range []gnmitest.RPC{gnmitest.RPCGet, gnmitest.RPCSet, gnmitest.RPCSubscribe} {
+		srv.SetResponseExtensions(rpc, registered)
+	}
+
+	t.Run("get", func(t *testing.T) {
+		res, err := client.Get(ctx, []string{"/system/config/hostname"}, Depth(1))
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		sent := Extensions(srv.GetRequests()[0].GetExtension())
+		if sent.Depth().GetLevel() != 1 {
+			t.Errorf("sent extensions = %v, want depth", sent)
+		}
+		if msg, ok := res.Extensions.Registered(gnmi_ext.ExtensionID_EID_EXPERIMENTAL); !ok || string(msg) != "ok" {
+			t.Errorf("response extensions = %v, want registered extension", res.Extensions)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		res, err := client.Set(ctx, []SetOperation{Update("/system/config/hostname", `"router2"`)}, MasterArbitration("primary", 9))
+		if err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		sent := Extensions(srv.SetRequests()[0].GetExtension())
+		if ma := sent.MasterArbitration(); ma.GetRole().GetId() != "primary" || ma.GetElectionId().GetLow() != 9 {
+			t.Errorf("sent extensions = %v, want master arbitration", sent)
+		}
+		if _, ok := res.Extensions.Registered(gnmi_ext.ExtensionID_EID_EXPERIMENTAL); !ok {
+			t.Errorf("response extensions = %v, want registered extension", res.Extensions)
+		}
+	})
+
+	t.Run("subscribe", func(t *testing.T) {
+		stream, err := client.Subscribe(ctx, []Subscription{TargetDefined("/system/config/hostname")},
+			SubscribeMode(SubscribeModeOnce), HistoryRange(time.Unix(0, 1), time.Unix(0, 2)))
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		defer stream.Close()
+		var sync SubscribeRes
+		for sync = nextResponse(t, stream); !sync.SyncResponse; sync = nextResponse(t, stream) {
+		}
+		if _, ok := sync.Extensions.Registered(gnmi_ext.ExtensionID_EID_EXPERIMENTAL); !ok {
+			t.Errorf("sync response extensions = %v, want registered extension", sync.Extensions)
+		}
+		sent := Extensions(srv.SubscribeRequests()[0].GetExtension())
+		if r := sent.History().GetRange(); r.GetStart() != 1 || r.GetEnd() != 2 {
+			t.Errorf("sent extensions = %v, want history range", sent)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := client.Set(ctx, []SetOperation{Delete("/system/config/hostname")}, Depth(1))
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("Set() with depth error = %v, want ErrValidation", err)
+		}
+		_, err = client.Get(ctx, []string{"/system/config/hostname"}, MasterArbitration("", 1))
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("Get() with master arbitration error = %v, want ErrValidation", err)
+		}
+	})
+}
@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+// Package gnmiprom exports go-gnmi client metrics to Prometheus.
+//
+// Metrics implements both gnmi.Metrics and prometheus.Collector: register it
+// with a Prometheus registry and pass it to clients with gnmi.WithMetrics.
+// One Metrics value can be shared by any number of clients; series are
+// labeled by target.
+//
+// Example:
+//
+//	metrics := gnmiprom.New()
+//	prometheus.MustRegister(metrics)
+//
+//	mgr := gnmi.NewManager(gnmi.ClientOptions(gnmi.WithMetrics(metrics)))
+//	http.Handle("/metrics", promhttp.Handler())
+//
+// Exported metrics (with the default namespace "gnmi" and subsystem "client"):
+//
+//	gnmi_client_operations_total{target, operation, code}       counter
+//	gnmi_client_operation_duration_seconds{target, operation}   histogram
+//	gnmi_client_retries_total{target, operation, code}          counter
+//	gnmi_client_reconnects_total{target, result}                counter
+//	gnmi_client_sent_bytes_total{target, operation}             counter
+//	gnmi_client_received_bytes_total{target, operation}         counter
+//	gnmi_client_active_subscriptions{target}                    gauge
+//	gnmi_client_limiter_wait_seconds{target, operation}         histogram
+package gnmiprom
+
+import (
+	"context"
+	"time"
+
+	"github.com/netascode/go-gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+)
+
+// Default metric naming
+const (
+	DefaultNamespace = "gnmi"
+	DefaultSubsystem = "client"
+)
+
+// Reconnect results used as the "result" label of the reconnects counter
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Metrics collects go-gnmi client measurements as Prometheus metrics
+type Metrics struct {
+	// Configuration set by options
+	namespace      string
+	subsystem      string
+	buckets        []float64
+	limiterBuckets []float64
+	constLabels    prometheus.Labels
+
+	operations    *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	retries       *prometheus.CounterVec
+	reconnects    *prometheus.CounterVec
+	sentBytes     *prometheus.CounterVec
+	receivedBytes *prometheus.CounterVec
+	subscriptions *prometheus.GaugeVec
+	limiterWait   *prometheus.HistogramVec
+
+	// collectors lists all metric vectors for Describe, Collect, and Forget
+	collectors []prometheus.Collector
+}
+
+var (
+	_ gnmi.Metrics         = (*Metrics)(nil)
+	_ prometheus.Collector = (*Metrics)(nil)
+)
+
+// Namespace sets the metric namespace (default: "gnmi")
+func Namespace(namespace string) func(*Metrics) {
+	return func(m *Metrics) {
+		m.namespace = namespace
+	}
+}
+
+// Subsystem sets the metric subsystem (default: "client")
+func Subsystem(subsystem string) func(*Metrics) {
+	return func(m *Metrics) {
+		m.subsystem = subsystem
+	}
+}
+
+// DurationBuckets sets the histogram buckets of the operation duration in
+// seconds (default: prometheus.DefBuckets)
+func DurationBuckets(buckets []float64) func(*Metrics) {
+	return func(m *Metrics) {
+		m.buckets = buckets
+	}
+}
+
+// LimiterBuckets sets the histogram buckets of the limiter queueing delay in
+// seconds (default: 0.001s to ~4s in powers of 4)
+func LimiterBuckets(buckets []float64) func(*Metrics) {
+	return func(m *Metrics) {
+		m.limiterBuckets = buckets
+	}
+}
+
+// ConstLabels sets labels added to all metrics (e.g., the name of the collector instance)
+func ConstLabels(labels prometheus.Labels) func(*Metrics) {
+	return func(m *Metrics) {
+		m.constLabels = labels
+	}
+}
+
+// New creates Metrics with the given options
+//
+// The metrics are not registered; pass the result to a
+// prometheus.Registerer (e.g., prometheus.MustRegister).
+//
+// Example:
+//
+//	metrics := gnmiprom.New(
+//	    gnmiprom.Namespace("netops"),
+//	    gnmiprom.DurationBuckets([]float64{0.05, 0.1, 0.5, 1, 5, 15}),
+//	)
+//	registry.MustRegister(metrics)
+func New(opts ...func(*Metrics)) *Metrics {
+	m := &Metrics{
+		namespace:      DefaultNamespace,
+		subsystem:      DefaultSubsystem,
+		buckets:        prometheus.DefBuckets,
+		limiterBuckets: prometheus.ExponentialBuckets(0.001, 4, 7),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	counter := func(name, help string, labels ...string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   m.namespace,
+			Subsystem:   m.subsystem,
+			Name:        name,
+			Help:        help,
+			ConstLabels: m.constLabels,
+		}, labels)
+	}
+	histogram := func(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   m.namespace,
+			Subsystem:   m.subsystem,
+			Name:        name,
+			Help:        help,
+			ConstLabels: m.constLabels,
+			Buckets:     buckets,
+		}, labels)
+	}
+
+	m.operations = counter("operations_total",
+		"Number of completed gNMI operations by gRPC status code.",
+		"target", "operation", "code")
+	m.duration = histogram("operation_duration_seconds",
+		"Duration of gNMI operations including retries.",
+		m.buckets, "target", "operation")
+	m.retries = counter("retries_total",
+		"Number of retried gNMI operations and resubscriptions by gRPC status code of the failure.",
+		"target", "operation", "code")
+	m.reconnects = counter("reconnects_total",
+		"Number of reconnection attempts by result.",
+		"target", "result")
+	m.sentBytes = counter("sent_bytes_total",
+		"Encoded size of gNMI requests sent.",
+		"target", "operation")
+	m.receivedBytes = counter("received_bytes_total",
+		"Encoded size of gNMI responses received.",
+		"target", "operation")
+	m.subscriptions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   m.namespace,
+		Subsystem:   m.subsystem,
+		Name:        "active_subscriptions",
+		Help:        "Number of active gNMI subscriptions.",
+		ConstLabels: m.constLabels,
+	}, []string{"target"})
+	m.limiterWait = histogram("limiter_wait_seconds",
+		"Time gNMI operations waited for the client request limiter.",
+		m.limiterBuckets, "target", "operation")
+
+	m.collectors = []prometheus.Collector{
+		m.operations, m.duration, m.retries, m.reconnects,
+		m.sentBytes, m.receivedBytes, m.subscriptions, m.limiterWait,
+	}
+	return m
+}
+
+// Describe implements prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range m.collectors {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range m.collectors {
+		c.Collect(ch)
+	}
+}
+
+// OperationCompleted implements gnmi.Metrics
+func (m *Metrics) OperationCompleted(_ context.Context, target, operation string, code codes.Code, duration time.Duration) {
+	m.operations.WithLabelValues(target, operation, code.String()).Inc()
+	m.duration.WithLabelValues(target, operation).Observe(duration.Seconds())
+}
+
+// OperationRetried implements gnmi.Metrics
+func (m *Metrics) OperationRetried(_ context.Context, target, operation string, code codes.Code) {
+	m.retries.WithLabelValues(target, operation, code.String()).Inc()
+}
+
+// Reconnected implements gnmi.Metrics
+func (m *Metrics) Reconnected(_ context.Context, target string, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+	m.reconnects.WithLabelValues(target, result).Inc()
+}
+
+// BytesTransferred implements gnmi.Metrics
+func (m *Metrics) BytesTransferred(_ context.Context, target, operation string, sent, received int) {
+	if sent > 0 {
+		m.sentBytes.WithLabelValues(target, operation).Add(float64(sent))
+	}
+	if received > 0 {
+		m.receivedBytes.WithLabelValues(target, operation).Add(float64(received))
+	}
+}
+
+// SubscriptionsChanged implements gnmi.Metrics
+func (m *Metrics) SubscriptionsChanged(_ context.Context, target string, delta int) {
+	m.subscriptions.WithLabelValues(target).Add(float64(delta))
+}
+
+// LimiterWaited implements gnmi.Metrics
+func (m *Metrics) LimiterWaited(_ context.Context, target, operation string, wait time.Duration) {
+	m.limiterWait.WithLabelValues(target, operation).Observe(wait.Seconds())
+}
+
+// Forget removes all series of a target, e.g. after it was removed from a gnmi.Manager
+func (m *Metrics) Forget(target string) {
+	labels := prometheus.Labels{"target": target}
+	for _, c := range m.collectors {
+		if vec, ok := c.(interface {
+			DeletePartialMatch(prometheus.Labels) int
+		}); ok {
+			vec.DeletePartialMatch(labels)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmiprom
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi"
+	"github.com/netascode/go-gnmi/gnmitest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+)
+
+// TestMetrics tests the exported series for each gnmi.Metrics call
+func TestMetrics(t *testing.T) {
+	m := New(Namespace("test"), ConstLabels(prometheus.Labels{"instance": "a"}))
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(m); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	ctx := context.Background()
+	m.OperationCompleted(ctx, "r1", "Get", codes.OK, 20*time.Millisecond)
+	m.OperationCompleted(ctx, "r1", "Get", codes.Unavailable, time.Second)
+	m.OperationRetried(ctx, "r1", "Get", codes.Unavailable)
+	m.Reconnected(ctx, "r1", nil)
+	m.Reconnected(ctx, "r1", errors.New("refused"))
+	m.BytesTransferred(ctx, "r1", "Get", 100, 2000)
+	m.SubscriptionsChanged(ctx, "r1", 1)
+	m.SubscriptionsChanged(ctx, "r2", 1)
+	m.SubscriptionsChanged(ctx, "r2", -1)
+	m.LimiterWaited(ctx, "r1", "Set", 5*time.Millisecond)
+
+	want := `
+# HELP test_client_operations_total Number of completed gNMI operations by gRPC status code.
+# TYPE test_client_operations_total counter
+test_client_operations_total{code="OK",instance="a",operation="Get",target="r1"} 1
+test_client_operations_total{code="Unavailable",instance="a",operation="Get",target="r1"} 1
+# HELP test_client_reconnects_total Number of reconnection attempts by result.
+# TYPE test_client_reconnects_total counter
+test_client_reconnects_total{instance="a",result="failure",target="r1"} 1
+test_client_reconnects_total{instance="a",result="success",target="r1"} 1
+# HELP test_client_sent_bytes_total Encoded size of gNMI requests sent.
+# TYPE test_client_sent_bytes_total counter
+test_client_sent_bytes_total{instance="a",operation="Get",target="r1"} 100
+# HELP test_client_received_bytes_total Encoded size of gNMI responses received.
+# TYPE test_client_received_bytes_total counter
+test_client_received_bytes_total{instance="a",operation="Get",target="r1"} 2000
+# HELP test_client_active_subscriptions Number of active gNMI subscriptions.
+# TYPE test_client_active_subscriptions gauge
+test_client_active_subscriptions{instance="a",target="r1"} 1
+test_client_active_subscriptions{instance="a",target="r2"} 0
+`
+	names := []string{
+		"test_client_operations_total",
+		"test_client_reconnects_total",
+		"test_client_sent_bytes_total",
+		"test_client_received_bytes_total",
+		"test_client_active_subscriptions",
+	}
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), names...); err != nil {
+		t.Errorf("GatherAndCompare() error = %v", err)
+	}
+
+	if n := testutil.CollectAndCount(m, "test_client_operation_duration_seconds"); n != 1 {
+		t.Errorf("duration series = %d, want 1", n)
+	}
+	if n := testutil.CollectAndCount(m, "test_client_limiter_wait_seconds"); n != 1 {
+		t.Errorf("limiter wait series = %d, want 1", n)
+	}
+
+	m.Forget("r1")
+	if n := testutil.CollectAndCount(m); n != 1 {
+		t.Errorf("series after Forget = %d, want 1 (r2 subscriptions)", n)
+	}
+}
+
+// TestMetrics_Client tests the adapter with a client against the fake server
+func TestMetrics_Client(t *testing.T) {
+	srv, err := gnmitest.NewServer()
+	if err != nil {
+		t.Fatalf("gnmitest.NewServer() error = %v", err)
+	}
+	defer srv.Close()
+	if err := srv.Load("/system/config", `{"hostname": "r1"}`); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	m := New()
+	client, err := gnmi.NewClient(srv.Addr(), gnmi.TLS(false), gnmi.WithMetrics(m))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Get(context.Background(), []string{"/system/config"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	ops := m.operations.WithLabelValues(client.Target, "Get", "OK")
+	if v := testutil.ToFloat64(ops); v != 1 {
+		t.Errorf("gnmi_client_operations_total = %v, want 1", v)
+	}
+	if v := testutil.ToFloat64(m.receivedBytes.WithLabelValues(client.Target, "Get")); v <= 0 {
+		t.Errorf("gnmi_client_received_bytes_total = %v, want > 0", v)
+	}
+}
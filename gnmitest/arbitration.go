@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmitest
+
+import (
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PrimaryElectionID returns the highest election ID received for role via
+// the MasterArbitration extension, or 0 if none was received
+//
+// An empty role is the default role. Only the low 64 bits of the election ID
+// are returned.
+func (s *Server) PrimaryElectionID(role string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.elections[role][1]
+}
+
+// arbitrateLocked applies master arbitration to a Set request
+//
+// Requests with an election ID lower than the highest one received for the
+// role fail with PERMISSION_DENIED; others make their election ID the
+// highest. Requests without MasterArbitration extension are accepted.
+func (s *Server) arbitrateLocked(req *gnmipb.SetRequest) error {
+	for _, ext := range req.GetExtension() {
+		ma := ext.GetMasterArbitration()
+		if ma == nil {
+			continue
+		}
+		if ma.GetElectionId() == nil {
+			return status.Errorf(codes.InvalidArgument, "master arbitration requires an election id")
+		}
+		role := ma.GetRole().GetId()
+		id := [2]uint64{ma.GetElectionId().GetHigh(), ma.GetElectionId().GetLow()}
+		primary := s.elections[role]
+		if id[0] < primary[0] || (id[0] == primary[0] && id[1] < primary[1]) {
+			return status.Errorf(codes.PermissionDenied, "election id %d is lower than the primary election id %d", id[1], primary[1])
+		}
+		s.elections[role] = id
+	}
+	return nil
+}
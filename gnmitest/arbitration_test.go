@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmitest
+
+import (
+	"context"
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestServerArbitration tests master arbitration of Set requests
+func TestServerArbitration(t *testing.T) {
+	srv, client := newTestServer(t)
+	set := func(role string, high, low uint64) error {
+		ma := &gnmi_ext.MasterArbitration{ElectionId: &gnmi_ext.Uint128{High: high, Low: low}}
+		if role != "" {
+			ma.Role = &gnmi_ext.Role{Id: role}
+		}
+		_, err := client.Set(context.Background(), &gnmipb.SetRequest{
+			Update:    []*gnmipb.Update{jsonUpdate(t, "/system/config/hostname", `"router1"`)},
+			Extension: []*gnmi_ext.Extension{{Ext: &gnmi_ext.Extension_MasterArbitration{MasterArbitration: ma}}},
+		})
+		return err
+	}
+
+	if err := set("", 0, 5); err != nil {
+		t.Fatalf("Set() election 5 error = %v", err)
+	}
+	if err := set("", 0, 5); err != nil {
+		t.Errorf("Set() same election ID error = %v", err)
+	}
+	if err := set("", 0, 4); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Set() lower election ID error = %v, want PermissionDenied", err)
+	}
+	if err := set("other", 0, 1); err != nil {
+		t.Errorf("Set() other role error = %v", err)
+	}
+	if err := set("", 1, 0); err != nil {
+		t.Errorf("Set() higher election ID error = %v", err)
+	}
+	if err := set("", 0, 6); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Set() lower high bits error = %v, want PermissionDenied", err)
+	}
+	if id := srv.PrimaryElectionID("other"); id != 1 {
+		t.Errorf("PrimaryElectionID(other) = %d, want 1", id)
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmitest
+
+import (
+	"time"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// pendingCommit is a commit-confirmed Set awaiting confirmation
+type pendingCommit struct {
+	id      string
+	data    *tree
+	cli     string
+	changed []*gnmipb.Path
+	timer   *time.Timer
+}
+
+// PendingCommit returns the ID of the commit-confirmed Set awaiting
+// confirmation, or "" if there is none
+func (s *Server) PendingCommit() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.commit == nil {
+		return ""
+	}
+	return s.commit.id
+}
+
+// commitExtension returns the commit extension of req, or nil
+func commitExtension(req *gnmipb.SetRequest) *gnmi_ext.Commit {
+	for _, ext := range req.GetExtension() {
+		if commit := ext.GetCommit(); commit != nil {
+			return commit
+		}
+	}
+	return nil
+}
+
+// checkCommitLocked validates a commit request before the Set is applied
+//
+// Only one commit can await confirmation at a time.
+func (s *Server) checkCommitLocked(commit *gnmi_ext.Commit) error {
+	if commit.GetId() == "" {
+		return status.Errorf(codes.InvalidArgument, "commit id cannot be empty")
+	}
+	if commit.GetCommit().GetRollbackDuration().AsDuration() <= 0 {
+		return status.Errorf(codes.InvalidArgument, "commit rollback duration must be positive")
+	}
+	if s.commit != nil {
+		return status.Errorf(codes.FailedPrecondition, "commit %s awaits confirmation", s.commit.id)
+	}
+	return nil
+}
+
+// startCommitLocked records the state before a commit-confirmed Set and
+// starts the rollback timer
+func (s *Server) startCommitLocked(commit *gnmi_ext.Commit, data *tree, cli string, changed []*gnmipb.Path) {
+	pending := &pendingCommit{id: commit.GetId(), data: data, cli: cli, changed: changed}
+	pending.timer = time.AfterFunc(commit.GetCommit().GetRollbackDuration().AsDuration(), func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.commit == pending {
+			s.rollbackLocked()
+		}
+	})
+	s.commit = pending
+}
+
+// commitActionLocked applies a confirm, cancel, or set rollback duration action
+func (s *Server) commitActionLocked(commit *gnmi_ext.Commit) error {
+	if s.commit == nil || s.commit.id != commit.GetId() {
+		return status.Errorf(codes.NotFound, "commit %s not found", commit.GetId())
+	}
+
+	switch action := commit.GetAction().(type) {
+	case *gnmi_ext.Commit_Confirm:
+		s.commit.timer.Stop()
+		s.commit = nil
+	case *gnmi_ext.Commit_Cancel:
+		s.rollbackLocked()
+	case *gnmi_ext.Commit_SetRollbackDuration:
+		d := action.SetRollbackDuration.GetRollbackDuration().AsDuration()
+		if d <= 0 {
+			return status.Errorf(codes.InvalidArgument, "commit rollback duration must be positive")
+		}
+		s.commit.timer.Reset(d)
+	default:
+		return status.Errorf(codes.InvalidArgument, "commit action missing")
+	}
+	return nil
+}
+
+// rollbackLocked restores the state before the pending commit
+func (s *Server) rollbackLocked() {
+	s.commit.timer.Stop()
+	s.data = s.commit.data
+	s.cli = s.commit.cli
+	s.notifyLocked(s.commit.changed)
+	s.commit = nil
+}
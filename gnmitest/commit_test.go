@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmitest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// commitRequest returns a Set request carrying the commit extension
+func commitRequest(commit *gnmi_ext.Commit, updates ...*gnmipb.Update) *gnmipb.SetRequest {
+	return &gnmipb.SetRequest{
+		Update:    updates,
+		Extension: []*gnmi_ext.Extension{{Ext: &gnmi_ext.Extension_Commit{Commit: commit}}},
+	}
+}
+
+// newCommit returns a commit extension starting a commit-confirmed Set
+func newCommit(id string, rollback time.Duration) *gnmi_ext.Commit {
+	return &gnmi_ext.Commit{Id: id, Action: &gnmi_ext.Commit_Commit{
+		Commit: &gnmi_ext.CommitRequest{RollbackDuration: durationpb.New(rollback)},
+	}}
+}
+
+// TestServerCommit tests commit-confirmed Set with confirm, cancel, and rollback
+func TestServerCommit(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+
+	if err := srv.Load("/system/config", `{"hostname": "router1"}`); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	hostname := func() string {
+		v, _ := srv.JSON("/system/config/hostname")
+		return v
+	}
+	set := func(req *gnmipb.SetRequest) error {
+		_, err := client.Set(ctx, req)
+		return err
+	}
+
+	t.Run("confirm", func(t *testing.T) {
+		if err := set(commitRequest(newCommit("c1", time.Minute), jsonUpdate(t, "/system/config/hostname", `"router2"`))); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if id := srv.PendingCommit(); id != "c1" {
+			t.Errorf("PendingCommit() = %q, want c1", id)
+		}
+		if err := set(commitRequest(newCommit("c2", time.Minute), jsonUpdate(t, "/system/config/hostname", `"router3"`))); status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("second commit error = %v, want FailedPrecondition", err)
+		}
+		if err := set(commitRequest(&gnmi_ext.Commit{Id: "c1", Action: &gnmi_ext.Commit_Confirm{Confirm: &gnmi_ext.CommitConfirm{}}})); err != nil {
+			t.Fatalf("confirm error = %v", err)
+		}
+		if id := srv.PendingCommit(); id != "" || hostname() != `"router2"` {
+			t.Errorf("after confirm: pending %q, hostname %s, want none and \"router2\"", id, hostname())
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		if err := set(commitRequest(newCommit("c3", time.Minute), jsonUpdate(t, "/system/config/hostname", `"router3"`))); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if err := set(commitRequest(&gnmi_ext.Commit{Id: "other", Action: &gnmi_ext.Commit_Cancel{Cancel: &gnmi_ext.CommitCancel{}}})); status.Code(err) != codes.NotFound {
+			t.Errorf("cancel unknown commit error = %v, want NotFound", err)
+		}
+		if err := set(commitRequest(&gnmi_ext.Commit{Id: "c3", Action: &gnmi_ext.Commit_Cancel{Cancel: &gnmi_ext.CommitCancel{}}})); err != nil {
+			t.Fatalf("cancel error = %v", err)
+		}
+		if hostname() != `"router2"` {
+			t.Errorf("hostname after cancel = %s, want \"router2\"", hostname())
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		if err := set(commitRequest(newCommit("c4", time.Hour), jsonUpdate(t, "/system/config/hostname", `"router4"`))); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		rollback := &gnmi_ext.Commit{Id: "c4", Action: &gnmi_ext.Commit_SetRollbackDuration{
+			SetRollbackDuration: &gnmi_ext.CommitSetRollbackDuration{RollbackDuration: durationpb.New(20 * time.Millisecond)},
+		}}
+		if err := set(commitRequest(rollback)); err != nil {
+			t.Fatalf("set rollback duration error = %v", err)
+		}
+		deadline := time.Now().Add(2 * time.Second)
+		for srv.PendingCommit() != "" && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if id := srv.PendingCommit(); id != "" || hostname() != `"router2"` {
+			t.Errorf("after rollback: pending %q, hostname %s, want none and \"router2\"", id, hostname())
+		}
+	})
+}
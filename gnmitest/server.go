@@ -0,0 +1,871 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+// Package gnmitest provides an in-process fake gNMI server for testing.
+//
+// The server listens on a loopback address, stores its data in an in-memory
+// JSON tree, and implements the Get, Set, Capabilities, and Subscribe RPCs.
+// Tests can inject gRPC status codes, latency, and dropped connections to
+// exercise retry and reconnect logic without a real device.
+//
+// Example:
+//
+//	srv, err := gnmitest.NewServer()
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	defer srv.Close()
+//
+//	if err := srv.Load("/system/config", `{"hostname": "router1"}`); err != nil {
+//	    t.Fatal(err)
+//	}
+//
+//	// Fail the next two Get calls with UNAVAILABLE
+//	srv.FailNext(gnmitest.RPCGet, codes.Unavailable, 2)
+//
+//	client, _ := gnmi.NewClient(srv.Addr(), gnmi.TLS(false))
+//	res, err := client.Get(ctx, []string{"/system/config/hostname"})
+//
+// The server is intentionally simple: paths must not contain wildcards, SAMPLE
+// intervals are not honored (all STREAM subscriptions behave like ON_CHANGE),
+// and only JSON and JSON_IETF encodings are supported.
+package gnmitest
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	gnmipath "github.com/openconfig/gnmic/pkg/api/path"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Default server configuration values
+const (
+	DefaultListenAddress = "127.0.0.1:0"
+	DefaultGNMIVersion   = "0.10.0"
+)
+
+// originCLI is the path origin of CLI configuration sent as ASCII text
+const originCLI = "cli"
+
+// RPC identifies a gNMI RPC for fault injection and call counting
+type RPC string
+
+const (
+	// RPCCapabilities is the gNMI Capabilities RPC
+	RPCCapabilities RPC = "capabilities"
+
+	// RPCGet is the gNMI Get RPC
+	RPCGet RPC = "get"
+
+	// RPCSet is the gNMI Set RPC
+	RPCSet RPC = "set"
+
+	// RPCSubscribe is the gNMI Subscribe RPC
+	RPCSubscribe RPC = "subscribe"
+)
+
+// Server is an in-process fake gNMI server
+//
+// Thread-safe: all methods may be called concurrently with running RPCs.
+type Server struct {
+	gnmipb.UnimplementedGNMIServer
+
+	listener   *trackingListener
+	grpcServer *grpc.Server
+
+	// Configuration (set by options before start)
+	listenAddress string
+	username      string
+	password      string
+	token         string
+	tlsConfig     *tls.Config
+	models        []*gnmipb.ModelData
+
+	mu        sync.Mutex
+	data      *tree
+	cli       string
+	commit    *pendingCommit
+	exts      map[RPC][]*gnmi_ext.Extension
+	elections map[string][2]uint64
+	failures  map[RPC][]error
+	latency   map[RPC]time.Duration
+	calls     map[RPC]int
+	getReqs   []*gnmipb.GetRequest
+	setReqs   []*gnmipb.SetRequest
+	subReqs   []*gnmipb.SubscribeRequest
+	watchers  map[*watcher]struct{}
+	closeOnce sync.Once
+}
+
+// watcher is an active STREAM subscription waiting for tree changes
+type watcher struct {
+	prefix  *gnmipb.Path
+	paths   []*gnmipb.Path
+	updates chan *gnmipb.Notification
+	done    chan struct{}
+}
+
+// Credentials sets the username and password the server requires
+//
+// Requests without matching "username" and "password" metadata fail with
+// UNAUTHENTICATED. By default no credentials are required.
+func Credentials(username, password string) func(*Server) {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// Token sets the bearer token the server requires
+//
+// Requests without "authorization: Bearer <token>" metadata fail with
+// UNAUTHENTICATED. Use SetToken to rotate the token while the server runs.
+func Token(token string) func(*Server) {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// TLSConfig makes the server accept TLS connections with cfg instead of plaintext
+//
+// Set cfg.ClientAuth to require client certificates.
+func TLSConfig(cfg *tls.Config) func(*Server) {
+	return func(s *Server) {
+		s.tlsConfig = cfg
+	}
+}
+
+// ListenAddress sets the address the server listens on (default: 127.0.0.1:0)
+func ListenAddress(address string) func(*Server) {
+	return func(s *Server) {
+		s.listenAddress = address
+	}
+}
+
+// Models sets the models reported in the Capabilities response
+func Models(models ...*gnmipb.ModelData) func(*Server) {
+	return func(s *Server) {
+		s.models = models
+	}
+}
+
+// NewServer creates and starts a fake gNMI server
+//
+// The server accepts plaintext connections (use gnmi.TLS(false) on the
+// client) unless TLSConfig is set. Call Close() to stop it.
+//
+// Returns the running Server or an error if the listener cannot be created.
+func NewServer(opts ...func(*Server)) (*Server, error) {
+	s := &Server{
+		listenAddress: DefaultListenAddress,
+		data:          newTree(),
+		failures:      make(map[RPC][]error),
+		latency:       make(map[RPC]time.Duration),
+		calls:         make(map[RPC]int),
+		exts:          make(map[RPC][]*gnmi_ext.Extension),
+		elections:     make(map[string][2]uint64),
+		watchers:      make(map[*watcher]struct{}),
+	}
+
+	// Apply functional options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	lis, err := net.Listen("tcp", s.listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", s.listenAddress, err)
+	}
+
+	s.listener = &trackingListener{Listener: lis, conns: make(map[net.Conn]struct{})}
+	var serverOpts []grpc.ServerOption
+	if s.tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	s.grpcServer = grpc.NewServer(serverOpts...)
+	gnmipb.RegisterGNMIServer(s.grpcServer, s)
+
+	go s.grpcServer.Serve(s.listener) //nolint:errcheck // Serve returns when the server is stopped
+
+	return s, nil
+}
+
+// Addr returns the address the server listens on (host:port)
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the server and closes all connections
+//
+// Thread-safe: safe to call multiple times (subsequent calls are no-ops).
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		s.grpcServer.Stop()
+	})
+}
+
+// Load merges a JSON value into the tree at the given path
+//
+// Active STREAM subscriptions covering the path are notified.
+//
+// Example:
+//
+//	err := srv.Load("/interfaces/interface[name=eth0]/config",
+//	    `{"name": "eth0", "mtu": 1500}`)
+func (s *Server) Load(path, value string) error {
+	p, err := gnmipath.ParsePath(path)
+	if err != nil {
+		return fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	v, err := decodeJSON([]byte(value))
+	if err != nil {
+		return fmt.Errorf("invalid JSON value: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.data.update(p.GetElem(), v); err != nil {
+		return err
+	}
+	s.notifyLocked([]*gnmipb.Path{p})
+	return nil
+}
+
+// JSON returns the JSON value stored at the given path
+//
+// Returns false if the path does not exist.
+func (s *Server) JSON(path string) (string, bool) {
+	p, err := gnmipath.ParsePath(path)
+	if err != nil {
+		return "", false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.data.get(p.GetElem())
+	if !ok {
+		return "", false
+	}
+	tv, err := encodeTypedValue(v, gnmipb.Encoding_JSON_IETF)
+	if err != nil {
+		return "", false
+	}
+	return string(tv.GetJsonIetfVal()), true
+}
+
+// FailNext makes the next n calls of rpc fail with the given gRPC status code
+//
+// Failures are queued: calling FailNext twice for the same RPC fails the
+// following calls with the first code, then the second.
+func (s *Server) FailNext(rpc RPC, code codes.Code, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		s.failures[rpc] = append(s.failures[rpc], status.Errorf(code, "gnmitest: injected %s failure", code))
+	}
+}
+
+// SetLatency delays every subsequent call of rpc by d (zero disables)
+//
+// The delay respects the caller's deadline: a call whose context expires
+// while waiting fails with DEADLINE_EXCEEDED or CANCELED.
+func (s *Server) SetLatency(rpc RPC, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency[rpc] = d
+}
+
+// DropConnections closes all client connections without stopping the server
+//
+// Clients see UNAVAILABLE errors on in-flight RPCs and active streams, which
+// simulates a link flap or device reload. New connections are accepted.
+func (s *Server) DropConnections() {
+	s.listener.closeConns()
+}
+
+// SetCredentials replaces the username and password the server requires (see Credentials)
+//
+// Requests with the previous credentials fail with UNAUTHENTICATED, e.g. to
+// simulate a rotated password.
+func (s *Server) SetCredentials(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username = username
+	s.password = password
+}
+
+// SetToken replaces the bearer token the server requires (see Token)
+//
+// Requests with the previous token fail with UNAUTHENTICATED, e.g. to
+// simulate an expired token. An empty token disables token authentication.
+func (s *Server) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// SetResponseExtensions sets the gNMI extensions returned in responses of rpc
+//
+// Get and Set responses carry the extensions, as does the sync response of
+// Subscribe. Capabilities is not supported.
+func (s *Server) SetResponseExtensions(rpc RPC, exts ...*gnmi_ext.Extension) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exts[rpc] = exts
+}
+
+// Calls returns the number of calls received for rpc, including failed ones
+func (s *Server) Calls(rpc RPC) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[rpc]
+}
+
+// GetRequests returns copies of all Get requests received that passed fault injection
+func (s *Server) GetRequests() []*gnmipb.GetRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneAll(s.getReqs)
+}
+
+// SetRequests returns copies of all Set requests received that passed fault injection
+func (s *Server) SetRequests() []*gnmipb.SetRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneAll(s.setReqs)
+}
+
+// SubscribeRequests returns copies of all initial Subscribe requests received
+// that passed fault injection
+func (s *Server) SubscribeRequests() []*gnmipb.SubscribeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneAll(s.subReqs)
+}
+
+// CLIConfig returns the CLI configuration text set via the "cli" origin
+func (s *Server) CLIConfig() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cli
+}
+
+// Capabilities implements the gNMI Capabilities RPC
+func (s *Server) Capabilities(ctx context.Context, _ *gnmipb.CapabilityRequest) (*gnmipb.CapabilityResponse, error) {
+	if err := s.intercept(ctx, RPCCapabilities); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	models := s.models
+	s.mu.Unlock()
+
+	return &gnmipb.CapabilityResponse{
+		SupportedModels:    models,
+		SupportedEncodings: []gnmipb.Encoding{gnmipb.Encoding_JSON, gnmipb.Encoding_JSON_IETF},
+		GNMIVersion:        DefaultGNMIVersion,
+	}, nil
+}
+
+// Get implements the gNMI Get RPC
+//
+// Each requested path yields one notification with a single update holding
+// the JSON value at that path. Missing paths fail the RPC with NOT_FOUND.
+func (s *Server) Get(ctx context.Context, req *gnmipb.GetRequest) (*gnmipb.GetResponse, error) {
+	if err := s.intercept(ctx, RPCGet); err != nil {
+		return nil, err
+	}
+	if err := checkEncoding(req.GetEncoding()); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.getReqs = append(s.getReqs, proto.Clone(req).(*gnmipb.GetRequest))
+
+	now := time.Now().UnixNano()
+	notifications := make([]*gnmipb.Notification, 0, len(req.GetPath()))
+	for _, p := range req.GetPath() {
+		elems := joinElems(req.GetPrefix(), p)
+		if hasWildcard(elems) {
+			return nil, status.Errorf(codes.Unimplemented, "gnmitest: wildcards are not supported")
+		}
+		v, ok := s.data.get(elems)
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "path %s not found", gnmipath.GnmiPathToXPath(&gnmipb.Path{Elem: elems}, false))
+		}
+		tv, err := encodeTypedValue(v, req.GetEncoding())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "gnmitest: %v", err)
+		}
+		notifications = append(notifications, &gnmipb.Notification{
+			Timestamp: now,
+			Prefix:    req.GetPrefix(),
+			Update:    []*gnmipb.Update{{Path: p, Val: tv}},
+		})
+	}
+
+	return &gnmipb.GetResponse{Notification: notifications, Extension: s.exts[RPCGet]}, nil
+}
+
+// Set implements the gNMI Set RPC
+//
+// Deletes, replaces, union replaces, and updates are applied in that order to
+// a copy of the tree, which is committed only if all operations succeed
+// (atomic Set). Union replaces are applied like replaces. Values for the "cli"
+// origin must be ASCII text and are kept separately (see CLIConfig): replaces
+// set the text, updates append to it.
+//
+// The gNMI commit extension is supported: a commit-confirmed Set is rolled
+// back unless confirmed within its rollback duration, and confirm, cancel, and
+// set rollback duration requests are accepted without operations (see
+// PendingCommit). Master arbitration rejects Sets with an election ID lower
+// than the highest one of the role (see PrimaryElectionID).
+func (s *Server) Set(ctx context.Context, req *gnmipb.SetRequest) (*gnmipb.SetResponse, error) {
+	if err := s.intercept(ctx, RPCSet); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setReqs = append(s.setReqs, proto.Clone(req).(*gnmipb.SetRequest))
+
+	if err := s.arbitrateLocked(req); err != nil {
+		return nil, err
+	}
+
+	commit := commitExtension(req)
+	if commit != nil {
+		if commit.GetCommit() == nil {
+			if err := s.commitActionLocked(commit); err != nil {
+				return nil, err
+			}
+			return &gnmipb.SetResponse{Prefix: req.GetPrefix(), Timestamp: time.Now().UnixNano(), Extension: s.exts[RPCSet]}, nil
+		}
+		if err := s.checkCommitLocked(commit); err != nil {
+			return nil, err
+		}
+	}
+
+	data := s.data.clone()
+	cli := s.cli
+	results := make([]*gnmipb.UpdateResult, 0, len(req.GetDelete())+len(req.GetReplace())+len(req.GetUpdate()))
+	changed := make([]*gnmipb.Path, 0, cap(results))
+
+	for _, p := range req.GetDelete() {
+		elems := joinElems(req.GetPrefix(), p)
+		if hasWildcard(elems) {
+			return nil, status.Errorf(codes.Unimplemented, "gnmitest: wildcards are not supported")
+		}
+		data.delete(elems)
+		results = append(results, &gnmipb.UpdateResult{Path: p, Op: gnmipb.UpdateResult_DELETE})
+		changed = append(changed, &gnmipb.Path{Elem: elems})
+	}
+
+	apply := []struct {
+		updates []*gnmipb.Update
+		op      gnmipb.UpdateResult_Operation
+		fn      func([]*gnmipb.PathElem, any) error
+	}{
+		{req.GetReplace(), gnmipb.UpdateResult_REPLACE, data.replace},
+		{req.GetUnionReplace(), gnmipb.UpdateResult_UNION_REPLACE, data.replace},
+		{req.GetUpdate(), gnmipb.UpdateResult_UPDATE, data.update},
+	}
+	for _, a := range apply {
+		for _, u := range a.updates {
+			if origin(req.GetPrefix(), u.GetPath()) == originCLI {
+				text, ok := u.GetVal().GetValue().(*gnmipb.TypedValue_AsciiVal)
+				if !ok {
+					return nil, status.Errorf(codes.InvalidArgument, "gnmitest: cli origin requires ascii_val")
+				}
+				if a.op == gnmipb.UpdateResult_UPDATE {
+					cli += text.AsciiVal
+				} else {
+					cli = text.AsciiVal
+				}
+				results = append(results, &gnmipb.UpdateResult{Path: u.GetPath(), Op: a.op})
+				continue
+			}
+			elems := joinElems(req.GetPrefix(), u.GetPath())
+			if hasWildcard(elems) {
+				return nil, status.Errorf(codes.Unimplemented, "gnmitest: wildcards are not supported")
+			}
+			v, err := decodeTypedValue(u.GetVal())
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid value for %s: %v",
+					gnmipath.GnmiPathToXPath(u.GetPath(), false), err)
+			}
+			if err := a.fn(elems, v); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "%s: %v",
+					gnmipath.GnmiPathToXPath(u.GetPath(), false), err)
+			}
+			results = append(results, &gnmipb.UpdateResult{Path: u.GetPath(), Op: a.op})
+			changed = append(changed, &gnmipb.Path{Elem: elems})
+		}
+	}
+
+	if commit != nil {
+		s.startCommitLocked(commit, s.data, s.cli, changed)
+	}
+	s.data = data
+	s.cli = cli
+	s.notifyLocked(changed)
+
+	return &gnmipb.SetResponse{
+		Prefix:    req.GetPrefix(),
+		Response:  results,
+		Timestamp: time.Now().UnixNano(),
+		Extension: s.exts[RPCSet],
+	}, nil
+}
+
+// Subscribe implements the gNMI Subscribe RPC
+//
+// ONCE subscriptions receive the current state and a sync response. POLL
+// subscriptions receive the same on every poll. STREAM subscriptions receive
+// the current state (unless updates_only is set), a sync response, and then
+// a notification whenever a subscribed path changes via Set or Load.
+func (s *Server) Subscribe(stream gnmipb.GNMI_SubscribeServer) error {
+	ctx := stream.Context()
+	if err := s.intercept(ctx, RPCSubscribe); err != nil {
+		return err
+	}
+
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	list := req.GetSubscribe()
+	if list == nil {
+		return status.Errorf(codes.InvalidArgument, "first message must be a SubscriptionList")
+	}
+	if err := checkEncoding(list.GetEncoding()); err != nil {
+		return err
+	}
+
+	paths := make([]*gnmipb.Path, 0, len(list.GetSubscription()))
+	for _, sub := range list.GetSubscription() {
+		if hasWildcard(joinElems(list.GetPrefix(), sub.GetPath())) {
+			return status.Errorf(codes.Unimplemented, "gnmitest: wildcards are not supported")
+		}
+		paths = append(paths, sub.GetPath())
+	}
+
+	s.mu.Lock()
+	s.subReqs = append(s.subReqs, proto.Clone(req).(*gnmipb.SubscribeRequest))
+	s.mu.Unlock()
+
+	switch list.GetMode() {
+	case gnmipb.SubscriptionList_ONCE:
+		return s.sendState(stream, list, paths, false)
+	case gnmipb.SubscriptionList_POLL:
+		if err := s.sendState(stream, list, paths, false); err != nil {
+			return err
+		}
+		for {
+			req, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if req.GetPoll() == nil {
+				return status.Errorf(codes.InvalidArgument, "expected poll request")
+			}
+			if err := s.sendState(stream, list, paths, false); err != nil {
+				return err
+			}
+		}
+	default:
+		w := &watcher{
+			prefix:  list.GetPrefix(),
+			paths:   paths,
+			updates: make(chan *gnmipb.Notification, 100),
+			done:    make(chan struct{}),
+		}
+		s.mu.Lock()
+		s.watchers[w] = struct{}{}
+		s.mu.Unlock()
+		defer func() {
+			s.mu.Lock()
+			delete(s.watchers, w)
+			s.mu.Unlock()
+			close(w.done)
+		}()
+
+		if err := s.sendState(stream, list, paths, list.GetUpdatesOnly()); err != nil {
+			return err
+		}
+		for {
+			select {
+			case n := <-w.updates:
+				if err := stream.Send(&gnmipb.SubscribeResponse{
+					Response: &gnmipb.SubscribeResponse_Update{Update: n},
+				}); err != nil {
+					return err
+				}
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+	}
+}
+
+// sendState sends the current value of every subscribed path followed by a
+// sync response (only the sync response if updatesOnly is set)
+func (s *Server) sendState(stream gnmipb.GNMI_SubscribeServer, list *gnmipb.SubscriptionList, paths []*gnmipb.Path, updatesOnly bool) error {
+	var notifications []*gnmipb.Notification
+	if !updatesOnly {
+		s.mu.Lock()
+		now := time.Now().UnixNano()
+		for _, p := range paths {
+			v, ok := s.data.get(joinElems(list.GetPrefix(), p))
+			if !ok {
+				continue
+			}
+			tv, err := encodeTypedValue(v, list.GetEncoding())
+			if err != nil {
+				s.mu.Unlock()
+				return status.Errorf(codes.Internal, "gnmitest: %v", err)
+			}
+			notifications = append(notifications, &gnmipb.Notification{
+				Timestamp: now,
+				Prefix:    list.GetPrefix(),
+				Update:    []*gnmipb.Update{{Path: p, Val: tv}},
+			})
+		}
+		s.mu.Unlock()
+	}
+
+	for _, n := range notifications {
+		if err := stream.Send(&gnmipb.SubscribeResponse{
+			Response: &gnmipb.SubscribeResponse_Update{Update: n},
+		}); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	exts := s.exts[RPCSubscribe]
+	s.mu.Unlock()
+
+	return stream.Send(&gnmipb.SubscribeResponse{
+		Response:  &gnmipb.SubscribeResponse_SyncResponse{SyncResponse: true},
+		Extension: exts,
+	})
+}
+
+// notifyLocked queues notifications for STREAM subscriptions whose paths
+// overlap any of the changed paths
+//
+// PRECONDITION: Caller must hold s.mu.
+func (s *Server) notifyLocked(changed []*gnmipb.Path) {
+	now := time.Now().UnixNano()
+	for w := range s.watchers {
+		for _, p := range w.paths {
+			elems := joinElems(w.prefix, p)
+			if !overlapsAny(elems, changed) {
+				continue
+			}
+
+			n := &gnmipb.Notification{Timestamp: now, Prefix: w.prefix}
+			if v, ok := s.data.get(elems); ok {
+				tv, err := encodeTypedValue(v, gnmipb.Encoding_JSON_IETF)
+				if err != nil {
+					continue
+				}
+				n.Update = []*gnmipb.Update{{Path: p, Val: tv}}
+			} else {
+				n.Delete = []*gnmipb.Path{p}
+			}
+
+			select {
+			case w.updates <- n:
+			case <-w.done:
+			default:
+				// Slow consumer: drop the update rather than block Set
+			}
+		}
+	}
+}
+
+// intercept applies authentication, latency, and injected failures to an RPC
+//
+// Returns the error the RPC must fail with, or nil to proceed.
+func (s *Server) intercept(ctx context.Context, rpc RPC) error {
+	s.mu.Lock()
+	s.calls[rpc]++
+	latency := s.latency[rpc]
+	var injected error
+	if queue := s.failures[rpc]; len(queue) > 0 {
+		injected = queue[0]
+		s.failures[rpc] = queue[1:]
+	}
+	username, password, token := s.username, s.password, s.token
+	s.mu.Unlock()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if username != "" || password != "" {
+		if first(md.Get("username")) != username || first(md.Get("password")) != password {
+			return status.Errorf(codes.Unauthenticated, "invalid credentials")
+		}
+	}
+	if token != "" && first(md.Get("authorization")) != "Bearer "+token {
+		return status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+
+	return injected
+}
+
+// checkEncoding rejects encodings the fake server cannot produce
+func checkEncoding(encoding gnmipb.Encoding) error {
+	if encoding != gnmipb.Encoding_JSON && encoding != gnmipb.Encoding_JSON_IETF {
+		return status.Errorf(codes.Unimplemented, "gnmitest: encoding %s not supported", encoding)
+	}
+	return nil
+}
+
+// joinElems concatenates the elements of a prefix and a path
+func joinElems(prefix, path *gnmipb.Path) []*gnmipb.PathElem {
+	elems := make([]*gnmipb.PathElem, 0, len(prefix.GetElem())+len(path.GetElem()))
+	elems = append(elems, prefix.GetElem()...)
+	return append(elems, path.GetElem()...)
+}
+
+// origin returns the origin of path, falling back to the origin of prefix
+func origin(prefix, path *gnmipb.Path) string {
+	if o := path.GetOrigin(); o != "" {
+		return o
+	}
+	return prefix.GetOrigin()
+}
+
+// hasWildcard reports whether any element name or key value is a wildcard
+func hasWildcard(elems []*gnmipb.PathElem) bool {
+	for _, elem := range elems {
+		if elem.GetName() == "*" || elem.GetName() == "..." {
+			return true
+		}
+		for _, v := range elem.GetKey() {
+			if v == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// overlapsAny reports whether elems is a prefix of, or prefixed by, any changed path
+func overlapsAny(elems []*gnmipb.PathElem, changed []*gnmipb.Path) bool {
+	for _, c := range changed {
+		n := len(elems)
+		if len(c.GetElem()) < n {
+			n = len(c.GetElem())
+		}
+		match := true
+		for i := 0; i < n; i++ {
+			if !proto.Equal(elems[i], c.GetElem()[i]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// first returns the first value or an empty string
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// cloneAll returns deep copies of the given protobuf messages
+func cloneAll[T proto.Message](msgs []T) []T {
+	out := make([]T, 0, len(msgs))
+	for _, m := range msgs {
+		out = append(out, proto.Clone(m).(T))
+	}
+	return out
+}
+
+// trackingListener records accepted connections so they can be dropped
+type trackingListener struct {
+	net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// Accept waits for and returns the next connection, tracking it until closed
+func (l *trackingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	tc := &trackedConn{Conn: conn, listener: l}
+	l.mu.Lock()
+	l.conns[tc] = struct{}{}
+	l.mu.Unlock()
+	return tc, nil
+}
+
+// closeConns closes all tracked connections
+func (l *trackingListener) closeConns() {
+	l.mu.Lock()
+	conns := make([]net.Conn, 0, len(l.conns))
+	for c := range l.conns {
+		conns = append(conns, c)
+	}
+	l.mu.Unlock()
+
+	for _, c := range conns {
+		_ = c.Close() //nolint:errcheck // Connection may already be closed
+	}
+}
+
+// trackedConn removes itself from the listener when closed
+type trackedConn struct {
+	net.Conn
+	listener *trackingListener
+}
+
+// Close closes the connection and stops tracking it
+func (c *trackedConn) Close() error {
+	c.listener.mu.Lock()
+	delete(c.listener.conns, c)
+	c.listener.mu.Unlock()
+	return c.Conn.Close()
+}
@@ -0,0 +1,324 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmitest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	gnmipath "github.com/openconfig/gnmic/pkg/api/path"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// newTestServer starts a server and returns a raw gNMI client connected to it
+func newTestServer(t *testing.T, opts ...func(*Server)) (*Server, gnmipb.GNMIClient) {
+	t.Helper()
+
+	srv, err := NewServer(opts...)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	conn, err := grpc.NewClient(srv.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return srv, gnmipb.NewGNMIClient(conn)
+}
+
+func mustPath(t *testing.T, p string) *gnmipb.Path {
+	t.Helper()
+	path, err := gnmipath.ParsePath(p)
+	if err != nil {
+		t.Fatalf("ParsePath(%q) error = %v", p, err)
+	}
+	return path
+}
+
+func jsonUpdate(t *testing.T, p, value string) *gnmipb.Update {
+	return &gnmipb.Update{
+		Path: mustPath(t, p),
+		Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(value)}},
+	}
+}
+
+// TestTree tests update, replace, and delete on the in-memory JSON tree
+func TestTree(t *testing.T) {
+	tr := newTree()
+	intf := mustPath(t, "/interfaces/interface[name=eth0]/config").GetElem()
+
+	if err := tr.update(intf, map[string]any{"mtu": 1500, "description": "uplink"}); err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+	if err := tr.update(intf, map[string]any{"mtu": 9000}); err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+
+	v, ok := tr.get(intf)
+	if !ok {
+		t.Fatalf("get() path not found")
+	}
+	cfg := v.(map[string]any)
+	if cfg["mtu"] != 9000 || cfg["description"] != "uplink" {
+		t.Errorf("update() did not merge: %v", cfg)
+	}
+
+	entry, ok := tr.get(mustPath(t, "/interfaces/interface[name=eth0]").GetElem())
+	if !ok || entry.(map[string]any)["name"] != "eth0" {
+		t.Errorf("list entry missing key leaf: %v", entry)
+	}
+
+	if err := tr.replace(intf, map[string]any{"mtu": 1400}); err != nil {
+		t.Fatalf("replace() error = %v", err)
+	}
+	v, _ = tr.get(intf)
+	if _, ok := v.(map[string]any)["description"]; ok {
+		t.Errorf("replace() kept old member: %v", v)
+	}
+
+	tr.delete(mustPath(t, "/interfaces/interface[name=eth0]").GetElem())
+	if _, ok := tr.get(intf); ok {
+		t.Errorf("delete() left path in tree")
+	}
+
+	// Deleting a missing path is not an error
+	tr.delete(mustPath(t, "/system/config").GetElem())
+}
+
+// TestTreeModuleQualified tests that unqualified path elements match module-qualified members
+func TestTreeModuleQualified(t *testing.T) {
+	tr := newTree()
+	if err := tr.update(nil, map[string]any{"openconfig-system:system": map[string]any{"hostname": "r1"}}); err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+
+	v, ok := tr.get(mustPath(t, "/system/hostname").GetElem())
+	if !ok || v != "r1" {
+		t.Errorf("get() = %v, %v, want r1", v, ok)
+	}
+}
+
+// TestServerGetSet tests Get and Set against the in-memory tree
+func TestServerGetSet(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+
+	if err := srv.Load("/system/config", `{"hostname": "router1"}`); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	_, err := client.Set(ctx, &gnmipb.SetRequest{
+		Update: []*gnmipb.Update{jsonUpdate(t, "/system/config/domain-name", `"example.com"`)},
+	})
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	got, ok := srv.JSON("/system/config")
+	if !ok || got != `{"domain-name":"example.com","hostname":"router1"}` {
+		t.Errorf("JSON() = %s, want merged config", got)
+	}
+
+	resp, err := client.Get(ctx, &gnmipb.GetRequest{
+		Path:     []*gnmipb.Path{mustPath(t, "/system/config/hostname")},
+		Encoding: gnmipb.Encoding_JSON_IETF,
+	})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if val := string(resp.GetNotification()[0].GetUpdate()[0].GetVal().GetJsonIetfVal()); val != `"router1"` {
+		t.Errorf("Get() value = %s, want \"router1\"", val)
+	}
+
+	_, err = client.Get(ctx, &gnmipb.GetRequest{Path: []*gnmipb.Path{mustPath(t, "/missing")}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Get() missing path error = %v, want NotFound", err)
+	}
+
+	if n := len(srv.SetRequests()); n != 1 {
+		t.Errorf("SetRequests() = %d, want 1", n)
+	}
+}
+
+// TestServerSetAtomic tests that a failing Set leaves the tree unchanged
+func TestServerSetAtomic(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	_, err := client.Set(context.Background(), &gnmipb.SetRequest{
+		Update: []*gnmipb.Update{
+			jsonUpdate(t, "/system/config/hostname", `"router1"`),
+			jsonUpdate(t, "/interfaces/interface[name=eth0]", `"not an object"`),
+		},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Set() error = %v, want InvalidArgument", err)
+	}
+	if _, ok := srv.JSON("/system/config/hostname"); ok {
+		t.Errorf("failed Set() modified the tree")
+	}
+}
+
+// TestServerUnionReplace tests union_replace and CLI origin values
+func TestServerUnionReplace(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+
+	if err := srv.Load("/system/config", `{"hostname": "router1", "domain-name": "example.com"}`); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	cliPath := &gnmipb.Path{Origin: "cli"}
+	ascii := func(text string) *gnmipb.TypedValue {
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_AsciiVal{AsciiVal: text}}
+	}
+	resp, err := client.Set(ctx, &gnmipb.SetRequest{
+		UnionReplace: []*gnmipb.Update{
+			jsonUpdate(t, "/system/config", `{"hostname": "router2"}`),
+			{Path: cliPath, Val: ascii("router bgp 65000\n")},
+		},
+		Update: []*gnmipb.Update{{Path: cliPath, Val: ascii("ntp server 10.0.0.1\n")}},
+	})
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if op := resp.GetResponse()[0].GetOp(); op != gnmipb.UpdateResult_UNION_REPLACE {
+		t.Errorf("response op = %v, want UNION_REPLACE", op)
+	}
+	if got, _ := srv.JSON("/system/config"); got != `{"hostname":"router2"}` {
+		t.Errorf("JSON() = %s, want replaced config", got)
+	}
+	if got := srv.CLIConfig(); got != "router bgp 65000\nntp server 10.0.0.1\n" {
+		t.Errorf("CLIConfig() = %q", got)
+	}
+
+	_, err = client.Set(ctx, &gnmipb.SetRequest{
+		UnionReplace: []*gnmipb.Update{{Path: cliPath, Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonVal{JsonVal: []byte(`"x"`)}}}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Set() with JSON CLI value error = %v, want InvalidArgument", err)
+	}
+}
+
+// TestServerFaultInjection tests injected failures, latency, and credentials
+func TestServerFaultInjection(t *testing.T) {
+	t.Run("fail next", func(t *testing.T) {
+		srv, client := newTestServer(t)
+		srv.FailNext(RPCCapabilities, codes.Unavailable, 2)
+
+		for i := 0; i < 2; i++ {
+			if _, err := client.Capabilities(context.Background(), &gnmipb.CapabilityRequest{}); status.Code(err) != codes.Unavailable {
+				t.Errorf("call %d error = %v, want Unavailable", i, err)
+			}
+		}
+		if _, err := client.Capabilities(context.Background(), &gnmipb.CapabilityRequest{}); err != nil {
+			t.Errorf("call after injected failures error = %v", err)
+		}
+		if n := srv.Calls(RPCCapabilities); n != 3 {
+			t.Errorf("Calls() = %d, want 3", n)
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		srv, client := newTestServer(t)
+		srv.SetLatency(RPCCapabilities, time.Second)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := client.Capabilities(ctx, &gnmipb.CapabilityRequest{}); status.Code(err) != codes.DeadlineExceeded {
+			t.Errorf("Capabilities() error = %v, want DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		srv, client := newTestServer(t, Credentials("admin", "secret"))
+
+		if _, err := client.Capabilities(context.Background(), &gnmipb.CapabilityRequest{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Capabilities() without credentials error = %v, want Unauthenticated", err)
+		}
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "username", "admin", "password", "secret")
+		if _, err := client.Capabilities(ctx, &gnmipb.CapabilityRequest{}); err != nil {
+			t.Errorf("Capabilities() with credentials error = %v", err)
+		}
+
+		srv.SetCredentials("admin", "rotated")
+		if _, err := client.Capabilities(ctx, &gnmipb.CapabilityRequest{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Capabilities() with rotated credentials error = %v, want Unauthenticated", err)
+		}
+	})
+
+	t.Run("token", func(t *testing.T) {
+		srv, client := newTestServer(t, Token("t1"))
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer t1")
+		if _, err := client.Capabilities(ctx, &gnmipb.CapabilityRequest{}); err != nil {
+			t.Errorf("Capabilities() with token error = %v", err)
+		}
+
+		srv.SetToken("t2")
+		if _, err := client.Capabilities(ctx, &gnmipb.CapabilityRequest{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Capabilities() with rotated token error = %v, want Unauthenticated", err)
+		}
+	})
+}
+
+// TestServerSubscribe tests STREAM subscriptions and dropped connections
+func TestServerSubscribe(t *testing.T) {
+	srv, client := newTestServer(t)
+	if err := srv.Load("/system/config", `{"hostname": "router1"}`); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	stream, err := client.Subscribe(context.Background())
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	err = stream.Send(&gnmipb.SubscribeRequest{
+		Request: &gnmipb.SubscribeRequest_Subscribe{Subscribe: &gnmipb.SubscriptionList{
+			Mode:         gnmipb.SubscriptionList_STREAM,
+			Encoding:     gnmipb.Encoding_JSON_IETF,
+			Subscription: []*gnmipb.Subscription{{Path: mustPath(t, "/system/config/hostname")}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	recv := func() *gnmipb.SubscribeResponse {
+		t.Helper()
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		return resp
+	}
+
+	if val := string(recv().GetUpdate().GetUpdate()[0].GetVal().GetJsonIetfVal()); val != `"router1"` {
+		t.Errorf("initial value = %s, want \"router1\"", val)
+	}
+	if !recv().GetSyncResponse() {
+		t.Errorf("expected sync response")
+	}
+
+	if err := srv.Load("/system/config", `{"hostname": "router2"}`); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if val := string(recv().GetUpdate().GetUpdate()[0].GetVal().GetJsonIetfVal()); val != `"router2"` {
+		t.Errorf("changed value = %s, want \"router2\"", val)
+	}
+
+	srv.DropConnections()
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Recv() after DropConnections() error = %v, want Unavailable", err)
+	}
+}
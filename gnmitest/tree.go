@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// tree is an in-memory JSON document addressed by gNMI path elements
+//
+// Containers are JSON objects and lists are JSON arrays of objects that carry
+// their key leafs (RFC 7951 style). Member names may be module-qualified
+// ("openconfig-system:system"); a path element without module prefix matches
+// the qualified member as well.
+//
+// Not thread-safe: callers must synchronize access.
+type tree struct {
+	root map[string]any
+}
+
+// newTree creates an empty tree
+func newTree() *tree {
+	return &tree{root: map[string]any{}}
+}
+
+// clone returns a deep copy of the tree (used for atomic Set transactions)
+func (t *tree) clone() *tree {
+	return &tree{root: deepCopy(t.root).(map[string]any)}
+}
+
+// get returns the node at the given path
+//
+// Returns false if any element along the path does not exist.
+func (t *tree) get(elems []*gnmipb.PathElem) (any, bool) {
+	var node any = t.root
+	for _, elem := range elems {
+		obj, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		name, ok := memberName(obj, elem.GetName())
+		if !ok {
+			return nil, false
+		}
+		node = obj[name]
+
+		if len(elem.GetKey()) > 0 {
+			list, ok := node.([]any)
+			if !ok {
+				return nil, false
+			}
+			idx := findEntry(list, elem.GetKey())
+			if idx < 0 {
+				return nil, false
+			}
+			node = list[idx]
+		}
+	}
+	return node, true
+}
+
+// update merges value into the node at the given path, creating missing
+// containers and list entries
+//
+// Objects are merged recursively; all other values replace the existing node.
+func (t *tree) update(elems []*gnmipb.PathElem, value any) error {
+	return t.set(elems, value, true)
+}
+
+// replace replaces the node at the given path with value, creating missing
+// containers and list entries
+func (t *tree) replace(elems []*gnmipb.PathElem, value any) error {
+	return t.set(elems, value, false)
+}
+
+// set writes value at the given path, merging objects if merge is true
+func (t *tree) set(elems []*gnmipb.PathElem, value any, merge bool) error {
+	if len(elems) == 0 {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("root value must be a JSON object")
+		}
+		if merge {
+			mergeObjects(t.root, obj)
+		} else {
+			t.root = obj
+		}
+		return nil
+	}
+
+	parent := t.root
+	for i, elem := range elems {
+		last := i == len(elems)-1
+		name, ok := memberName(parent, elem.GetName())
+		if !ok {
+			name = elem.GetName()
+		}
+
+		if len(elem.GetKey()) == 0 {
+			if last {
+				parent[name] = mergeValue(parent[name], value, merge)
+				return nil
+			}
+			child, ok := parent[name].(map[string]any)
+			if !ok {
+				if parent[name] != nil {
+					return fmt.Errorf("%s is not a container", elem.GetName())
+				}
+				child = map[string]any{}
+				parent[name] = child
+			}
+			parent = child
+			continue
+		}
+
+		// List entry: locate or create the entry carrying the keys
+		list, ok := parent[name].([]any)
+		if !ok && parent[name] != nil {
+			return fmt.Errorf("%s is not a list", elem.GetName())
+		}
+		idx := findEntry(list, elem.GetKey())
+		if idx < 0 {
+			entry := map[string]any{}
+			for k, v := range elem.GetKey() {
+				entry[k] = v
+			}
+			list = append(list, entry)
+			idx = len(list) - 1
+			parent[name] = list
+		}
+
+		if last {
+			obj, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("list entry %s value must be a JSON object", elem.GetName())
+			}
+			entry := list[idx].(map[string]any)
+			if !merge {
+				entry = map[string]any{}
+				for k := range elem.GetKey() {
+					entry[k] = list[idx].(map[string]any)[k]
+				}
+				list[idx] = entry
+			}
+			mergeObjects(entry, obj)
+			return nil
+		}
+		parent = list[idx].(map[string]any)
+	}
+
+	return nil
+}
+
+// delete removes the node at the given path
+//
+// Deleting a path that does not exist is not an error (gNMI semantics).
+// Deleting the root path clears the whole tree.
+func (t *tree) delete(elems []*gnmipb.PathElem) {
+	if len(elems) == 0 {
+		t.root = map[string]any{}
+		return
+	}
+
+	parentElems, last := elems[:len(elems)-1], elems[len(elems)-1]
+	node, ok := t.get(parentElems)
+	if !ok {
+		return
+	}
+	parent, ok := node.(map[string]any)
+	if !ok {
+		return
+	}
+	name, ok := memberName(parent, last.GetName())
+	if !ok {
+		return
+	}
+
+	if len(last.GetKey()) == 0 {
+		delete(parent, name)
+		return
+	}
+
+	list, ok := parent[name].([]any)
+	if !ok {
+		return
+	}
+	if idx := findEntry(list, last.GetKey()); idx >= 0 {
+		parent[name] = append(list[:idx], list[idx+1:]...)
+	}
+}
+
+// memberName resolves a path element name to the member name used in obj
+//
+// Exact matches win; otherwise a module-qualified member whose local name
+// matches is returned.
+func memberName(obj map[string]any, name string) (string, bool) {
+	if _, ok := obj[name]; ok {
+		return name, true
+	}
+	local := name
+	if idx := strings.IndexByte(name, ':'); idx >= 0 {
+		local = name[idx+1:]
+	}
+	for member := range obj {
+		if idx := strings.IndexByte(member, ':'); idx >= 0 && member[idx+1:] == local {
+			return member, true
+		}
+	}
+	return "", false
+}
+
+// findEntry returns the index of the list entry matching all keys, or -1
+//
+// Key values are compared in their string form since gNMI keys are strings.
+func findEntry(list []any, keys map[string]string) int {
+	for i, item := range list {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		match := true
+		for k, v := range keys {
+			if fmt.Sprint(entry[k]) != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeValue merges value into existing if both are objects and merge is set
+func mergeValue(existing, value any, merge bool) any {
+	dst, ok := existing.(map[string]any)
+	src, isObj := value.(map[string]any)
+	if merge && ok && isObj {
+		mergeObjects(dst, src)
+		return dst
+	}
+	return value
+}
+
+// mergeObjects recursively merges src into dst
+func mergeObjects(dst, src map[string]any) {
+	for k, v := range src {
+		dst[k] = mergeValue(dst[k], v, true)
+	}
+}
+
+// deepCopy returns a deep copy of a decoded JSON value
+func deepCopy(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			out[k] = deepCopy(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = deepCopy(val)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// decodeJSON decodes a JSON document, preserving numbers as json.Number
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// decodeTypedValue converts a gNMI TypedValue into a JSON tree value
+//
+// Returns an error for value types the fake server does not store.
+func decodeTypedValue(tv *gnmipb.TypedValue) (any, error) {
+	switch v := tv.GetValue().(type) {
+	case *gnmipb.TypedValue_JsonIetfVal:
+		return decodeJSON(v.JsonIetfVal)
+	case *gnmipb.TypedValue_JsonVal:
+		return decodeJSON(v.JsonVal)
+	case *gnmipb.TypedValue_StringVal:
+		return v.StringVal, nil
+	case *gnmipb.TypedValue_AsciiVal:
+		return v.AsciiVal, nil
+	case *gnmipb.TypedValue_IntVal:
+		return json.Number(fmt.Sprint(v.IntVal)), nil
+	case *gnmipb.TypedValue_UintVal:
+		return json.Number(fmt.Sprint(v.UintVal)), nil
+	case *gnmipb.TypedValue_BoolVal:
+		return v.BoolVal, nil
+	case *gnmipb.TypedValue_DoubleVal:
+		return json.Number(fmt.Sprint(v.DoubleVal)), nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+// encodeTypedValue converts a JSON tree value into a gNMI TypedValue
+//
+// Encoding JSON yields json_val, everything else json_ietf_val.
+func encodeTypedValue(v any, encoding gnmipb.Encoding) (*gnmipb.TypedValue, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if encoding == gnmipb.Encoding_JSON {
+		return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonVal{JsonVal: data}}, nil
+	}
+	return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: data}}, nil
+}
@@ -0,0 +1,519 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newIntegrationClient starts a fake gNMI server and returns a client connected to it
+//
+// Backoff delays are shortened so retry tests run quickly.
+func newIntegrationClient(t *testing.T, opts ...func(*Client)) (*Client, *gnmitest.Server) {
+	t.Helper()
+
+	srv, err := gnmitest.NewServer(gnmitest.Credentials("admin", "secret"))
+	if err != nil {
+		t.Fatalf("gnmitest.NewServer() error = %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	if err := srv.Load("/system/config", `{"hostname": "router1"}`); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	defaults := []func(*Client){
+		Username("admin"),
+		Password("secret"),
+		TLS(false),
+		BackoffMinDelay(time.Millisecond),
+		BackoffMaxDelay(10 * time.Millisecond),
+		OperationTimeout(2 * time.Second),
+	}
+	client, err := NewClient(srv.Addr(), append(defaults, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client, srv
+}
+
+// TestIntegration_GetSet tests a Set followed by a Get against the fake server
+func TestIntegration_GetSet(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+
+	_, err := client.Set(ctx, []SetOperation{
+		Update("/system/config", `{"domain-name": "example.com"}`),
+	})
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, _ := srv.JSON("/system/config/domain-name"); got != `"example.com"` {
+		t.Errorf("server domain-name = %s, want \"example.com\"", got)
+	}
+
+	res, err := client.Get(ctx, []string{"/system/config/hostname"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := string(res.Notifications[0].GetUpdate()[0].GetVal().GetJsonIetfVal()); got != `"router1"` {
+		t.Errorf("Get() value = %s, want \"router1\"", got)
+	}
+
+	caps, err := client.Capabilities(ctx)
+	if err != nil {
+		t.Fatalf("Capabilities() error = %v", err)
+	}
+	if caps.Version != gnmitest.DefaultGNMIVersion {
+		t.Errorf("Capabilities() version = %s, want %s", caps.Version, gnmitest.DefaultGNMIVersion)
+	}
+}
+
+// TestIntegration_Paths tests structured paths with escaped key values end to end
+func TestIntegration_Paths(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+
+	entry := NewPath().Elem("acl").Elem("entry", "name", "deny [all]/0")
+	_, err := client.Set(ctx, []SetOperation{UpdatePath(entry, `{"action": "drop"}`)})
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	req := srv.SetRequests()[0]
+	if got := req.GetUpdate()[0].GetPath().GetElem()[1].GetKey()["name"]; got != "deny [all]/0" {
+		t.Errorf("server received key %q, want %q", got, "deny [all]/0")
+	}
+
+	res, err := client.GetPaths(ctx, []Path{entry.Elem("action")})
+	if err != nil {
+		t.Fatalf("GetPaths() error = %v", err)
+	}
+	if got := string(res.Notifications[0].GetUpdate()[0].GetVal().GetJsonIetfVal()); got != `"drop"` {
+		t.Errorf("GetPaths() value = %s, want \"drop\"", got)
+	}
+
+	if _, err := client.GetPaths(ctx, []Path{NewPath().Elem("")}); err == nil {
+		t.Errorf("GetPaths() with invalid Path succeeded")
+	}
+}
+
+// TestIntegration_GetDataType tests that the data type is sent in the GetRequest
+func TestIntegration_GetDataType(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Get(ctx, []string{"/system/config"}, GetDataType(DataTypeConfig)); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := srv.GetRequests()[0].GetType(); got != gnmipb.GetRequest_CONFIG {
+		t.Errorf("GetRequest type = %v, want CONFIG", got)
+	}
+
+	_, err := client.Get(ctx, []string{"/system/config"}, GetDataType("running"))
+	if err == nil || !strings.Contains(err.Error(), "invalid data type") {
+		t.Errorf("Get() error = %v, want invalid data type", err)
+	}
+	if calls := srv.Calls(gnmitest.RPCGet); calls != 1 {
+		t.Errorf("server calls = %d, want 1", calls)
+	}
+}
+
+// TestIntegration_Prefix tests that prefix, origin, and target are sent for Get, Set, and Subscribe
+func TestIntegration_Prefix(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+	mods := []func(*Req){Prefix("/system/config"), Origin("openconfig"), Target("router1")}
+	wantPrefix := "openconfig:/system/config"
+
+	_, err := client.Set(ctx, []SetOperation{Update("/domain-name", `"example.com"`)}, mods...)
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, _ := srv.JSON("/system/config/domain-name"); got != `"example.com"` {
+		t.Errorf("server domain-name = %s, want \"example.com\"", got)
+	}
+
+	res, err := client.Get(ctx, []string{"/hostname"}, mods...)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := string(res.Notifications[0].GetUpdate()[0].GetVal().GetJsonIetfVal()); got != `"router1"` {
+		t.Errorf("Get() value = %s, want \"router1\"", got)
+	}
+
+	stream, err := client.Subscribe(ctx, []Subscription{TargetDefined("/hostname")},
+		append(mods, SubscribeMode(SubscribeModeOnce))...)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if sub := nextResponse(t, stream); sub.Notification.Updates[0].Path != "openconfig:/system/config/hostname" {
+		t.Errorf("Subscribe() update path = %s", sub.Notification.Updates[0].Path)
+	}
+
+	prefixes := []*gnmipb.Path{
+		srv.SetRequests()[0].GetPrefix(),
+		srv.GetRequests()[0].GetPrefix(),
+		srv.SubscribeRequests()[0].GetSubscribe().GetPrefix(),
+	}
+	for i, prefix := range prefixes {
+		if got := pathToString(prefix, nil); got != wantPrefix || prefix.GetTarget() != "router1" {
+			t.Errorf("request %d prefix = %v, want %s with target router1", i, prefix, wantPrefix)
+		}
+	}
+}
+
+// TestIntegration_MergedJSON tests that a merged Get document can be restored with Replace
+func TestIntegration_MergedJSON(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+
+	intfs := `{"interface": [{"name": "eth0", "config": {"mtu": 1500}}, {"name": "eth1", "config": {"mtu": 9000}}]}`
+	if err := srv.Load("/interfaces", intfs); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want, _ := srv.JSON("/interfaces")
+
+	res, err := client.Get(ctx, []string{"/interfaces"}, GetDataType(DataTypeConfig))
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	backup, err := res.MergedJSON()
+	if err != nil {
+		t.Fatalf("MergedJSON() error = %v", err)
+	}
+
+	if _, err := client.Set(ctx, []SetOperation{Delete("/interfaces")}); err != nil {
+		t.Fatalf("Set() delete error = %v", err)
+	}
+	if _, err := client.Set(ctx, []SetOperation{Replace("/interfaces", backup)}); err != nil {
+		t.Fatalf("Set() replace error = %v", err)
+	}
+	if got, _ := srv.JSON("/interfaces"); got != want {
+		t.Errorf("restored /interfaces = %s, want %s", got, want)
+	}
+}
+
+// TestIntegration_UnionReplace tests union_replace with OpenConfig and CLI configuration
+func TestIntegration_UnionReplace(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+
+	_, err := client.Set(ctx, []SetOperation{
+		UnionReplace("openconfig:/system/config", `{"hostname": "router2"}`),
+		UnionReplaceCLI("router bgp 65000\n"),
+		Update("cli:/", "ntp server 10.0.0.1\n", SetEncoding(EncodingASCII)),
+	})
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, _ := srv.JSON("/system/config"); got != `{"hostname":"router2"}` {
+		t.Errorf("server /system/config = %s, want replaced config", got)
+	}
+	if got := srv.CLIConfig(); got != "router bgp 65000\nntp server 10.0.0.1\n" {
+		t.Errorf("CLIConfig() = %q", got)
+	}
+
+	req := srv.SetRequests()[0]
+	if n := len(req.GetUnionReplace()); n != 2 {
+		t.Fatalf("union_replace = %d, want 2", n)
+	}
+	cli := req.GetUnionReplace()[1]
+	if cli.GetPath().GetOrigin() != OriginCLI || len(cli.GetPath().GetElem()) != 0 || cli.GetVal().GetAsciiVal() != "router bgp 65000\n" {
+		t.Errorf("cli union_replace = %v, want ascii_val at cli:/", cli)
+	}
+
+	// CLI constraints also apply to the request origin
+	_, err = client.Set(ctx, []SetOperation{UnionReplace("/", `{}`)}, Origin(OriginCLI))
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Set() with cli request origin error = %v, want ErrValidation", err)
+	}
+}
+
+// TestIntegration_Retry tests the retry loop of Get and Set with injected failures
+func TestIntegration_Retry(t *testing.T) {
+	tests := []struct {
+		name        string
+		rpc         gnmitest.RPC
+		code        codes.Code
+		failures    int
+		wantErr     bool
+		wantCalls   int
+		wantRetries int
+	}{
+		{
+			name:      "get transient error recovers",
+			rpc:       gnmitest.RPCGet,
+			code:      codes.ResourceExhausted,
+			failures:  2,
+			wantCalls: 3,
+		},
+		{
+			name:      "get transport error reconnects",
+			rpc:       gnmitest.RPCGet,
+			code:      codes.Unavailable,
+			failures:  1,
+			wantCalls: 2,
+		},
+		{
+			name:        "get retries exhausted",
+			rpc:         gnmitest.RPCGet,
+			code:        codes.Aborted,
+			failures:    10,
+			wantErr:     true,
+			wantCalls:   DefaultMaxRetries + 1,
+			wantRetries: DefaultMaxRetries,
+		},
+		{
+			name:      "get permanent error not retried",
+			rpc:       gnmitest.RPCGet,
+			code:      codes.InvalidArgument,
+			failures:  1,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "set transient error recovers",
+			rpc:       gnmitest.RPCSet,
+			code:      codes.Aborted,
+			failures:  1,
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, srv := newIntegrationClient(t)
+			ctx := context.Background()
+
+			// Establish the connection before injecting failures
+			if err := client.Ping(ctx); err != nil {
+				t.Fatalf("Ping() error = %v", err)
+			}
+			srv.FailNext(tt.rpc, tt.code, tt.failures)
+
+			var err error
+			if tt.rpc == gnmitest.RPCGet {
+				_, err = client.Get(ctx, []string{"/system/config"})
+			} else {
+				_, err = client.Set(ctx, []SetOperation{Update("/system/config", `{"hostname": "router2"}`)})
+			}
+
+			if tt.wantErr {
+				if status.Code(err) != tt.code {
+					t.Errorf("error = %v, want wrapped %v", err, tt.code)
+				}
+				var gnmiErr *GnmiError
+				if !errors.As(err, &gnmiErr) {
+					t.Fatalf("error = %T, want *GnmiError", err)
+				}
+				if gnmiErr.Code != tt.code || gnmiErr.Retries != tt.wantRetries || gnmiErr.IsTransient != (tt.wantRetries > 0) {
+					t.Errorf("GnmiError = {Code: %v, Retries: %d, IsTransient: %v}, want {Code: %v, Retries: %d}",
+						gnmiErr.Code, gnmiErr.Retries, gnmiErr.IsTransient, tt.code, tt.wantRetries)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if calls := srv.Calls(tt.rpc); calls != tt.wantCalls {
+				t.Errorf("server calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// TestIntegration_Timeout tests that slow responses hit the request timeout and are retried
+func TestIntegration_Timeout(t *testing.T) {
+	client, srv := newIntegrationClient(t, MaxRetries(1))
+	srv.SetLatency(gnmitest.RPCGet, time.Second)
+
+	_, err := client.Get(context.Background(), []string{"/system/config"}, Timeout(50*time.Millisecond))
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Get() error = %v, want DeadlineExceeded", err)
+	}
+	if calls := srv.Calls(gnmitest.RPCGet); calls != 2 {
+		t.Errorf("server calls = %d, want 2", calls)
+	}
+}
+
+// TestIntegration_CanceledDuringBackoff tests that canceling the context interrupts the backoff delay
+func TestIntegration_CanceledDuringBackoff(t *testing.T) {
+	client, srv := newIntegrationClient(t, BackoffMinDelay(time.Minute), BackoffMaxDelay(2*time.Minute))
+	srv.FailNext(gnmitest.RPCGet, codes.Unavailable, 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Get(ctx, []string{"/system/config"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Get() returned after %v, want prompt return", elapsed)
+	}
+}
+
+// TestIntegration_Authentication tests that wrong credentials are rejected without retry
+func TestIntegration_Authentication(t *testing.T) {
+	client, srv := newIntegrationClient(t, Password("wrong"))
+
+	_, err := client.Get(context.Background(), []string{"/system/config"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Get() error = %v, want Unauthenticated", err)
+	}
+	if calls := srv.Calls(gnmitest.RPCGet); calls != 1 {
+		t.Errorf("server calls = %d, want 1", calls)
+	}
+}
+
+// TestIntegration_DroppedConnection tests that operations recover after the connection drops
+func TestIntegration_DroppedConnection(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Get(ctx, []string{"/system/config"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	srv.DropConnections()
+
+	if _, err := client.Get(ctx, []string{"/system/config"}); err != nil {
+		t.Errorf("Get() after dropped connection error = %v", err)
+	}
+}
+
+// TestIntegration_Subscribe tests ONCE, POLL, and STREAM subscriptions
+func TestIntegration_Subscribe(t *testing.T) {
+	t.Run("once", func(t *testing.T) {
+		client, _ := newIntegrationClient(t)
+
+		stream, err := client.Subscribe(context.Background(),
+			[]Subscription{TargetDefined("/system/config/hostname")},
+			SubscribeMode(SubscribeModeOnce))
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+
+		var got []SubscribeRes
+		for res := range stream.Responses() {
+			got = append(got, res)
+		}
+		if len(got) != 2 || got[0].Notification.Updates[0].Value != "router1" || !got[1].SyncResponse {
+			t.Errorf("responses = %+v, want value and sync", got)
+		}
+		if err := stream.Err(); err != nil {
+			t.Errorf("Err() = %v", err)
+		}
+	})
+
+	t.Run("poll", func(t *testing.T) {
+		client, _ := newIntegrationClient(t)
+		ctx := context.Background()
+
+		stream, err := client.Subscribe(ctx,
+			[]Subscription{TargetDefined("/system/config/hostname")},
+			SubscribeMode(SubscribeModePoll))
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		defer stream.Close() //nolint:errcheck // Close always returns nil
+
+		waitForSync(t, stream)
+		if err := stream.Poll(ctx); err != nil {
+			t.Fatalf("Poll() error = %v", err)
+		}
+		waitForSync(t, stream)
+	})
+
+	t.Run("stream with resubscribe", func(t *testing.T) {
+		client, srv := newIntegrationClient(t)
+		ctx := context.Background()
+
+		stream, err := client.Subscribe(ctx, []Subscription{OnChange("/system/config/hostname")})
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		defer stream.Close() //nolint:errcheck // Close always returns nil
+
+		waitForSync(t, stream)
+
+		if _, err := client.Set(ctx, []SetOperation{Update("/system/config", `{"hostname": "router2"}`)}); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		res := nextResponse(t, stream)
+		if len(res.Notification.Updates) != 1 || res.Notification.Updates[0].Value != "router2" {
+			t.Errorf("change notification = %+v, want router2", res.Notification)
+		}
+
+		srv.DropConnections()
+
+		res = nextResponse(t, stream)
+		if !res.Reconnected {
+			t.Fatalf("response after dropped connection = %+v, want Reconnected", res)
+		}
+		res = nextResponse(t, stream)
+		if len(res.Notification.Updates) != 1 || res.Notification.Updates[0].Value != "router2" {
+			t.Errorf("resent state = %+v, want router2", res.Notification)
+		}
+		if !nextResponse(t, stream).SyncResponse {
+			t.Errorf("expected sync response after resubscribe")
+		}
+		if calls := srv.Calls(gnmitest.RPCSubscribe); calls != 2 {
+			t.Errorf("server subscribe calls = %d, want 2", calls)
+		}
+	})
+
+	t.Run("stream without resubscribe", func(t *testing.T) {
+		client, srv := newIntegrationClient(t)
+
+		stream, err := client.Subscribe(context.Background(),
+			[]Subscription{OnChange("/system/config/hostname")},
+			Resubscribe(false))
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		waitForSync(t, stream)
+
+		srv.DropConnections()
+
+		for range stream.Responses() {
+		}
+		if err := stream.Err(); err == nil || !strings.Contains(err.Error(), "stream failed") {
+			t.Errorf("Err() = %v, want stream failure", err)
+		}
+	})
+}
+
+// nextResponse waits for the next response on a subscription
+func nextResponse(t *testing.T, stream *SubscribeStream) SubscribeRes {
+	t.Helper()
+	select {
+	case res, ok := <-stream.Responses():
+		if !ok {
+			t.Fatalf("stream ended: %v", stream.Err())
+		}
+		return res
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for response")
+	}
+	return SubscribeRes{}
+}
+
+// waitForSync discards responses until the next sync response
+func waitForSync(t *testing.T, stream *SubscribeStream) {
+	t.Helper()
+	for !nextResponse(t, stream).SyncResponse {
+	}
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LimiterStats reports queueing statistics of a client's request limiter
+//
+// Use them to tune RateLimit and MaxConcurrentRequests: a growing MaxWait or
+// TotalWait means operations are queueing on the client instead of being
+// rejected by the target.
+type LimiterStats struct {
+	// Requests is the number of operations admitted by the limiter
+	Requests uint64
+
+	// Delayed is the number of admitted operations that had to wait
+	Delayed uint64
+
+	// Canceled is the number of operations whose context ended while waiting
+	Canceled uint64
+
+	// TotalWait is the sum of queueing delays of all admitted operations
+	TotalWait time.Duration
+
+	// MaxWait is the longest queueing delay of an admitted operation
+	MaxWait time.Duration
+
+	// InFlight is the number of operations currently holding a request slot
+	InFlight int
+
+	// Waiting is the number of operations currently waiting for admission
+	Waiting int
+}
+
+// limiter throttles the operations of a client with a token bucket and a
+// cap on concurrent requests
+type limiter struct {
+	// rate is the number of requests per second (0 if not rate limited)
+	rate float64
+
+	// burst is the bucket size, the number of requests that can be sent at once
+	burst int
+
+	// maxConcurrent is the maximum number of requests in flight (0 if unlimited)
+	maxConcurrent int
+
+	// slots holds one entry per request in flight (nil if unlimited)
+	slots chan struct{}
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	requests  atomic.Uint64
+	delayed   atomic.Uint64
+	canceled  atomic.Uint64
+	totalWait atomic.Int64
+	maxWait   atomic.Int64
+	inflight  atomic.Int32
+	waiting   atomic.Int32
+}
+
+// RateLimit limits the client to requestsPerSecond operations with bursts of up to burst
+//
+// Get, Set, Capabilities, and Subscribe take a token from a token bucket
+// before sending their request and wait for one, respecting ctx, if the
+// bucket is empty. Retries within an operation do not take another token;
+// they are paced by the retry backoff. A burst below 1 is treated as 1.
+//
+// Use it for platforms that answer request bursts with ResourceExhausted.
+// Queueing delays are reported by Client.LimiterStats.
+//
+// Example:
+//
+//	// At most 10 requests per second, up to 5 at once
+//	client, err := gnmi.NewClient("192.168.1.1:57400", gnmi.RateLimit(10, 5))
+func RateLimit(requestsPerSecond float64, burst int) func(*Client) {
+	return func(c *Client) {
+		l := c.requestLimiter()
+		l.rate = requestsPerSecond
+		l.burst = max(burst, 1)
+		l.tokens = float64(l.burst)
+		l.last = time.Now()
+	}
+}
+
+// MaxConcurrentRequests limits the number of operations in flight at once
+//
+// Get, Set, and Capabilities hold a request slot until they return, including
+// retries. Subscribe holds a slot only while the stream is opened; open
+// subscriptions do not count against the limit. Operations wait for a free
+// slot, respecting ctx.
+//
+// Example:
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400", gnmi.MaxConcurrentRequests(4))
+func MaxConcurrentRequests(n int) func(*Client) {
+	return func(c *Client) {
+		l := c.requestLimiter()
+		l.maxConcurrent = n
+		l.slots = nil
+		if n > 0 {
+			l.slots = make(chan struct{}, n)
+		}
+	}
+}
+
+// requestLimiter returns the limiter of the client, creating it if needed
+func (c *Client) requestLimiter() *limiter {
+	if c.limiter == nil {
+		c.limiter = &limiter{}
+	}
+	return c.limiter
+}
+
+// validate checks the limiter configuration
+func (l *limiter) validate() error {
+	if l.rate < 0 || math.IsNaN(l.rate) || math.IsInf(l.rate, 0) {
+		return fmt.Errorf("rate limit must be non-negative, got: %v", l.rate)
+	}
+	if l.maxConcurrent < 0 {
+		return fmt.Errorf("max concurrent requests must be non-negative, got: %d", l.maxConcurrent)
+	}
+	return nil
+}
+
+// wait blocks until the operation may proceed or ctx ends
+//
+// Returns a func releasing the request slot, the queueing delay, and ctx.Err()
+// if ctx ended while waiting.
+func (l *limiter) wait(ctx context.Context) (func(), time.Duration, error) {
+	start := time.Now()
+	l.waiting.Add(1)
+	defer l.waiting.Add(-1)
+
+	release := func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			l.canceled.Add(1)
+			return nil, time.Since(start), ctx.Err()
+		}
+		l.inflight.Add(1)
+		release = func() {
+			l.inflight.Add(-1)
+			<-l.slots
+		}
+	}
+
+	if delay := l.reserve(time.Now()); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			l.unreserve()
+			release()
+			l.canceled.Add(1)
+			return nil, time.Since(start), ctx.Err()
+		}
+	}
+
+	waited := time.Since(start)
+	l.requests.Add(1)
+	if waited > time.Millisecond {
+		l.delayed.Add(1)
+	}
+	l.totalWait.Add(int64(waited))
+	for {
+		prev := l.maxWait.Load()
+		if int64(waited) <= prev || l.maxWait.CompareAndSwap(prev, int64(waited)) {
+			break
+		}
+	}
+	return release, waited, nil
+}
+
+// reserve takes a token from the bucket
+//
+// Returns the time until the token becomes available (0 if available now).
+func (l *limiter) reserve(now time.Time) time.Duration {
+	if l.rate == 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Refill the bucket for the time elapsed since the last reservation
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.tokens+elapsed.Seconds()*l.rate, float64(l.burst))
+		l.last = now
+	}
+
+	// A negative balance reserves tokens for callers already waiting
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// unreserve returns a token reserved by a caller that stopped waiting
+func (l *limiter) unreserve() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.tokens+1, float64(l.burst))
+}
+
+// stats returns a snapshot of the limiter statistics
+func (l *limiter) stats() LimiterStats {
+	return LimiterStats{
+		Requests:  l.requests.Load(),
+		Delayed:   l.delayed.Load(),
+		Canceled:  l.canceled.Load(),
+		TotalWait: time.Duration(l.totalWait.Load()),
+		MaxWait:   time.Duration(l.maxWait.Load()),
+		InFlight:  int(l.inflight.Load()),
+		Waiting:   int(l.waiting.Load()),
+	}
+}
+
+// LimiterStats returns the queueing statistics of the request limiter
+//
+// Returns zero LimiterStats if neither RateLimit nor MaxConcurrentRequests
+// is configured.
+//
+// Example:
+//
+//	stats := client.LimiterStats()
+//	fmt.Printf("delayed %d of %d requests, max wait %s\n",
+//	    stats.Delayed, stats.Requests, stats.MaxWait)
+func (c *Client) LimiterStats() LimiterStats {
+	if c.limiter == nil {
+		return LimiterStats{}
+	}
+	return c.limiter.stats()
+}
+
+// waitLimiter waits until the request limiter admits an operation
+//
+// Returns a func releasing the request slot (a no-op without limiter), or an
+// error wrapping ctx.Err() if ctx ended while waiting.
+func (c *Client) waitLimiter(ctx context.Context, operation string) (func(), error) {
+	if c.limiter == nil {
+		return func() {}, nil
+	}
+
+	release, waited, err := c.limiter.wait(ctx)
+	if err != nil {
+		c.logger.Debug(ctx, "gNMI request canceled while waiting for limiter",
+			"operation", operation,
+			"target", c.Target,
+			"waited", waited.String(),
+			"error", err.Error())
+		return nil, fmt.Errorf("waiting for request limiter: %w", err)
+	}
+	if c.metrics != nil {
+		c.metrics.LimiterWaited(ctx, c.Target, operation, waited)
+	}
+	if waited > time.Millisecond {
+		c.logger.Debug(ctx, "gNMI request delayed by limiter",
+			"operation", operation,
+			"target", c.Target,
+			"waited", waited.String())
+	}
+	return release, nil
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+)
+
+// TestLimiter_RateLimit tests token bucket pacing and cancellation
+func TestLimiter_RateLimit(t *testing.T) {
+	c := &Client{}
+	RateLimit(20, 2)(c)
+	l := c.limiter
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		release, _, err := l.wait(context.Background())
+		if err != nil {
+			t.Fatalf("wait() error = %v", err)
+		}
+		release()
+	}
+	// The burst of 2 passes immediately, the next 2 requests wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("4 requests took %v, want at least 100ms", elapsed)
+	}
+
+	stats := c.LimiterStats()
+	if stats.Requests != 4 || stats.Delayed != 2 || stats.MaxWait < 40*time.Millisecond {
+		t.Errorf("LimiterStats() = %+v, want 4 requests, 2 delayed", stats)
+	}
+
+	// A canceled wait returns its token to the bucket
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := l.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait() error = %v, want deadline exceeded", err)
+	}
+	if stats := c.LimiterStats(); stats.Canceled != 1 || stats.Waiting != 0 {
+		t.Errorf("LimiterStats() = %+v, want 1 canceled, 0 waiting", stats)
+	}
+	start = time.Now()
+	release, _, err := l.wait(context.Background())
+	if err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed > 60*time.Millisecond {
+		t.Errorf("wait() after cancellation took %v, want at most one token interval", elapsed)
+	}
+}
+
+// TestLimiter_Validation tests rejection of invalid limits
+func TestLimiter_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  func(*Client)
+	}{
+		{"negative rate", RateLimit(-1, 1)},
+		{"negative concurrency", MaxConcurrentRequests(-1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClient("device:57400", tt.opt); err == nil {
+				t.Errorf("NewClient() expected error")
+			}
+		})
+	}
+
+	if stats := (&Client{}).LimiterStats(); stats != (LimiterStats{}) {
+		t.Errorf("LimiterStats() without limiter = %+v, want zero", stats)
+	}
+}
+
+// TestIntegration_MaxConcurrentRequests tests the concurrency cap against the fake server
+func TestIntegration_MaxConcurrentRequests(t *testing.T) {
+	client, srv := newIntegrationClient(t, MaxConcurrentRequests(1))
+	ctx := context.Background()
+	paths := []string{"/system/config"}
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	srv.SetLatency(gnmitest.RPCGet, 50*time.Millisecond)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Get(ctx, paths)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Get() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("3 Gets took %v, want them serialized (at least 150ms)", elapsed)
+	}
+
+	stats := client.LimiterStats()
+	if stats.Requests != 4 || stats.Delayed < 2 || stats.InFlight != 0 {
+		t.Errorf("LimiterStats() = %+v, want 4 requests, 2 delayed, none in flight", stats)
+	}
+
+	t.Run("context ends while waiting", func(t *testing.T) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = client.Get(ctx, paths)
+		}()
+		if !waitFor(t, time.Second, func() bool { return client.LimiterStats().InFlight == 1 }) {
+			t.Fatalf("Get() did not take the request slot")
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := client.Get(waitCtx, paths)
+		var gnmiErr *GnmiError
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.As(err, &gnmiErr) {
+			t.Errorf("Get() error = %v, want GnmiError matching deadline exceeded", err)
+		}
+		<-done
+	})
+
+	t.Run("subscriptions do not hold a slot", func(t *testing.T) {
+		stream, err := client.Subscribe(testContext(t), []Subscription{OnChange("/system/config/hostname")})
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		defer stream.Close()
+		waitForSync(t, stream)
+
+		if _, err := client.Get(ctx, paths); err != nil {
+			t.Errorf("Get() with open subscription error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,379 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultMaxParallel is the default number of targets a Manager operates on concurrently
+const DefaultMaxParallel = 10
+
+// Manager owns a set of Clients keyed by name and runs operations on all of them
+//
+// Each fan-out operation (GetAll, SetAll, SubscribeAll) calls the matching
+// Client method on every target, with at most MaxParallel targets in flight
+// at a time. Per-target retries, timeouts, and logging are those of the
+// individual Clients, so options passed via ClientOptions or Add apply
+// unchanged.
+//
+// Thread-safe: targets may be added and removed while operations run;
+// an operation works on the targets present when it starts.
+type Manager struct {
+	// MaxParallel is the maximum number of targets operated on concurrently
+	MaxParallel int
+
+	// clientOpts are applied to every Client created by Add
+	clientOpts []func(*Client)
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// Report collects the per-target outcome of a Manager fan-out operation
+type Report[T any] struct {
+	// Results contains the result of every target that succeeded, keyed by name
+	Results map[string]T
+
+	// Errors contains the error of every target that failed, keyed by name
+	Errors map[string]error
+}
+
+// MaxParallel sets the maximum number of targets operated on concurrently (default: 10)
+func MaxParallel(n int) func(*Manager) {
+	return func(m *Manager) {
+		m.MaxParallel = n
+	}
+}
+
+// ClientOptions sets options applied to every Client created by Manager.Add
+//
+// Options passed to Add are applied after these, so they override them per target.
+//
+// Example:
+//
+//	mgr := gnmi.NewManager(
+//	    gnmi.ClientOptions(
+//	        gnmi.Username("admin"),
+//	        gnmi.Password("secret"),
+//	        gnmi.MaxRetries(5),
+//	    ),
+//	)
+func ClientOptions(opts ...func(*Client)) func(*Manager) {
+	return func(m *Manager) {
+		m.clientOpts = append(m.clientOpts, opts...)
+	}
+}
+
+// NewManager creates an empty Manager
+//
+// Example:
+//
+//	mgr := gnmi.NewManager(
+//	    gnmi.MaxParallel(50),
+//	    gnmi.ClientOptions(gnmi.Username("admin"), gnmi.Password("secret")),
+//	)
+//	defer mgr.Close()
+//
+//	for name, addr := range inventory {
+//	    if err := mgr.Add(name, addr); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+//
+//	report := mgr.GetAll(ctx, []string{"/system/config/hostname"})
+//	for name, err := range report.Errors {
+//	    log.Printf("%s: %v", name, err)
+//	}
+func NewManager(opts ...func(*Manager)) *Manager {
+	m := &Manager{
+		MaxParallel: DefaultMaxParallel,
+		clients:     make(map[string]*Client),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.MaxParallel < 1 {
+		m.MaxParallel = 1
+	}
+
+	return m
+}
+
+// Add creates a Client for target and registers it under name
+//
+// The Client is created with the Manager's ClientOptions followed by opts.
+// Like NewClient, no connection is established until the first operation.
+//
+// Returns an error if name is empty or already registered, or if the client
+// configuration is invalid.
+func (m *Manager) Add(name, target string, opts ...func(*Client)) error {
+	if name == "" {
+		return fmt.Errorf("target name cannot be empty")
+	}
+
+	m.mu.RLock()
+	_, exists := m.clients[name]
+	m.mu.RUnlock()
+	if exists {
+		return fmt.Errorf("target %q already exists", name)
+	}
+
+	allOpts := make([]func(*Client), 0, len(m.clientOpts)+len(opts))
+	allOpts = append(allOpts, m.clientOpts...)
+	allOpts = append(allOpts, opts...)
+
+	client, err := NewClient(target, allOpts...)
+	if err != nil {
+		return fmt.Errorf("target %q: %w", name, err)
+	}
+
+	if err := m.AddClient(name, client); err != nil {
+		_ = client.Close() //nolint:errcheck // Client was never used
+		return err
+	}
+	return nil
+}
+
+// AddClient registers an existing Client under name
+//
+// The Manager takes ownership of the client: Remove and Close close it.
+//
+// Returns an error if name is empty or already registered.
+func (m *Manager) AddClient(name string, client *Client) error {
+	if name == "" {
+		return fmt.Errorf("target name cannot be empty")
+	}
+	if client == nil {
+		return fmt.Errorf("target %q: client cannot be nil", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.clients[name]; exists {
+		return fmt.Errorf("target %q already exists", name)
+	}
+	m.clients[name] = client
+	return nil
+}
+
+// Remove unregisters the target name and closes its Client
+//
+// Returns an error if name is not registered or closing the client fails.
+func (m *Manager) Remove(name string) error {
+	m.mu.Lock()
+	client, ok := m.clients[name]
+	delete(m.clients, name)
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("target %q not found", name)
+	}
+	return client.Close()
+}
+
+// Client returns the Client registered under name
+func (m *Manager) Client(name string) (*Client, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	client, ok := m.clients[name]
+	return client, ok
+}
+
+// Names returns the names of all registered targets in sorted order
+func (m *Manager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return sortedNames(m.clients)
+}
+
+// Close closes all Clients and removes them from the Manager
+//
+// Returns the errors of all clients that failed to close, joined.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	clients := m.clients
+	m.clients = make(map[string]*Client)
+	m.mu.Unlock()
+
+	var errs []error
+	for _, name := range sortedNames(clients) {
+		if err := clients[name].Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetAll performs a Get on every target
+//
+// Example:
+//
+//	report := mgr.GetAll(ctx, []string{"/system/config/hostname"})
+//	for name, res := range report.Results {
+//	    fmt.Printf("%s: %v\n", name, res.Values())
+//	}
+//	if err := report.Err(); err != nil {
+//	    log.Printf("some targets failed: %v", err)
+//	}
+//
+// Returns a Report with the GetRes of every target that succeeded and the
+// error of every target that failed.
+func (m *Manager) GetAll(ctx context.Context, paths []string, mods ...func(*Req)) Report[GetRes] {
+	return fanOut(ctx, m, "Get", func(ctx context.Context, c *Client) (GetRes, error) {
+		return c.Get(ctx, paths, mods...)
+	})
+}
+
+// SetAll performs the same Set on every target
+//
+// Each target applies the operations in its own transaction; a failure on
+// one target does not roll back the others.
+//
+// Example:
+//
+//	report := mgr.SetAll(ctx, []gnmi.SetOperation{
+//	    gnmi.Update("/system/config", `{"login-banner": "Authorized access only"}`),
+//	})
+//	for _, name := range report.Failed() {
+//	    log.Printf("%s: %v", name, report.Errors[name])
+//	}
+//
+// Returns a Report with the SetRes of every target that succeeded and the
+// error of every target that failed.
+func (m *Manager) SetAll(ctx context.Context, ops []SetOperation, mods ...func(*Req)) Report[SetRes] {
+	return fanOut(ctx, m, "Set", func(ctx context.Context, c *Client) (SetRes, error) {
+		return c.Set(ctx, ops, mods...)
+	})
+}
+
+// SubscribeAll starts the same subscription on every target
+//
+// The streams of the successful targets must be closed by the caller, even
+// if other targets failed.
+//
+// Example:
+//
+//	report := mgr.SubscribeAll(ctx, []gnmi.Subscription{
+//	    gnmi.OnChange("/interfaces/interface/state/oper-status"),
+//	})
+//	for name, stream := range report.Results {
+//	    defer stream.Close()
+//	    go consume(name, stream.Responses())
+//	}
+//
+// Returns a Report with the SubscribeStream of every target that succeeded
+// and the error of every target that failed.
+func (m *Manager) SubscribeAll(ctx context.Context, subs []Subscription, mods ...func(*Req)) Report[*SubscribeStream] {
+	return fanOut(ctx, m, "Subscribe", func(ctx context.Context, c *Client) (*SubscribeStream, error) {
+		return c.Subscribe(ctx, subs, mods...)
+	})
+}
+
+// OK reports whether all targets succeeded
+func (r Report[T]) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// Failed returns the names of all failed targets in sorted order
+func (r Report[T]) Failed() []string {
+	return sortedNames(r.Errors)
+}
+
+// Err returns the errors of all failed targets, joined and prefixed with the target name
+//
+// The result supports errors.Is and errors.As for the per-target errors.
+//
+// Returns nil if all targets succeeded.
+func (r Report[T]) Err() error {
+	var errs []error
+	for _, name := range r.Failed() {
+		errs = append(errs, fmt.Errorf("%s: %w", name, r.Errors[name]))
+	}
+	return errors.Join(errs...)
+}
+
+// fanOut runs the operation fn on every target of m with at most
+// m.MaxParallel in flight
+//
+// Targets that have not started when ctx is canceled fail with a GnmiError
+// for operation wrapping the context error.
+func fanOut[T any](ctx context.Context, m *Manager, operation string, fn func(context.Context, *Client) (T, error)) Report[T] {
+	start := time.Now()
+
+	m.mu.RLock()
+	clients := make(map[string]*Client, len(m.clients))
+	for name, client := range m.clients {
+		clients[name] = client
+	}
+	m.mu.RUnlock()
+
+	report := Report[T]{
+		Results: make(map[string]T, len(clients)),
+		Errors:  make(map[string]error),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(m.MaxParallel, 1))
+
+	for _, name := range sortedNames(clients) {
+		// Check ctx after acquiring a slot as well, since select picks
+		// randomly when both cases are ready
+		acquired := false
+		select {
+		case sem <- struct{}{}:
+			acquired = true
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			if acquired {
+				<-sem
+			}
+			mu.Lock()
+			report.Errors[name] = newGnmiError(operation, nil, err, start)
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, client *Client) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			res, err := fn(ctx, client)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Errors[name] = err
+			} else {
+				report.Results[name] = res
+			}
+		}(name, clients[name])
+	}
+
+	wg.Wait()
+	return report
+}
+
+// sortedNames returns the keys of a map keyed by target name in sorted order
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
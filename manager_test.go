@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestManager returns a Manager with the named clients and no connections
+func newTestManager(t *testing.T, names ...string) *Manager {
+	t.Helper()
+	m := NewManager(ClientOptions(TLS(false)))
+	for _, name := range names {
+		if err := m.Add(name, "192.0.2.1:57400"); err != nil {
+			t.Fatalf("Add(%q) error = %v", name, err)
+		}
+	}
+	t.Cleanup(func() { _ = m.Close() })
+	return m
+}
+
+// TestManager_Targets tests adding, looking up, and removing targets
+func TestManager_Targets(t *testing.T) {
+	m := newTestManager(t, "r2", "r1")
+
+	if got := m.Names(); !reflect.DeepEqual(got, []string{"r1", "r2"}) {
+		t.Errorf("Names() = %v, want [r1 r2]", got)
+	}
+	if c, ok := m.Client("r1"); !ok || c.Target != "192.0.2.1:57400" || c.UseTLS {
+		t.Errorf("Client(r1) = %+v, %v", c, ok)
+	}
+
+	if err := m.Add("r1", "192.0.2.2:57400"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Add() duplicate error = %v", err)
+	}
+	if err := m.Add("", "192.0.2.2:57400"); err == nil {
+		t.Errorf("Add() accepted empty name")
+	}
+	if err := m.Add("r3", "192.0.2.3:57400", MaxRetries(-1)); err == nil || !strings.Contains(err.Error(), `target "r3"`) {
+		t.Errorf("Add() invalid config error = %v", err)
+	}
+	if err := m.AddClient("r3", nil); err == nil {
+		t.Errorf("AddClient() accepted nil client")
+	}
+
+	c, _ := m.Client("r2")
+	if err := m.Remove("r2"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if !c.closed {
+		t.Errorf("Remove() did not close the client")
+	}
+	if err := m.Remove("r2"); err == nil {
+		t.Errorf("Remove() of unknown target returned nil")
+	}
+	if got := m.Names(); !reflect.DeepEqual(got, []string{"r1"}) {
+		t.Errorf("Names() after Remove = %v, want [r1]", got)
+	}
+}
+
+// TestManager_FanOut tests bounded parallelism and result collection
+func TestManager_FanOut(t *testing.T) {
+	m := newTestManager(t, "r1", "r2", "r3", "r4", "r5", "r6")
+	m.MaxParallel = 2
+
+	var inFlight, peak atomic.Int32
+	report := fanOut(context.Background(), m, "Get", func(ctx context.Context, c *Client) (string, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if c == mustClient(t, m, "r3") {
+			return "", errors.New("boom")
+		}
+		return c.Target, nil
+	})
+
+	if p := peak.Load(); p > 2 {
+		t.Errorf("peak parallelism = %d, want <= 2", p)
+	}
+	if len(report.Results) != 5 || report.OK() {
+		t.Errorf("Results = %v, OK = %v", report.Results, report.OK())
+	}
+	if got := report.Failed(); !reflect.DeepEqual(got, []string{"r3"}) {
+		t.Errorf("Failed() = %v, want [r3]", got)
+	}
+	if err := report.Err(); err == nil || err.Error() != "r3: boom" {
+		t.Errorf("Err() = %v, want r3: boom", err)
+	}
+}
+
+// TestManager_FanOutCanceled tests that targets not started before cancellation fail with the context error
+func TestManager_FanOutCanceled(t *testing.T) {
+	m := newTestManager(t, "r1", "r2", "r3")
+	m.MaxParallel = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	report := fanOut(ctx, m, "Get", func(ctx context.Context, c *Client) (int, error) {
+		cancel()
+		return 1, nil
+	})
+
+	if len(report.Results) != 1 || !errors.Is(report.Errors["r2"], context.Canceled) || !errors.Is(report.Errors["r3"], context.Canceled) {
+		t.Errorf("Report = %+v, want r1 result and r2, r3 canceled", report)
+	}
+	var gnmiErr *GnmiError
+	if !errors.As(report.Errors["r2"], &gnmiErr) || gnmiErr.Operation != "Get" || gnmiErr.Code != codes.Canceled {
+		t.Errorf("Errors[r2] = %#v, want Get GnmiError with code Canceled", report.Errors["r2"])
+	}
+}
+
+// TestIntegration_Manager tests GetAll, SetAll, and SubscribeAll against fake servers
+func TestIntegration_Manager(t *testing.T) {
+	m := NewManager(ClientOptions(
+		Username("admin"),
+		Password("secret"),
+		TLS(false),
+		MaxRetries(0),
+	))
+	t.Cleanup(func() { _ = m.Close() })
+
+	servers := map[string]*gnmitest.Server{}
+	for _, name := range []string{"r1", "r2"} {
+		srv, err := gnmitest.NewServer(gnmitest.Credentials("admin", "secret"))
+		if err != nil {
+			t.Fatalf("gnmitest.NewServer() error = %v", err)
+		}
+		t.Cleanup(srv.Close)
+		if err := srv.Load("/system/config", `{"hostname": "`+name+`"}`); err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		servers[name] = srv
+		if err := m.Add(name, srv.Addr()); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	// r3 rejects the manager credentials
+	if err := m.Add("r3", servers["r1"].Addr(), Password("wrong")); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	ctx := context.Background()
+
+	getReport := m.GetAll(ctx, []string{"/system/config/hostname"})
+	for _, name := range []string{"r1", "r2"} {
+		if got := getReport.Results[name].Values()["/system/config/hostname"]; got != name {
+			t.Errorf("GetAll() %s hostname = %v, want %q", name, got, name)
+		}
+	}
+	if status.Code(getReport.Errors["r3"]) != codes.Unauthenticated {
+		t.Errorf("GetAll() r3 error = %v, want Unauthenticated", getReport.Errors["r3"])
+	}
+
+	setReport := m.SetAll(ctx, []SetOperation{Update("/system/config/login-banner", `"hello"`)})
+	if len(setReport.Results) != 2 || setReport.Failed()[0] != "r3" {
+		t.Errorf("SetAll() = %+v", setReport)
+	}
+	for name, srv := range servers {
+		if got, _ := srv.JSON("/system/config/login-banner"); got != `"hello"` {
+			t.Errorf("%s login-banner = %s, want \"hello\"", name, got)
+		}
+	}
+
+	subReport := m.SubscribeAll(ctx, []Subscription{OnChange("/system/config")}, SubscribeMode(SubscribeModeOnce))
+	if !subReport.OK() {
+		t.Fatalf("SubscribeAll() errors = %v", subReport.Err())
+	}
+	for name, stream := range subReport.Results {
+		var updates int
+		for res := range stream.Responses() {
+			updates += len(res.Notification.Updates)
+		}
+		// Authentication is checked when the stream is opened by the target,
+		// so r3 fails on the stream
+		if name == "r3" {
+			if status.Code(stream.Err()) != codes.Unauthenticated {
+				t.Errorf("SubscribeAll() r3 stream error = %v, want Unauthenticated", stream.Err())
+			}
+		} else if updates == 0 {
+			t.Errorf("SubscribeAll() %s received no updates", name)
+		}
+		_ = stream.Close()
+	}
+}
+
+// mustClient returns the named client of m
+func mustClient(t *testing.T, m *Manager, name string) *Client {
+	t.Helper()
+	c, ok := m.Client(name)
+	if !ok {
+		t.Fatalf("Client(%q) not found", name)
+	}
+	return c
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metrics receives measurements of client operations
+//
+// Set it with WithMetrics. The gnmiprom package provides an implementation
+// exporting Prometheus metrics. All methods are called synchronously on the
+// goroutine of the operation and must be fast and safe for concurrent use.
+// Embed NoOpMetrics to implement only some of the methods.
+//
+// Example:
+//
+//	type opCounter struct {
+//	    gnmi.NoOpMetrics
+//	    failures atomic.Int64
+//	}
+//
+//	func (m *opCounter) OperationCompleted(ctx context.Context, target, operation string, code codes.Code, duration time.Duration) {
+//	    if code != codes.OK {
+//	        m.failures.Add(1)
+//	    }
+//	}
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400", gnmi.WithMetrics(&opCounter{}))
+type Metrics interface {
+	// OperationCompleted is called when Get, Set, Capabilities, or Subscribe
+	// returns, with the status code of the result (see OperationCode) and
+	// the duration including retries
+	OperationCompleted(ctx context.Context, target, operation string, code codes.Code, duration time.Duration)
+
+	// OperationRetried is called before a failed Get or Set is retried or a
+	// dropped subscription is resubscribed, with the status code of the failure
+	OperationRetried(ctx context.Context, target, operation string, code codes.Code)
+
+	// Reconnected is called after every reconnection attempt (err is nil on success)
+	Reconnected(ctx context.Context, target string, err error)
+
+	// BytesTransferred is called after every RPC attempt and for every
+	// subscription response, with the encoded sizes of the messages sent and
+	// received
+	BytesTransferred(ctx context.Context, target, operation string, sent, received int)
+
+	// SubscriptionsChanged is called with +1 when a subscription starts and -1
+	// when it ends
+	SubscriptionsChanged(ctx context.Context, target string, delta int)
+
+	// LimiterWaited is called when the request limiter admits an operation,
+	// with its queueing delay (see RateLimit and MaxConcurrentRequests)
+	LimiterWaited(ctx context.Context, target, operation string, wait time.Duration)
+}
+
+// NoOpMetrics is a Metrics implementation that discards all measurements
+//
+// Embed it in custom implementations to stay compatible when methods are
+// added to Metrics.
+type NoOpMetrics struct{}
+
+// OperationCompleted implements Metrics
+func (NoOpMetrics) OperationCompleted(context.Context, string, string, codes.Code, time.Duration) {}
+
+// OperationRetried implements Metrics
+func (NoOpMetrics) OperationRetried(context.Context, string, string, codes.Code) {}
+
+// Reconnected implements Metrics
+func (NoOpMetrics) Reconnected(context.Context, string, error) {}
+
+// BytesTransferred implements Metrics
+func (NoOpMetrics) BytesTransferred(context.Context, string, string, int, int) {}
+
+// SubscriptionsChanged implements Metrics
+func (NoOpMetrics) SubscriptionsChanged(context.Context, string, int) {}
+
+// LimiterWaited implements Metrics
+func (NoOpMetrics) LimiterWaited(context.Context, string, string, time.Duration) {}
+
+// WithMetrics configures a Metrics implementation for the client
+//
+// Example:
+//
+//	metrics := gnmiprom.New()
+//	prometheus.MustRegister(metrics)
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400", gnmi.WithMetrics(metrics))
+func WithMetrics(metrics Metrics) func(*Client) {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// OperationCode returns the gRPC status code reported to Metrics for the
+// result of an operation
+//
+// Returns codes.OK for nil, the gRPC status code of the error if it has one
+// (including Canceled and DeadlineExceeded for context errors), and otherwise
+// InvalidArgument for ErrValidation, Unavailable for ErrNotConnected and
+// ErrCircuitOpen, Canceled for ErrClosed, and Unknown for other errors.
+func OperationCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	var gnmiErr *GnmiError
+	if errors.As(err, &gnmiErr) && gnmiErr.Code != codes.OK {
+		return gnmiErr.Code
+	}
+
+	switch {
+	case errors.Is(err, ErrValidation):
+		return codes.InvalidArgument
+	case errors.Is(err, ErrNotConnected), errors.Is(err, ErrCircuitOpen):
+		return codes.Unavailable
+	case errors.Is(err, ErrClosed), errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	default:
+		return codes.Unknown
+	}
+}
+
+// observeOperation reports a completed operation to the metrics
+func (c *Client) observeOperation(ctx context.Context, operation string, start time.Time, err error) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.OperationCompleted(ctx, c.Target, operation, OperationCode(err), time.Since(start))
+}
+
+// observeRetry reports a retry after err to the metrics and the operation span
+func (c *Client) observeRetry(ctx context.Context, operation string, attempt int, delay time.Duration, err error) {
+	c.addSpanEvent(ctx, "retry", retryEventAttributes(attempt, delay, err)...)
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.OperationRetried(ctx, c.Target, operation, attemptCode(err))
+}
+
+// observeBytes reports the encoded sizes of a request and response to the metrics
+//
+// Either message may be nil.
+func (c *Client) observeBytes(ctx context.Context, operation string, sent, received proto.Message) {
+	if c.metrics == nil {
+		return
+	}
+	var sentBytes, receivedBytes int
+	if sent != nil {
+		sentBytes = proto.Size(sent)
+	}
+	if received != nil {
+		receivedBytes = proto.Size(received)
+	}
+	c.metrics.BytesTransferred(ctx, c.Target, operation, sentBytes, receivedBytes)
+}
+
+// observeReconnect reports a reconnection attempt to the metrics and the operation span
+func (c *Client) observeReconnect(ctx context.Context, err error) {
+	if err != nil {
+		c.addSpanEvent(ctx, "reconnect", attribute.Bool("gnmi.reconnected", false), attribute.String("exception.message", err.Error()))
+	} else {
+		c.addSpanEvent(ctx, "reconnect", attribute.Bool("gnmi.reconnected", true))
+	}
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.Reconnected(ctx, c.Target, err)
+}
+
+// observeSubscriptions reports a started (+1) or ended (-1) subscription to the metrics
+func (c *Client) observeSubscriptions(ctx context.Context, delta int) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.SubscriptionsChanged(ctx, c.Target, delta)
+}
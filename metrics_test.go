@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recordingMetrics records the calls of the Metrics interface
+type recordingMetrics struct {
+	mu            sync.Mutex
+	operations    []string
+	retries       []string
+	reconnects    int
+	sent          map[string]int
+	received      map[string]int
+	subscriptions int
+	limiterWaits  int
+}
+
+var _ Metrics = (*recordingMetrics)(nil)
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{sent: map[string]int{}, received: map[string]int{}}
+}
+
+func (m *recordingMetrics) OperationCompleted(_ context.Context, _, operation string, code codes.Code, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.operations = append(m.operations, fmt.Sprintf("%s:%s", operation, code))
+}
+
+func (m *recordingMetrics) OperationRetried(_ context.Context, _, operation string, code codes.Code) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, fmt.Sprintf("%s:%s", operation, code))
+}
+
+func (m *recordingMetrics) Reconnected(_ context.Context, _ string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		m.reconnects++
+	}
+}
+
+func (m *recordingMetrics) BytesTransferred(_ context.Context, _, operation string, sent, received int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent[operation] += sent
+	m.received[operation] += received
+}
+
+func (m *recordingMetrics) SubscriptionsChanged(_ context.Context, _ string, delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscriptions += delta
+}
+
+func (m *recordingMetrics) LimiterWaited(context.Context, string, string, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limiterWaits++
+}
+
+// activeSubscriptions returns the current subscription count
+func (m *recordingMetrics) activeSubscriptions() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.subscriptions
+}
+
+// TestOperationCode tests the status codes reported for operation results
+func TestOperationCode(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"success", nil, codes.OK},
+		{"gRPC status", newGnmiError("Get", nil, status.Error(codes.NotFound, "missing"), start), codes.NotFound},
+		{"deadline", newGnmiError("Get", nil, context.DeadlineExceeded, start), codes.DeadlineExceeded},
+		{"validation", newGnmiError("Get", ErrValidation, errors.New("invalid path"), start), codes.InvalidArgument},
+		{"not connected", newGnmiError("Get", ErrNotConnected, errors.New("refused"), start), codes.Unavailable},
+		{"circuit open", newGnmiError("Get", ErrCircuitOpen, ErrCircuitOpen, start), codes.Unavailable},
+		{"closed", newGnmiError("Get", ErrClosed, ErrClosed, start), codes.Canceled},
+		{"other", errors.New("boom"), codes.Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OperationCode(tt.err); got != tt.want {
+				t.Errorf("OperationCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIntegration_Metrics tests that operations report metrics against the fake server
+func TestIntegration_Metrics(t *testing.T) {
+	metrics := newRecordingMetrics()
+	client, srv := newIntegrationClient(t, WithMetrics(metrics), MaxRetries(2), MaxConcurrentRequests(4))
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	srv.FailNext(gnmitest.RPCGet, codes.Unavailable, 1)
+	if _, err := client.Get(ctx, []string{"/system/config"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := client.Set(ctx, []SetOperation{Update("/system/config", `{"hostname": "r2"}`)}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := client.Get(ctx, []string{"invalid["}); err == nil {
+		t.Fatalf("Get() expected validation error")
+	}
+
+	stream, err := client.Subscribe(testContext(t), []Subscription{OnChange("/system/config/hostname")})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	waitForSync(t, stream)
+	if n := metrics.activeSubscriptions(); n != 1 {
+		t.Errorf("active subscriptions = %d, want 1", n)
+	}
+
+	// A dropped stream is resubscribed after reconnecting
+	srv.DropConnections()
+	if res := nextResponse(t, stream); !res.Reconnected {
+		t.Errorf("response = %+v, want Reconnected", res)
+	}
+	_ = stream.Close()
+	if n := metrics.activeSubscriptions(); n != 0 {
+		t.Errorf("active subscriptions after Close = %d, want 0", n)
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	wantOps := []string{"Capabilities:OK", "Get:OK", "Set:OK", "Get:InvalidArgument", "Subscribe:OK"}
+	if fmt.Sprint(metrics.operations) != fmt.Sprint(wantOps) {
+		t.Errorf("operations = %v, want %v", metrics.operations, wantOps)
+	}
+	if len(metrics.retries) != 2 || metrics.retries[0] != "Get:Unavailable" || metrics.retries[1] != "Subscribe:Unavailable" {
+		t.Errorf("retries = %v, want [Get:Unavailable Subscribe:Unavailable]", metrics.retries)
+	}
+	if metrics.reconnects < 1 {
+		t.Errorf("reconnects = %d, want at least 1", metrics.reconnects)
+	}
+	for _, op := range []string{"Get", "Set", "Subscribe"} {
+		if metrics.sent[op] == 0 || metrics.received[op] == 0 {
+			t.Errorf("%s bytes sent/received = %d/%d, want both > 0", op, metrics.sent[op], metrics.received[op])
+		}
+	}
+	if metrics.received["Capabilities"] == 0 {
+		t.Errorf("Capabilities bytes received = 0, want > 0")
+	}
+	// Capabilities, the valid Get, Set, and Subscribe (not the invalid Get)
+	if metrics.limiterWaits != 4 {
+		t.Errorf("limiter waits = %d, want 4", metrics.limiterWaits)
+	}
+}
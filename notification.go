@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Notification represents a decoded gNMI notification
+//
+// Paths are rendered as XPath-style strings and values are decoded from
+// gNMI TypedValue messages into plain Go values, so consumers do not need
+// to work with the raw protobuf messages.
+type Notification struct {
+	// Timestamp is the notification timestamp (nanoseconds since Unix epoch)
+	Timestamp int64
+
+	// Prefix is the notification prefix path (empty if no prefix was sent)
+	Prefix string
+
+	// Updates contains the updated paths and their values
+	Updates []NotificationUpdate
+
+	// Deletes contains the full paths (prefix + path) that were deleted
+	Deletes []string
+
+	// Atomic indicates the notification must be processed as a single unit
+	Atomic bool
+}
+
+// NotificationUpdate represents a single path/value pair within a Notification
+type NotificationUpdate struct {
+	// Path is the full path of the update (prefix + path)
+	Path string
+
+	// Value is the decoded value
+	//
+	// Value types depend on the TypedValue variant sent by the target:
+	//   - json, json_ietf: any (map[string]any, []any, string, json.Number, bool, nil)
+	//   - string, ascii: string
+	//   - int: int64
+	//   - uint: uint64
+	//   - bool: bool
+	//   - float, double, decimal64: float64
+	//   - bytes, proto_bytes: []byte
+	//   - leaflist: []any with each element decoded by the same rules
+	//   - any: the unpacked proto.Message if its type is registered,
+	//     otherwise the *anypb.Any
+	//
+	// Unknown variants are returned as *gnmipb.TypedValue.
+	Value any
+
+	// Duplicates is the number of coalesced duplicates reported by the target
+	Duplicates uint32
+}
+
+// newNotification converts a gNMI notification into a Notification
+//
+// Returns a Notification with an empty update list if n is nil.
+func newNotification(n *gnmipb.Notification) Notification {
+	if n == nil {
+		return Notification{}
+	}
+
+	notif := Notification{
+		Timestamp: n.GetTimestamp(),
+		Prefix:    pathToString(nil, n.GetPrefix()),
+		Updates:   make([]NotificationUpdate, 0, len(n.GetUpdate())),
+		Deletes:   make([]string, 0, len(n.GetDelete())),
+		Atomic:    n.GetAtomic(),
+	}
+
+	for _, u := range n.GetUpdate() {
+		notif.Updates = append(notif.Updates, NotificationUpdate{
+			Path:       pathToString(n.GetPrefix(), u.GetPath()),
+			Value:      decodeTypedValue(u.GetVal()),
+			Duplicates: u.GetDuplicates(),
+		})
+	}
+
+	for _, d := range n.GetDelete() {
+		notif.Deletes = append(notif.Deletes, pathToString(n.GetPrefix(), d))
+	}
+
+	return notif
+}
+
+// Values returns the decoded update values keyed by full path (prefix + path)
+//
+// Example:
+//
+//	for path, value := range res.Notification.Values() {
+//	    fmt.Printf("%s = %v\n", path, value)
+//	}
+//
+// Returns an empty map if the notification has no updates.
+func (n Notification) Values() map[string]any {
+	values := make(map[string]any, len(n.Updates))
+	for _, u := range n.Updates {
+		values[u.Path] = u.Value
+	}
+	return values
+}
+
+// decodeTypedValue converts a gNMI TypedValue into a plain Go value
+//
+// JSON payloads are decoded with json.Number to preserve 64-bit counters.
+// If a JSON payload cannot be decoded, the raw JSON string is returned.
+//
+// Returns nil if tv is nil.
+func decodeTypedValue(tv *gnmipb.TypedValue) any {
+	if tv == nil {
+		return nil
+	}
+
+	switch v := tv.GetValue().(type) {
+	case *gnmipb.TypedValue_JsonIetfVal:
+		return decodeJSONValue(v.JsonIetfVal)
+	case *gnmipb.TypedValue_JsonVal:
+		return decodeJSONValue(v.JsonVal)
+	case *gnmipb.TypedValue_StringVal:
+		return v.StringVal
+	case *gnmipb.TypedValue_AsciiVal:
+		return v.AsciiVal
+	case *gnmipb.TypedValue_IntVal:
+		return v.IntVal
+	case *gnmipb.TypedValue_UintVal:
+		return v.UintVal
+	case *gnmipb.TypedValue_BoolVal:
+		return v.BoolVal
+	case *gnmipb.TypedValue_FloatVal:
+		return float64(v.FloatVal) //nolint:staticcheck // SA1019: FloatVal is deprecated but still sent by older targets
+	case *gnmipb.TypedValue_DoubleVal:
+		return v.DoubleVal
+	case *gnmipb.TypedValue_DecimalVal:
+		return decodeDecimal64(v.DecimalVal) //nolint:staticcheck // SA1019: DecimalVal is deprecated but still sent by older targets
+	case *gnmipb.TypedValue_BytesVal:
+		return v.BytesVal
+	case *gnmipb.TypedValue_ProtoBytes:
+		return v.ProtoBytes
+	case *gnmipb.TypedValue_LeaflistVal:
+		elems := v.LeaflistVal.GetElement()
+		values := make([]any, 0, len(elems))
+		for _, elem := range elems {
+			values = append(values, decodeTypedValue(elem))
+		}
+		return values
+	case *gnmipb.TypedValue_AnyVal:
+		if msg, err := v.AnyVal.UnmarshalNew(); err == nil {
+			return msg
+		}
+		return v.AnyVal
+	default:
+		return tv
+	}
+}
+
+// decodeDecimal64 converts a gNMI Decimal64 into a float64
+//
+// Digits beyond float64 precision are lost.
+func decodeDecimal64(d *gnmipb.Decimal64) float64 {
+	return float64(d.GetDigits()) / math.Pow10(int(d.GetPrecision()))
+}
+
+// decodeJSONValue unmarshals a JSON payload into a plain Go value
+//
+// Returns the payload as a string if it is not valid JSON.
+func decodeJSONValue(data []byte) any {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return string(data)
+	}
+	return v
+}
+
+// pathToString renders a gNMI prefix and path as an XPath-style string
+//
+// The prefix and path are joined and rendered in canonical form (see
+// Path.String). Both prefix and path may be nil; if both are nil the result
+// is empty.
+//
+// Example output: /interfaces/interface[name=Gi0/0/0/0]/state/counters
+func pathToString(prefix, path *gnmipb.Path) string {
+	if prefix == nil && path == nil {
+		return ""
+	}
+
+	elems := joinPathElems(prefix, path)
+
+	origin := prefix.GetOrigin()
+	if origin == "" {
+		origin = path.GetOrigin()
+	}
+
+	return Path{origin: origin, elems: elems}.String()
+}
@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// TestDecodeTypedValue tests conversion of TypedValue variants into Go values
+func TestDecodeTypedValue(t *testing.T) {
+	tests := []struct {
+		name string
+		tv   *gnmipb.TypedValue
+		want any
+	}{
+		{
+			name: "nil",
+			tv:   nil,
+			want: nil,
+		},
+		{
+			name: "json_ietf object",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"mtu":9000}`)}},
+			want: map[string]any{"mtu": json.Number("9000")},
+		},
+		{
+			name: "json scalar",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonVal{JsonVal: []byte(`"up"`)}},
+			want: "up",
+		},
+		{
+			name: "invalid json returned as string",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonVal{JsonVal: []byte(`{broken`)}},
+			want: "{broken",
+		},
+		{
+			name: "string",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "router1"}},
+			want: "router1",
+		},
+		{
+			name: "ascii",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_AsciiVal{AsciiVal: "hostname router1"}},
+			want: "hostname router1",
+		},
+		{
+			name: "int",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_IntVal{IntVal: -5}},
+			want: int64(-5),
+		},
+		{
+			name: "uint",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{UintVal: 18446744073709551615}},
+			want: uint64(18446744073709551615),
+		},
+		{
+			name: "bool",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BoolVal{BoolVal: true}},
+			want: true,
+		},
+		{
+			name: "double",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DoubleVal{DoubleVal: 1.5}},
+			want: 1.5,
+		},
+		{
+			name: "bytes",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_BytesVal{BytesVal: []byte{0x01, 0x02}}},
+			want: []byte{0x01, 0x02},
+		},
+		{
+			name: "proto bytes",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_ProtoBytes{ProtoBytes: []byte{0x0a}}},
+			want: []byte{0x0a},
+		},
+		{
+			name: "decimal64",
+			tv:   &gnmipb.TypedValue{Value: &gnmipb.TypedValue_DecimalVal{DecimalVal: &gnmipb.Decimal64{Digits: 12345, Precision: 2}}},
+			want: 123.45,
+		},
+		{
+			name: "leaflist",
+			tv: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_LeaflistVal{LeaflistVal: &gnmipb.ScalarArray{
+				Element: []*gnmipb.TypedValue{
+					{Value: &gnmipb.TypedValue_StringVal{StringVal: "a"}},
+					{Value: &gnmipb.TypedValue_UintVal{UintVal: 1}},
+				},
+			}}},
+			want: []any{"a", uint64(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeTypedValue(tt.tv)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeTypedValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDecodeTypedValue_Any tests unpacking of google.protobuf.Any values
+func TestDecodeTypedValue_Any(t *testing.T) {
+	model := &gnmipb.ModelData{Name: "openconfig-interfaces", Version: "2.0.0"}
+	packed, err := anypb.New(model)
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+
+	got := decodeTypedValue(&gnmipb.TypedValue{Value: &gnmipb.TypedValue_AnyVal{AnyVal: packed}})
+	if msg, ok := got.(proto.Message); !ok || !proto.Equal(msg, model) {
+		t.Errorf("decodeTypedValue() = %v, want %v", got, model)
+	}
+
+	unknown := &anypb.Any{TypeUrl: "type.googleapis.com/example.Unknown", Value: []byte{0x08, 0x01}}
+	got = decodeTypedValue(&gnmipb.TypedValue{Value: &gnmipb.TypedValue_AnyVal{AnyVal: unknown}})
+	if got != unknown {
+		t.Errorf("decodeTypedValue() of unregistered type = %v, want the Any message", got)
+	}
+}
+
+// TestPathToString tests rendering of gNMI prefix and path messages
+func TestPathToString(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix *gnmipb.Path
+		path   *gnmipb.Path
+		want   string
+	}{
+		{
+			name: "nil",
+			want: "",
+		},
+		{
+			name: "root",
+			path: &gnmipb.Path{},
+			want: "/",
+		},
+		{
+			name: "path with keys",
+			path: &gnmipb.Path{Elem: []*gnmipb.PathElem{
+				{Name: "interfaces"},
+				{Name: "interface", Key: map[string]string{"name": "Gi0/0/0/0"}},
+				{Name: "state"},
+			}},
+			want: "/interfaces/interface[name=Gi0/0/0/0]/state",
+		},
+		{
+			name: "sorted multiple keys",
+			path: &gnmipb.Path{Elem: []*gnmipb.PathElem{
+				{Name: "protocol", Key: map[string]string{"name": "BGP", "identifier": "BGP"}},
+			}},
+			want: "/protocol[identifier=BGP][name=BGP]",
+		},
+		{
+			name:   "prefix and path with origin",
+			prefix: &gnmipb.Path{Origin: "openconfig", Elem: []*gnmipb.PathElem{{Name: "system"}}},
+			path:   &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "config"}, {Name: "hostname"}}},
+			want:   "openconfig:/system/config/hostname",
+		},
+		{
+			name: "origin only",
+			path: &gnmipb.Path{Origin: "cli"},
+			want: "cli:/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathToString(tt.prefix, tt.path); got != tt.want {
+				t.Errorf("pathToString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewNotification tests conversion of gNMI notifications
+func TestNewNotification(t *testing.T) {
+	n := &gnmipb.Notification{
+		Timestamp: 1234,
+		Prefix:    &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "system"}}},
+		Update: []*gnmipb.Update{{
+			Path:       &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "config"}, {Name: "hostname"}}},
+			Val:        &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "router1"}},
+			Duplicates: 2,
+		}},
+		Delete: []*gnmipb.Path{
+			{Elem: []*gnmipb.PathElem{{Name: "config"}, {Name: "domain-name"}}},
+		},
+		Atomic: true,
+	}
+
+	got := newNotification(n)
+	want := Notification{
+		Timestamp: 1234,
+		Prefix:    "/system",
+		Updates: []NotificationUpdate{
+			{Path: "/system/config/hostname", Value: "router1", Duplicates: 2},
+		},
+		Deletes: []string{"/system/config/domain-name"},
+		Atomic:  true,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newNotification() = %+v, want %+v", got, want)
+	}
+
+	if empty := newNotification(nil); !reflect.DeepEqual(empty, Notification{}) {
+		t.Errorf("newNotification(nil) = %+v, want empty", empty)
+	}
+}
+
+// TestNotification_Values tests the flattened path/value map of a notification
+func TestNotification_Values(t *testing.T) {
+	n := Notification{Updates: []NotificationUpdate{
+		{Path: "/system/config/hostname", Value: "router1"},
+		{Path: "/system/config/domain-name", Value: "example.com"},
+	}}
+
+	want := map[string]any{
+		"/system/config/hostname":    "router1",
+		"/system/config/domain-name": "example.com",
+	}
+	if got := n.Values(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/gnmic/pkg/api"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -33,6 +34,7 @@ const (
 //   - Each path starts with "/"
 //   - Each path length does not exceed MaxPathLength
 //   - Each path does not contain malicious patterns (null bytes, path traversal)
+//   - Each path is syntactically valid (see ParsePath)
 //
 // Returns an error if any path is invalid with a descriptive message.
 func validatePaths(paths []string) error {
@@ -51,17 +53,22 @@ func validatePaths(paths []string) error {
 
 		// gNMI paths can be in two formats:
 		// 1. Absolute: /interfaces/interface[name=eth0]
-		// 2. Module-qualified: openconfig-interfaces:/interfaces/interface[name=eth0]
+		// 2. With origin: openconfig:/interfaces/interface[name=eth0]
 		//    or: Cisco-IOS-XR-um-banner-cfg:/banners/banner[banner-type=login]
-		// Check if path is valid (starts with / or is module-qualified)
+		// Check if path is valid (starts with / or has an origin)
 		if !isValidGNMIPath(path) {
-			return fmt.Errorf("path at index %d must start with '/' or be module-qualified (module:path): %s", i, path)
+			return fmt.Errorf("path at index %d must start with '/' or have an origin (origin:/path): %s", i, path)
 		}
 
 		// Check for malicious patterns
 		if err := checkPathSecurity(path); err != nil {
 			return fmt.Errorf("path at index %d is invalid: %w", i, err)
 		}
+
+		// Check path syntax (keys, escapes)
+		if _, err := ParsePath(path); err != nil {
+			return fmt.Errorf("path at index %d is invalid: %w", i, err)
+		}
 	}
 
 	return nil
@@ -81,6 +88,49 @@ func validateEncoding(encoding string) error {
 	return ValidateEncoding(encoding)
 }
 
+// validateDataType validates a Get data type
+//
+// Valid data types: all, config, state, operational
+// Empty string is valid (will default to all)
+//
+// Returns an error if the data type is not supported.
+func validateDataType(dataType DataType) error {
+	switch dataType {
+	case "", DataTypeAll, DataTypeConfig, DataTypeState, DataTypeOperational:
+		return nil
+	default:
+		return fmt.Errorf("invalid data type: %s (valid values: all, config, state, operational)", dataType)
+	}
+}
+
+// validateRequestPrefix validates the Prefix and Origin request modifiers
+//
+// Checks:
+//   - Prefix (if set) is a valid gNMI path
+//   - Origin (if set) is a valid origin name
+//   - Origin does not conflict with an origin given in the prefix
+//
+// Returns an error if the prefix or origin is invalid.
+func validateRequestPrefix(req *Req) error {
+	if req.Origin != "" {
+		if err := validateOrigin(req.Origin); err != nil {
+			return err
+		}
+	}
+	if req.Prefix == "" {
+		return nil
+	}
+
+	if err := validatePaths([]string{req.Prefix}); err != nil {
+		return fmt.Errorf("prefix: %w", err)
+	}
+	if origin, _, ok := splitOrigin(req.Prefix); ok && req.Origin != "" && origin != req.Origin {
+		return fmt.Errorf("prefix origin %q conflicts with request origin %q", origin, req.Origin)
+	}
+
+	return nil
+}
+
 // validateValue validates a value string for gNMI operations
 //
 // Checks:
@@ -110,8 +160,9 @@ func validateValue(value string, encoding string) error {
 //   - Operations slice is not empty
 //   - Each operation has a valid path
 //   - Each operation has a valid encoding
-//   - Each operation has a valid value (for Update/Replace operations)
+//   - Each operation has a valid value (for Update/Replace/UnionReplace operations)
 //   - Each operation has a valid operation type
+//   - Operations on the cli origin carry ASCII CLI text (see validateCLIOperation)
 //
 // Returns an error if any operation is invalid with a descriptive message.
 func validateSetOperations(ops []SetOperation) error {
@@ -126,11 +177,15 @@ func validateSetOperations(ops []SetOperation) error {
 		}
 		if op.OperationType != OperationUpdate &&
 			op.OperationType != OperationReplace &&
-			op.OperationType != OperationDelete {
-			return fmt.Errorf("operation type invalid: %s (must be 'update', 'replace', or 'delete', at index %d)", op.OperationType, i)
+			op.OperationType != OperationDelete &&
+			op.OperationType != OperationUnionReplace {
+			return fmt.Errorf("operation type invalid: %s (must be 'update', 'replace', 'delete', or 'union_replace', at index %d)", op.OperationType, i)
 		}
 
 		// Validate path
+		if op.pathErr != nil {
+			return fmt.Errorf("operation at index %d: %w", i, op.pathErr)
+		}
 		if err := validatePaths([]string{op.Path}); err != nil {
 			return fmt.Errorf("operation at index %d: %w", i, err)
 		}
@@ -144,17 +199,51 @@ func validateSetOperations(ops []SetOperation) error {
 			return fmt.Errorf("operation at index %d: %w", i, err)
 		}
 
-		// Validate value for Update/Replace operations
-		if op.OperationType == OperationUpdate || op.OperationType == OperationReplace {
+		// Validate value for Update/Replace/UnionReplace operations
+		if op.OperationType != OperationDelete {
 			if err := validateValue(op.Value, encoding); err != nil {
 				return fmt.Errorf("operation at index %d: %w", i, err)
 			}
 		}
+
+		// Validate CLI configuration
+		if origin, _, _ := splitOrigin(op.Path); origin == OriginCLI {
+			if err := validateCLIOperation(op); err != nil {
+				return fmt.Errorf("operation at index %d: %w", i, err)
+			}
+		}
 	}
 
 	return nil
 }
 
+// validateCLIOperation validates an operation on the cli origin
+//
+// CLI configuration is sent as ASCII text at the root of the cli origin
+// ("cli:/"), so the operation must not be a delete, must use the ascii
+// encoding, must not have path elements, and must have a non-empty value.
+//
+// Returns an error if the operation is invalid.
+func validateCLIOperation(op SetOperation) error {
+	if op.OperationType == OperationDelete {
+		return fmt.Errorf("cli origin does not support delete operations")
+	}
+	if op.Encoding != EncodingASCII {
+		return fmt.Errorf("cli origin requires ascii encoding, got: %s", op.Encoding)
+	}
+	p, err := ParsePath(op.Path)
+	if err != nil {
+		return err
+	}
+	if len(p.Proto().GetElem()) > 0 {
+		return fmt.Errorf("cli origin path must be the root path, got: %s", op.Path)
+	}
+	if strings.TrimSpace(op.Value) == "" {
+		return fmt.Errorf("cli configuration cannot be empty")
+	}
+	return nil
+}
+
 // Security validation helpers
 
 // checkPathSecurity checks a path for malicious patterns
@@ -324,12 +413,24 @@ func truncatePath(path string) string {
 //
 // Returns GetRes with notifications, timestamp, OK status, and any errors.
 func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (GetRes, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "Get")
+	res, err := c.doGet(ctx, start, paths, mods)
+	c.observeOperation(ctx, "Get", start, err)
+	endSpan(span, err)
+	return res, err
+}
+
+// doGet implements Get; start is the start time of the operation
+func (c *Client) doGet(ctx context.Context, start time.Time, paths []string, mods []func(*Req)) (GetRes, error) {
+	defer c.beginOperation()()
+
 	// Validate paths (before acquiring lock)
 	if err := validatePaths(paths); err != nil {
 		return GetRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, fmt.Errorf("get: %w", err)
+		}, newGnmiError("Get", ErrValidation, err, start)
 	}
 
 	// Build request with default encoding
@@ -341,13 +442,32 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 	for _, mod := range mods {
 		mod(req)
 	}
+	c.setSpanAttributes(ctx, attrPaths.Int(len(paths)), attrEncoding.String(req.Encoding))
 
-	// Validate encoding (before acquiring lock)
+	// Validate encoding and data type (before acquiring lock)
 	if err := validateEncoding(req.Encoding); err != nil {
 		return GetRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, fmt.Errorf("get: %w", err)
+		}, newGnmiError("Get", ErrValidation, err, start)
+	}
+	if err := validateDataType(req.DataType); err != nil {
+		return GetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Get", ErrValidation, err, start)
+	}
+	if err := validateRequestPrefix(req); err != nil {
+		return GetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Get", ErrValidation, err, start)
+	}
+	if err := validateExtensions("Get", req); err != nil {
+		return GetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Get", ErrValidation, err, start)
 	}
 
 	// Check context cancellation first (before acquiring lock)
@@ -355,15 +475,34 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 		return GetRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, err
+		}, newGnmiError("Get", nil, err, start)
 	}
 
+	// Fail fast while the circuit breaker is open
+	if err := c.checkCircuit(ctx); err != nil {
+		return GetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Get", ErrCircuitOpen, err, start)
+	}
+
+	// Wait for the request limiter
+	release, err := c.waitLimiter(ctx, "Get")
+	if err != nil {
+		return GetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Get", nil, err, start)
+	}
+	defer release()
+
 	// Ensure connection is established (lazy connection)
 	if err := c.ensureConnected(ctx); err != nil {
+		c.recordOutcome(ctx, err)
 		return GetRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, fmt.Errorf("get: connection failed: %w", err)
+		}, newGnmiError("Get", ErrNotConnected, fmt.Errorf("connection failed: %w", err), start)
 	}
 
 	// Acquire lock after validation
@@ -375,33 +514,57 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 		return GetRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: "client not connected"}},
-		}, fmt.Errorf("get: client not connected")
+		}, newGnmiError("Get", ErrNotConnected, ErrNotConnected, start)
 	}
 
-	// Calculate total timeout budget to prevent unbounded accumulation
-	// Total timeout = OperationTimeout + sum of actual backoff delays
-	// This accurately reflects the maximum time needed for all retry attempts
-	totalTimeout := c.calculateTotalTimeout()
-
-	c.logger.Debug(ctx, "applying total timeout budget",
-		"totalTimeout", totalTimeout.String(),
-		"operationTimeout", c.OperationTimeout.String(),
-		"maxRetries", c.MaxRetries,
-		"target", c.Target)
+	// Custom retry policies are bounded by the policy and ctx only
+	policy, customPolicy := c.retryPolicy(req)
+	if !customPolicy {
+		// Calculate total timeout budget to prevent unbounded accumulation
+		// Total timeout = OperationTimeout + sum of actual backoff delays
+		// This accurately reflects the maximum time needed for all retry attempts
+		totalTimeout := c.calculateTotalTimeout()
+
+		c.logger.Debug(ctx, "applying total timeout budget",
+			"totalTimeout", totalTimeout.String(),
+			"operationTimeout", c.OperationTimeout.String(),
+			"maxRetries", c.MaxRetries,
+			"target", c.Target)
 
-	// Apply parent context timeout for total budget
-	ctx, parentCancel := context.WithTimeout(ctx, totalTimeout)
-	defer parentCancel()
+		// Apply parent context timeout for total budget
+		var parentCancel context.CancelFunc
+		ctx, parentCancel = context.WithTimeout(ctx, totalTimeout)
+		defer parentCancel()
+	}
 
-	// NOTE: Full integration tests with mock gnmic target are documented in operations_test.go.
-	// Current tests focus on input validation, error handling, and API structure correctness.
+	// NOTE: Retry and reconnect behavior is tested against the gnmitest fake server
+	// in integration_test.go.
 
 	// Build gnmic GetRequest
+	prefix, err := buildPrefix(req)
+	if err != nil {
+		return GetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Get", ErrValidation, fmt.Errorf("failed to create request: %w", err), start)
+	}
 	gnmicOpts := []api.GNMIOption{
 		api.Encoding(req.Encoding),
+		api.DataType(string(req.DataType)),
+		protoPrefix(prefix),
 	}
 	for _, path := range paths {
-		gnmicOpts = append(gnmicOpts, api.Path(path))
+		gp, err := requestPath(path, req, prefix)
+		if err != nil {
+			return GetRes{
+				OK:     false,
+				Errors: []ErrorModel{{Message: err.Error()}},
+			}, newGnmiError("Get", ErrValidation, fmt.Errorf("failed to create request: %w", err), start)
+		}
+		gnmicOpts = append(gnmicOpts, protoPath(gp))
+	}
+	for _, ext := range req.Extensions {
+		gnmicOpts = append(gnmicOpts, api.Extension(ext))
 	}
 
 	getReq, err := api.NewGetRequest(gnmicOpts...)
@@ -412,14 +575,16 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 		return GetRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, fmt.Errorf("get: failed to create request: %w", err)
+		}, newGnmiError("Get", ErrValidation, fmt.Errorf("failed to create request: %w", err), start)
 	}
 
 	// Log request
 	c.logger.Debug(ctx, "gNMI Get request",
 		"target", c.Target,
 		"paths", len(paths),
-		"encoding", req.Encoding)
+		"encoding", req.Encoding,
+		"dataType", string(req.DataType),
+		"prefix", pathToString(prefix, nil))
 
 	// Log each path (at Debug level)
 	for i, path := range paths {
@@ -431,9 +596,14 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 	// Execute request with retry logic
 	var getResp *gnmipb.GetResponse
 	var lastErr error
+	var retries int
+	var delay time.Duration
+	var refreshed bool
 
 	//nolint:dupl // Get and Set retry logic are similar but have different error handling
-	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+	for attempt := 0; ; attempt++ {
+		retries = attempt
+
 		// Check parent context cancellation before attempt
 		if err := checkContextCancellation(ctx); err != nil {
 			c.logger.Debug(ctx, "get operation canceled",
@@ -443,7 +613,7 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 			return GetRes{
 				OK:     false,
 				Errors: []ErrorModel{{Message: fmt.Sprintf("context canceled: %s", err.Error())}},
-			}, fmt.Errorf("get: %w", err)
+			}, newGnmiError("Get", nil, err, start)
 		}
 
 		// Create attempt-specific context with timeout
@@ -451,9 +621,11 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 
 		// Execute Get request with attempt context
 		resp, err := c.target.Get(attemptCtx, getReq)
+		c.observeBytes(ctx, "Get", getReq, resp)
 
 		// Clean up attempt context immediately to prevent goroutine leak
 		attemptCancel()
+		c.recordOutcome(ctx, err)
 		if err == nil {
 			// Success
 			getResp = resp
@@ -464,11 +636,25 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 		// Store error
 		lastErr = err
 
-		// Extract error details for transient checking
-		errors := c.extractErrorDetails(err)
+		// Reconnect once with reloaded credentials if the target rejected them
+		if !refreshed && c.shouldRefreshCredentials(err) {
+			refreshed = true
+			// Upgrade to write lock for reconnection
+			c.mu.RUnlock()
+			c.mu.Lock()
+			refreshErr := c.refreshCredentials(ctx)
+			c.mu.Unlock()
+			c.mu.RLock()
+			if refreshErr == nil {
+				c.observeRetry(ctx, "Get", attempt+1, 0, err)
+				continue
+			}
+		}
 
-		// Check if error is transient and retries remain
-		if c.checkTransientErrorModels(errors) && attempt < c.MaxRetries {
+		// Ask the retry policy whether to retry and how long to wait
+		var retry bool
+		delay, retry = policy.Retry(newRetryAttempt("Get", attempt, err, true, delay))
+		if retry && c.circuitClosed() {
 			// Check for transport errors requiring reconnection
 			if c.isTransportError(lastErr) {
 				// Upgrade to write lock for reconnection
@@ -478,6 +664,7 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 
 				// Attempt to reconnect
 				if reconnectErr := c.reconnect(ctx); reconnectErr != nil {
+					c.recordOutcome(ctx, reconnectErr)
 					// Reconnection failed, downgrade to read lock and return error
 					c.mu.Unlock()
 					c.mu.RLock()
@@ -487,7 +674,7 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 					return GetRes{
 						OK:     false,
 						Errors: []ErrorModel{{Message: fmt.Sprintf("operation failed and reconnection failed: %s", reconnectErr.Error())}},
-					}, fmt.Errorf("get: reconnection failed: %w", reconnectErr)
+					}, c.retryError("Get", ErrNotConnected, fmt.Errorf("reconnection failed: %w", reconnectErr), lastErr, attempt, start)
 				}
 
 				// Reconnection succeeded, downgrade to read lock and continue retry
@@ -495,17 +682,16 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 				c.mu.RLock()
 			}
 
-			backoff := c.Backoff(attempt)
-			c.logger.Warn(ctx, "transient error, retrying",
+			c.observeRetry(ctx, "Get", attempt+1, delay, err)
+			c.logger.Warn(ctx, "retryable error, retrying",
 				"operation", "get",
 				"attempt", attempt+1,
-				"max_retries", c.MaxRetries,
-				"backoff", backoff,
+				"backoff", delay,
 				"error", err.Error())
 
 			// Sleep with context cancellation awareness (uses ctx)
 			select {
-			case <-time.After(backoff):
+			case <-time.After(delay):
 				// Backoff complete, continue to next attempt
 				continue
 			case <-ctx.Done():
@@ -516,10 +702,10 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 				return GetRes{
 					OK:     false,
 					Errors: []ErrorModel{{Message: fmt.Sprintf("context canceled during backoff: %s", ctx.Err().Error())}},
-				}, fmt.Errorf("get: context canceled during backoff: %w", ctx.Err())
+				}, c.retryError("Get", nil, fmt.Errorf("context canceled during backoff: %w", ctx.Err()), lastErr, attempt, start)
 			}
 		} else {
-			// Non-transient error or no retries remaining
+			// Non-retryable error, no retries remaining, or circuit breaker open
 			break
 		}
 	}
@@ -535,7 +721,7 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 		return GetRes{
 			OK:     false,
 			Errors: errors,
-		}, fmt.Errorf("get: request failed: %w", lastErr)
+		}, c.retryError("Get", nil, fmt.Errorf("request failed: %w", lastErr), lastErr, retries, start)
 	}
 
 	// Log response
@@ -563,9 +749,38 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 		Notifications: getResp.Notification,
 		Timestamp:     timestamp,
 		OK:            true,
+		Extensions:    getResp.GetExtension(),
+		request:       getReq,
 	}, nil
 }
 
+// GetPaths is like Get but takes structured Paths
+//
+// Example:
+//
+//	paths := []gnmi.Path{
+//	    gnmi.NewPath().Elem("interfaces").Elem("interface", "name", "Gi0/0/0/0").Elem("state"),
+//	    gnmi.NewPath().Elem("system").Elem("config").Elem("hostname"),
+//	}
+//	res, err := client.GetPaths(ctx, paths)
+//
+// Returns GetRes with notifications, timestamp, OK status, and any errors.
+func (c *Client) GetPaths(ctx context.Context, paths []Path, mods ...func(*Req)) (GetRes, error) {
+	start := time.Now()
+	strs := make([]string, 0, len(paths))
+	for i, path := range paths {
+		if err := path.Err(); err != nil {
+			err = fmt.Errorf("path at index %d is invalid: %w", i, err)
+			return GetRes{
+				OK:     false,
+				Errors: []ErrorModel{{Message: err.Error()}},
+			}, newGnmiError("Get", ErrValidation, err, start)
+		}
+		strs = append(strs, path.String())
+	}
+	return c.Get(ctx, strs, mods...)
+}
+
 // Set performs a gNMI Set operation to configure the device
 //
 // Set supports multiple update, replace, and delete operations in a single request.
@@ -596,12 +811,57 @@ func (c *Client) Get(ctx context.Context, paths []string, mods ...func(*Req)) (G
 //
 // Returns SetRes with response, timestamp, OK status, and any errors.
 func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)) (SetRes, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "Set")
+	res, err := c.doSet(ctx, start, ops, mods)
+	c.observeOperation(ctx, "Set", start, err)
+	endSpan(span, err)
+	return res, err
+}
+
+// doSet implements Set; start is the start time of the operation
+func (c *Client) doSet(ctx context.Context, start time.Time, ops []SetOperation, mods []func(*Req)) (SetRes, error) {
+	defer c.beginOperation()()
+
+	// Build request for modifiers
+	req := &Req{}
+
+	// Apply modifiers
+	for _, mod := range mods {
+		mod(req)
+	}
+
 	// Validate operations (before acquiring lock for better performance)
-	if err := validateSetOperations(ops); err != nil {
+	// Commit actions (confirm, cancel) are sent without operations
+	if req.commitAction == nil || len(ops) > 0 {
+		if err := validateSetOperations(ops); err != nil {
+			return SetRes{
+				OK:     false,
+				Errors: []ErrorModel{{Message: err.Error()}},
+			}, newGnmiError("Set", ErrValidation, err, start)
+		}
+	}
+	if err := validateCommit(req); err != nil {
 		return SetRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, fmt.Errorf("set: %w", err)
+		}, newGnmiError("Set", ErrValidation, err, start)
+	}
+	if err := validateExtensions("Set", req); err != nil {
+		return SetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Set", ErrValidation, err, start)
+	}
+	arbitrated := c.applyArbitration(req)
+	c.setSpanAttributes(ctx, attrOperations.Int(len(ops)))
+
+	// Validate prefix and origin (before acquiring lock)
+	if err := validateRequestPrefix(req); err != nil {
+		return SetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Set", ErrValidation, err, start)
 	}
 
 	// Check context cancellation first (before acquiring lock)
@@ -609,49 +869,74 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 		return SetRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, err
+		}, newGnmiError("Set", nil, err, start)
+	}
+
+	// Fail fast while the circuit breaker is open
+	if err := c.checkCircuit(ctx); err != nil {
+		return SetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Set", ErrCircuitOpen, err, start)
+	}
+
+	// Wait for the request limiter
+	release, err := c.waitLimiter(ctx, "Set")
+	if err != nil {
+		return SetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Set", nil, err, start)
 	}
+	defer release()
 
 	// Ensure connection is established (lazy connection)
 	if err := c.ensureConnected(ctx); err != nil {
+		c.recordOutcome(ctx, err)
 		return SetRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, fmt.Errorf("set: connection failed: %w", err)
+		}, newGnmiError("Set", ErrNotConnected, fmt.Errorf("connection failed: %w", err), start)
 	}
 
 	// Acquire lock after validation and connection
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Build request for modifiers
-	req := &Req{}
+	// Custom retry policies are bounded by the policy and ctx only
+	policy, customPolicy := c.retryPolicy(req)
+	if !customPolicy {
+		// Calculate total timeout budget to prevent unbounded accumulation
+		// Total timeout = OperationTimeout + sum of actual backoff delays
+		// This accurately reflects the maximum time needed for all retry attempts
+		totalTimeout := c.calculateTotalTimeout()
+
+		c.logger.Debug(ctx, "applying total timeout budget",
+			"totalTimeout", totalTimeout.String(),
+			"operationTimeout", c.OperationTimeout.String(),
+			"maxRetries", c.MaxRetries,
+			"target", c.Target)
 
-	// Apply modifiers
-	for _, mod := range mods {
-		mod(req)
+		// Apply parent context timeout for total budget
+		var parentCancel context.CancelFunc
+		ctx, parentCancel = context.WithTimeout(ctx, totalTimeout)
+		defer parentCancel()
 	}
 
-	// Calculate total timeout budget to prevent unbounded accumulation
-	// Total timeout = OperationTimeout + sum of actual backoff delays
-	// This accurately reflects the maximum time needed for all retry attempts
-	totalTimeout := c.calculateTotalTimeout()
-
-	c.logger.Debug(ctx, "applying total timeout budget",
-		"totalTimeout", totalTimeout.String(),
-		"operationTimeout", c.OperationTimeout.String(),
-		"maxRetries", c.MaxRetries,
-		"target", c.Target)
-
-	// Apply parent context timeout for total budget
-	ctx, parentCancel := context.WithTimeout(ctx, totalTimeout)
-	defer parentCancel()
-
-	// NOTE: Full integration tests with mock gnmic target are documented in operations_test.go.
-	// Current tests focus on input validation, operation helpers, and error handling correctness.
+	// NOTE: Retry and reconnect behavior is tested against the gnmitest fake server
+	// in integration_test.go.
 
 	// Build gnmic SetRequest based on operation types
-	gnmicOpts := []api.GNMIOption{}
+	prefix, err := buildPrefix(req)
+	if err != nil {
+		return SetRes{
+			OK:     false,
+			Errors: []ErrorModel{{Message: err.Error()}},
+		}, newGnmiError("Set", ErrValidation, fmt.Errorf("failed to create request: %w", err), start)
+	}
+	gnmicOpts := []api.GNMIOption{
+		protoPrefix(prefix),
+	}
 
 	for _, op := range ops {
 		encoding := op.Encoding
@@ -659,13 +944,33 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 			encoding = EncodingJSONIETF
 		}
 
+		gp, err := requestPath(op.Path, req, prefix)
+		if err != nil {
+			return SetRes{
+				OK:     false,
+				Errors: []ErrorModel{{Message: err.Error()}},
+			}, newGnmiError("Set", ErrValidation, fmt.Errorf("failed to create request: %w", err), start)
+		}
+
+		// Operations on the cli origin of the request modifiers
+		if origin, _, _ := splitOrigin(op.Path); origin != OriginCLI && (gp.GetOrigin() == OriginCLI || prefix.GetOrigin() == OriginCLI) {
+			if err := validateCLIOperation(op); err != nil {
+				return SetRes{
+					OK:     false,
+					Errors: []ErrorModel{{Message: err.Error()}},
+				}, newGnmiError("Set", ErrValidation, err, start)
+			}
+		}
+
 		switch op.OperationType {
 		case OperationUpdate:
-			gnmicOpts = append(gnmicOpts, api.Update(api.Path(op.Path), api.Value(op.Value, encoding)))
+			gnmicOpts = append(gnmicOpts, api.Update(protoPath(gp), api.Value(op.Value, encoding)))
 		case OperationReplace:
-			gnmicOpts = append(gnmicOpts, api.Replace(api.Path(op.Path), api.Value(op.Value, encoding)))
+			gnmicOpts = append(gnmicOpts, api.Replace(protoPath(gp), api.Value(op.Value, encoding)))
+		case OperationUnionReplace:
+			gnmicOpts = append(gnmicOpts, api.UnionReplace(protoPath(gp), api.Value(op.Value, encoding)))
 		case OperationDelete:
-			gnmicOpts = append(gnmicOpts, api.Delete(op.Path))
+			gnmicOpts = append(gnmicOpts, protoDelete(gp))
 		default:
 			// Invalid operation type
 			return SetRes{
@@ -673,10 +978,26 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 				Errors: []ErrorModel{{
 					Message: fmt.Sprintf("invalid operation type: %s", op.OperationType),
 				}},
-			}, fmt.Errorf("set: invalid operation type: %s", op.OperationType)
+			}, newGnmiError("Set", ErrValidation, fmt.Errorf("invalid operation type: %s", op.OperationType), start)
 		}
 	}
 
+	// Commit extension
+	var commit *Commit
+	if req.RollbackDuration > 0 {
+		commit = &Commit{ID: req.CommitID, client: c, target: req.Target, prefix: req.Prefix, origin: req.Origin}
+		if commit.ID == "" {
+			commit.ID = newCommitID()
+		}
+		gnmicOpts = append(gnmicOpts, api.Extension_CommitRequest(commit.ID, req.RollbackDuration))
+	}
+	if req.commitAction != nil {
+		gnmicOpts = append(gnmicOpts, req.commitAction)
+	}
+	for _, ext := range req.Extensions {
+		gnmicOpts = append(gnmicOpts, api.Extension(ext))
+	}
+
 	setReq, err := api.NewSetRequest(gnmicOpts...)
 	if err != nil {
 		c.logger.Error(ctx, "gNMI Set request creation failed",
@@ -685,13 +1006,19 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 		return SetRes{
 			OK:     false,
 			Errors: []ErrorModel{{Message: err.Error()}},
-		}, fmt.Errorf("set: failed to create request: %w", err)
+		}, newGnmiError("Set", ErrValidation, fmt.Errorf("failed to create request: %w", err), start)
 	}
 
 	// Log request with sanitized operation details
 	c.logger.Debug(ctx, "gNMI Set request",
 		"target", c.Target,
 		"operations", len(ops))
+	if commit != nil {
+		c.logger.Info(ctx, "gNMI commit-confirmed Set",
+			"target", c.Target,
+			"commit", commit.ID,
+			"rollback", req.RollbackDuration.String())
+	}
 
 	// Log each operation with redacted JSON values (at Debug level)
 	for i, op := range ops {
@@ -709,9 +1036,14 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 	// Execute request with retry logic
 	var setResp *gnmipb.SetResponse
 	var lastErr error
+	var retries int
+	var delay time.Duration
+	var refreshed bool
 
 	//nolint:dupl // Get and Set retry logic are similar but have different error handling
-	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+	for attempt := 0; ; attempt++ {
+		retries = attempt
+
 		// Check parent context cancellation before attempt
 		if err := checkContextCancellation(ctx); err != nil {
 			c.logger.Debug(ctx, "set operation canceled",
@@ -721,7 +1053,7 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 			return SetRes{
 				OK:     false,
 				Errors: []ErrorModel{{Message: fmt.Sprintf("context canceled: %s", err.Error())}},
-			}, fmt.Errorf("set: %w", err)
+			}, newGnmiError("Set", nil, err, start)
 		}
 
 		// Create attempt-specific context with timeout
@@ -729,9 +1061,11 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 
 		// Execute Set request with attempt context
 		resp, err := c.target.Set(attemptCtx, setReq)
+		c.observeBytes(ctx, "Set", setReq, resp)
 
 		// Clean up attempt context immediately to prevent goroutine leak
 		attemptCancel()
+		c.recordOutcome(ctx, err)
 		if err == nil {
 			// Success
 			setResp = resp
@@ -742,16 +1076,31 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 		// Store error
 		lastErr = err
 
-		// Extract error details for transient checking
-		errors := c.extractErrorDetails(err)
+		// Reconnect once with reloaded credentials if the target rejected them
+		if !refreshed && c.shouldRefreshCredentials(err) {
+			refreshed = true
+			// Already holding write lock
+			refreshErr := c.refreshCredentials(ctx)
+			if refreshErr == nil {
+				c.observeRetry(ctx, "Set", attempt+1, 0, err)
+				continue
+			}
+		}
 
-		// Check if error is transient and retries remain
-		if c.checkTransientErrorModels(errors) && attempt < c.MaxRetries {
+		// Ask the retry policy whether to retry and how long to wait
+		var retry bool
+		// A commit-confirmed Set is never retried: if the target applied it
+		// before the error, a retry is rejected or starts a second commit
+		if commit == nil {
+			delay, retry = policy.Retry(newRetryAttempt("Set", attempt, err, isIdempotentSet(ops), delay))
+		}
+		if retry && c.circuitClosed() {
 			// Check for transport errors requiring reconnection
 			// Note: Set operation already holds write lock (c.mu.Lock), so no lock upgrade needed
 			if c.isTransportError(lastErr) {
 				// Attempt to reconnect (already holding write lock)
 				if reconnectErr := c.reconnect(ctx); reconnectErr != nil {
+					c.recordOutcome(ctx, reconnectErr)
 					// Reconnection failed, return error
 					c.logger.Error(ctx, "gNMI reconnection failed",
 						"operation", "set",
@@ -759,22 +1108,21 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 					return SetRes{
 						OK:     false,
 						Errors: []ErrorModel{{Message: fmt.Sprintf("operation failed and reconnection failed: %s", reconnectErr.Error())}},
-					}, fmt.Errorf("set: reconnection failed: %w", reconnectErr)
+					}, c.retryError("Set", ErrNotConnected, fmt.Errorf("reconnection failed: %w", reconnectErr), lastErr, attempt, start)
 				}
 				// Reconnection succeeded, continue to retry
 			}
 
-			backoff := c.Backoff(attempt)
-			c.logger.Warn(ctx, "transient error, retrying",
+			c.observeRetry(ctx, "Set", attempt+1, delay, err)
+			c.logger.Warn(ctx, "retryable error, retrying",
 				"operation", "set",
 				"attempt", attempt+1,
-				"max_retries", c.MaxRetries,
-				"backoff", backoff,
+				"backoff", delay,
 				"error", err.Error())
 
 			// Sleep with context cancellation awareness (uses ctx)
 			select {
-			case <-time.After(backoff):
+			case <-time.After(delay):
 				// Backoff complete, continue to next attempt
 				continue
 			case <-ctx.Done():
@@ -785,16 +1133,26 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 				return SetRes{
 					OK:     false,
 					Errors: []ErrorModel{{Message: fmt.Sprintf("context canceled during backoff: %s", ctx.Err().Error())}},
-				}, fmt.Errorf("set: context canceled during backoff: %w", ctx.Err())
+				}, c.retryError("Set", nil, fmt.Errorf("context canceled during backoff: %w", ctx.Err()), lastErr, attempt, start)
 			}
 		} else {
-			// Non-transient error or no retries remaining
+			// Non-retryable error, no retries remaining, or circuit breaker open
 			break
 		}
 	}
 
 	// Check if all retries failed
 	if lastErr != nil {
+		// Rejected by master arbitration: another controller is primary
+		var kind error
+		if isArbitrationError(arbitrated, lastErr) {
+			kind = ErrNotPrimary
+			c.logger.Warn(ctx, "gNMI Set rejected by master arbitration",
+				"target", c.Target,
+				"election_id", req.Extensions.MasterArbitration().GetElectionId().GetLow(),
+				"error", lastErr.Error())
+		}
+
 		c.logger.Error(ctx, "gNMI Set failed",
 			"target", c.Target,
 			"error", lastErr.Error())
@@ -804,7 +1162,8 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 		return SetRes{
 			OK:     false,
 			Errors: errors,
-		}, fmt.Errorf("set: request failed: %w", lastErr)
+			Commit: commit,
+		}, c.retryError("Set", kind, fmt.Errorf("request failed: %w", lastErr), lastErr, retries, start)
 	}
 
 	// Log response
@@ -815,9 +1174,11 @@ func (c *Client) Set(ctx context.Context, ops []SetOperation, mods ...func(*Req)
 	// Parse response
 	timestamp := time.Now().UnixNano()
 	return SetRes{
-		Response:  setResp,
-		Timestamp: timestamp,
-		OK:        true,
+		Response:   setResp,
+		Timestamp:  timestamp,
+		OK:         true,
+		Extensions: setResp.GetExtension(),
+		Commit:     commit,
 	}, nil
 }
 
@@ -896,6 +1257,53 @@ func Replace(path, value string, opts ...func(*SetOperation)) SetOperation {
 	return op
 }
 
+// UnionReplace creates a SetOperation for replacing a path together with the
+// configuration of other origins
+//
+// All union_replace operations of a Set request are merged by the target into
+// one configuration replacing the existing one (gNMI 0.10 union_replace),
+// which allows replacing OpenConfig and CLI configuration in one transaction.
+// Check the gNMI version of the target before use (see Capabilities).
+//
+// The encoding defaults to json_ietf. Use the SetEncoding() modifier to specify
+// a different encoding. Use UnionReplaceCLI for CLI configuration.
+//
+// Example:
+//
+//	ops := []gnmi.SetOperation{
+//	    gnmi.UnionReplace("openconfig:/interfaces", interfacesJSON),
+//	    gnmi.UnionReplaceCLI("router bgp 65000\n  router-id 10.0.0.1\n"),
+//	}
+//	res, err := client.Set(ctx, ops)
+func UnionReplace(path, value string, opts ...func(*SetOperation)) SetOperation {
+	op := SetOperation{
+		OperationType: OperationUnionReplace,
+		Path:          path,
+		Value:         value,
+		Encoding:      EncodingJSONIETF, // default
+	}
+
+	// Apply functional options
+	for _, opt := range opts {
+		opt(&op)
+	}
+
+	return op
+}
+
+// UnionReplaceCLI creates a union_replace SetOperation for native CLI configuration
+//
+// The configuration is sent as ASCII text at the root of the cli origin
+// ("cli:/"). CLI text on other operations is sent the same way, e.g.
+// gnmi.Update("cli:/", config, gnmi.SetEncoding(gnmi.EncodingASCII)).
+//
+// Example:
+//
+//	op := gnmi.UnionReplaceCLI("hostname router1\n")
+func UnionReplaceCLI(config string) SetOperation {
+	return UnionReplace(OriginCLI+":/", config, SetEncoding(EncodingASCII))
+}
+
 // Delete creates a SetOperation for deleting a path
 //
 // Delete operations remove configuration at the specified path.
@@ -914,6 +1322,51 @@ func Delete(path string) SetOperation {
 	}
 }
 
+// UpdatePath is like Update but takes a structured Path
+//
+// Example:
+//
+//	path := gnmi.NewPath().Elem("interfaces").Elem("interface", "name", "Gi0/0/0/0").Elem("config")
+//	op := gnmi.UpdatePath(path, `{"description": "WAN Interface"}`)
+func UpdatePath(path Path, value string, opts ...func(*SetOperation)) SetOperation {
+	op := Update(path.String(), value, opts...)
+	op.pathErr = path.Err()
+	return op
+}
+
+// ReplacePath is like Replace but takes a structured Path
+//
+// Example:
+//
+//	op := gnmi.ReplacePath(gnmi.NewPath().Elem("system").Elem("config"), `{"hostname": "router1"}`)
+func ReplacePath(path Path, value string, opts ...func(*SetOperation)) SetOperation {
+	op := Replace(path.String(), value, opts...)
+	op.pathErr = path.Err()
+	return op
+}
+
+// UnionReplacePath is like UnionReplace but takes a structured Path
+//
+// Example:
+//
+//	op := gnmi.UnionReplacePath(gnmi.NewPath().Origin("openconfig").Elem("system"), systemJSON)
+func UnionReplacePath(path Path, value string, opts ...func(*SetOperation)) SetOperation {
+	op := UnionReplace(path.String(), value, opts...)
+	op.pathErr = path.Err()
+	return op
+}
+
+// DeletePath is like Delete but takes a structured Path
+//
+// Example:
+//
+//	op := gnmi.DeletePath(gnmi.NewPath().Elem("interfaces").Elem("interface", "name", "Gi0/0/0/1"))
+func DeletePath(path Path) SetOperation {
+	op := Delete(path.String())
+	op.pathErr = path.Err()
+	return op
+}
+
 // Internal helper methods
 
 // calculateTotalTimeout calculates the total timeout for all retry attempts
@@ -1064,11 +1517,31 @@ func (c *Client) extractErrorDetails(err error) []ErrorModel {
 	}}
 }
 
+// retryError creates a GnmiError for an operation that failed in the retry loop
+//
+// The error details, gRPC status code, and transient flag are taken from
+// lastErr, the last error returned by the target. retries is the number of
+// retries made.
+func (c *Client) retryError(operation string, kind, err, lastErr error, retries int, start time.Time) *GnmiError {
+	gnmiErr := newGnmiError(operation, kind, err, start)
+	gnmiErr.Errors = c.extractErrorDetails(lastErr)
+	gnmiErr.IsTransient = c.checkTransientErrorModels(gnmiErr.Errors)
+	gnmiErr.Retries = retries
+	if gnmiErr.Code == codes.OK {
+		gnmiErr.Code = status.Code(lastErr)
+	}
+	return gnmiErr
+}
+
 // isValidGNMIPath checks if a path is in valid gNMI format
 //
 // Valid formats:
 //  1. Absolute path: /interfaces/interface[name=eth0]
-//  2. Module-qualified path: module-name:/path (e.g., openconfig-interfaces:/interfaces)
+//  2. Path with origin: origin:/path (e.g., openconfig:/interfaces, cli:/,
+//     or module-qualified Cisco-IOS-XR-um-banner-cfg:/banners)
+//
+// Origins follow the same rules as ParsePath: a colon inside an element name
+// ("/openconfig-interfaces:interfaces") is not an origin.
 //
 // Returns true if the path is valid, false otherwise.
 func isValidGNMIPath(path string) bool {
@@ -1081,14 +1554,7 @@ func isValidGNMIPath(path string) bool {
 		return true
 	}
 
-	// Check for module-qualified path (module:path format)
-	// Must contain : and the part after : must start with /
-	colonIdx := strings.IndexByte(path, ':')
-	if colonIdx > 0 && colonIdx < len(path)-1 {
-		// Has module prefix, check if path part starts with /
-		pathPart := path[colonIdx+1:]
-		return len(pathPart) > 0 && pathPart[0] == '/'
-	}
-
-	return false
+	// Check for origin prefix; the path part must be absolute
+	_, rest, ok := splitOrigin(path)
+	return ok && strings.HasPrefix(rest, "/")
 }
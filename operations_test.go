@@ -5,6 +5,7 @@ package gnmi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
@@ -139,6 +140,39 @@ func TestReplace(t *testing.T) {
 	}
 }
 
+// TestUnionReplace tests the UnionReplace and UnionReplaceCLI helper functions
+func TestUnionReplace(t *testing.T) {
+	tests := []struct {
+		name string
+		got  SetOperation
+		want SetOperation
+	}{
+		{
+			name: "default encoding",
+			got:  UnionReplace("openconfig:/system/config", `{"hostname": "router1"}`),
+			want: SetOperation{OperationType: OperationUnionReplace, Path: "openconfig:/system/config", Value: `{"hostname": "router1"}`, Encoding: EncodingJSONIETF},
+		},
+		{
+			name: "structured path",
+			got:  UnionReplacePath(NewPath().Origin("openconfig").Elem("system"), `{}`, SetEncoding(EncodingJSON)),
+			want: SetOperation{OperationType: OperationUnionReplace, Path: "openconfig:/system", Value: `{}`, Encoding: EncodingJSON},
+		},
+		{
+			name: "CLI configuration",
+			got:  UnionReplaceCLI("hostname router1\n"),
+			want: SetOperation{OperationType: OperationUnionReplace, Path: "cli:/", Value: "hostname router1\n", Encoding: EncodingASCII},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
 // TestDelete tests the Delete helper function
 func TestDelete(t *testing.T) {
 	tests := []struct {
@@ -753,7 +787,7 @@ func TestGetValidationCanceledContext(t *testing.T) {
 	if err == nil {
 		t.Fatal("Get() should return error for canceled context")
 	}
-	if err != context.Canceled {
+	if !errors.Is(err, context.Canceled) {
 		t.Errorf("Expected context.Canceled error, got: %v", err)
 	}
 	if res.OK {
@@ -868,7 +902,7 @@ func TestSetValidationCanceledContext(t *testing.T) {
 	if err == nil {
 		t.Fatal("Set() should return error for canceled context")
 	}
-	if err != context.Canceled {
+	if !errors.Is(err, context.Canceled) {
 		t.Errorf("Expected context.Canceled error, got: %v", err)
 	}
 	if res.OK {
@@ -918,7 +952,7 @@ func TestCapabilitiesValidationCanceledContext(t *testing.T) {
 	if err == nil {
 		t.Fatal("Capabilities() should return error for canceled context")
 	}
-	if err != context.Canceled {
+	if !errors.Is(err, context.Canceled) {
 		t.Errorf("Expected context.Canceled error, got: %v", err)
 	}
 	if res.OK {
@@ -943,41 +977,9 @@ func expiredContext() context.Context {
 	return ctx
 }
 
-// NOTE: Context Cancellation Integration Tests (Future Enhancement)
-//
-// The tests above validate context cancellation at operation entry points (validation paths).
-// Future integration tests could extend this coverage to include:
-//
-// 1. Context cancellation during retry loops:
-//    - Cancel context between retry attempts
-//    - Verify immediate return without additional retries
-//    - Test with various retry configurations
-//
-// 2. Context cancellation during backoff delays:
-//    - Cancel context while waiting in backoff.Sleep()
-//    - Verify immediate wake-up and return
-//    - Test with different backoff configurations
-//
-// 3. Context cancellation during gnmic operations:
-//    - Cancel context during target.Get() execution
-//    - Cancel context during target.Set() execution
-//    - Verify proper cleanup and error propagation
-//
-// 4. Context deadline exceeded scenarios:
-//    - Deadline expires during retry loop
-//    - Deadline expires during backoff delay
-//    - Deadline expires during gnmic operation
-//
-// These integration tests require:
-// - Mock gnmic target that can simulate long-running operations
-// - Controllable timing to test cancellation at specific points
-// - Verification of proper resource cleanup
-//
-// Current tests provide foundational coverage of:
-// - checkContextCancellation() function (100% coverage)
-// - Context validation at Get/Set/Capabilities entry points
-// - Correct error types (context.Canceled, context.DeadlineExceeded)
-// - Response structure (OK=false, proper error messages)
+// NOTE: The tests above validate context cancellation at operation entry points
+// (validation paths). Cancellation during retry loops and backoff delays is
+// covered by the gnmitest-based tests in integration_test.go.
 
 // TestTotalTimeoutBudget verifies that Get operations respect the total timeout budget
 // to prevent unbounded timeout accumulation across retries.
@@ -1755,48 +1757,9 @@ func TestRetryLogicErrorExtraction(t *testing.T) {
 	}
 }
 
-// Future Enhancement: Full integration tests with mock gnmic target
-//
-// The following test scenarios could be added when mock gnmic target
-// infrastructure becomes available:
-//
-// 1. TestGetRetryTransientError
-//    - Mock target returns Unavailable 2 times, then success
-//    - Verify Get() retries 2 times and succeeds
-//    - Verify total attempts = 3 (initial + 2 retries)
-//
-// 2. TestGetRetryPermanentError
-//    - Mock target returns InvalidArgument (permanent error)
-//    - Verify Get() fails immediately without retry
-//    - Verify total attempts = 1
-//
-// 3. TestGetRetryMaxRetriesExceeded
-//    - Mock target always returns Unavailable
-//    - Client configured with MaxRetries = 2
-//    - Verify Get() retries 2 times then fails
-//    - Verify error message indicates max retries exceeded
-//
-// 4. TestGetRetryContextCanceled
-//    - Mock target returns Unavailable
-//    - Context canceled before retry
-//    - Verify Get() returns immediately with context.Canceled
-//
-// 5. TestGetRetryContextCanceledDuringBackoff
-//    - Mock target returns Unavailable
-//    - Context canceled during backoff sleep
-//    - Verify Get() returns immediately from backoff select
-//
-// 6. TestSetRetryTransientError (mirror Get scenarios)
-//    - Same scenarios as Get, but for Set operation
-//    - Verify Set-specific behavior (Lock instead of RLock)
-//
-// Implementation notes:
-// - Use gnmic target mocks or test server
-// - Track attempt counts via instrumentation
-// - Verify backoff timing with tolerance
-// - Test concurrent retry operations (race detector)
-//
-// Note: Requires mock gnmic target infrastructure for full implementation
+// Retry behavior against a running server (transient, permanent, and exhausted
+// retries, reconnection, cancellation during backoff) is covered by the
+// gnmitest-based tests in integration_test.go.
 
 // =============================================================================
 // Input Validation & Security Tests
@@ -2008,6 +1971,93 @@ func TestInputValidation_EncodingValidation(t *testing.T) {
 	}
 }
 
+// TestInputValidation_DataTypeValidation tests Get data type validation
+func TestInputValidation_DataTypeValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		dataType    DataType
+		expectError bool
+	}{
+		{name: "all", dataType: DataTypeAll},
+		{name: "config", dataType: DataTypeConfig},
+		{name: "state", dataType: DataTypeState},
+		{name: "operational", dataType: DataTypeOperational},
+		{name: "empty data type (defaults to all)", dataType: ""},
+		{name: "invalid data type", dataType: "running", expectError: true},
+		{name: "case sensitive", dataType: "CONFIG", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDataType(tt.dataType)
+			if tt.expectError && err == nil {
+				t.Errorf("expected error for data type %q but got none", tt.dataType)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error for valid data type %q: %v", tt.dataType, err)
+			}
+		})
+	}
+}
+
+// TestIsValidGNMIPath tests path format checks including origins
+func TestIsValidGNMIPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/interfaces", want: true},
+		{path: "/openconfig-interfaces:interfaces", want: true},
+		{path: "openconfig:/interfaces", want: true},
+		{path: "Cisco-IOS-XR-um-banner-cfg:/banners", want: true},
+		{path: "cli:/", want: true},
+		{path: "cli:", want: false},
+		{path: "interfaces", want: false},
+		{path: "a/b:/c", want: false},
+		{path: ":/interfaces", want: false},
+		{path: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isValidGNMIPath(tt.path); got != tt.want {
+				t.Errorf("isValidGNMIPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestInputValidation_RequestPrefix tests Prefix and Origin modifier validation
+func TestInputValidation_RequestPrefix(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         Req
+		expectError bool
+	}{
+		{name: "empty", req: Req{}},
+		{name: "prefix", req: Req{Prefix: "/interfaces/interface[name=eth0]"}},
+		{name: "prefix with origin", req: Req{Prefix: "openconfig:/interfaces", Origin: "openconfig"}},
+		{name: "origin only", req: Req{Origin: "rfc7951"}},
+		{name: "target only", req: Req{Target: "router1"}},
+		{name: "invalid prefix", req: Req{Prefix: "interfaces"}, expectError: true},
+		{name: "malformed prefix", req: Req{Prefix: "/interface[name=eth0"}, expectError: true},
+		{name: "invalid origin", req: Req{Origin: "a/b"}, expectError: true},
+		{name: "conflicting origin", req: Req{Prefix: "openconfig:/interfaces", Origin: "cli"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRequestPrefix(&tt.req)
+			if tt.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
 // TestInputValidation_SetOperations tests SetOperation validation
 func TestInputValidation_SetOperations(t *testing.T) {
 	tests := []struct {
@@ -2083,6 +2133,55 @@ func TestInputValidation_SetOperations(t *testing.T) {
 			expectError: true,
 			errorMsg:    "exceeds maximum",
 		},
+		{
+			name: "valid union replace with CLI configuration",
+			ops: []SetOperation{
+				UnionReplace("openconfig:/system/config", `{"hostname": "router1"}`),
+				UnionReplaceCLI("router bgp 65000\n"),
+				Update("cli:/", "ntp server 10.0.0.1\n", SetEncoding(EncodingASCII)),
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid JSON in union replace",
+			ops: []SetOperation{
+				UnionReplace("/config", `{invalid`),
+			},
+			expectError: true,
+			errorMsg:    "invalid JSON syntax",
+		},
+		{
+			name: "CLI configuration with JSON encoding",
+			ops: []SetOperation{
+				UnionReplace("cli:/", `"hostname router1"`),
+			},
+			expectError: true,
+			errorMsg:    "cli origin requires ascii encoding",
+		},
+		{
+			name: "CLI configuration below root",
+			ops: []SetOperation{
+				UnionReplace("cli:/router/bgp", "router bgp 65000", SetEncoding(EncodingASCII)),
+			},
+			expectError: true,
+			errorMsg:    "must be the root path",
+		},
+		{
+			name: "empty CLI configuration",
+			ops: []SetOperation{
+				UnionReplaceCLI("  \n"),
+			},
+			expectError: true,
+			errorMsg:    "cli configuration cannot be empty",
+		},
+		{
+			name: "CLI delete",
+			ops: []SetOperation{
+				Delete("cli:/"),
+			},
+			expectError: true,
+			errorMsg:    "does not support delete",
+		},
 	}
 
 	for _, tt := range tests {
@@ -51,6 +51,22 @@ func TLSCA(caPath string) func(*Client) {
 	}
 }
 
+// ConnectionPool adds the client to a connection pool
+//
+// The pool disconnects the client when idle, health-checks its connection,
+// and limits the number of open connections shared with other pooled
+// clients. See Pool.
+//
+// Example:
+//
+//	pool := gnmi.NewPool(gnmi.IdleTimeout(time.Minute))
+//	client, err := gnmi.NewClient("192.168.1.1:57400", gnmi.ConnectionPool(pool))
+func ConnectionPool(pool *Pool) func(*Client) {
+	return func(c *Client) {
+		c.pool = pool
+	}
+}
+
 // Port sets the gNMI port (default: 57400)
 func Port(port int) func(*Client) {
 	return func(c *Client) {
@@ -129,6 +145,22 @@ func BackoffDelayFactor(factor float64) func(*Client) {
 	}
 }
 
+// WithRetryPolicy sets the retry policy of the client
+//
+// The policy replaces MaxRetries and the backoff settings for Get and Set,
+// and decides whether dropped subscriptions are resubscribed. See RetryPolicy.
+//
+// Example:
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.WithRetryPolicy(gnmi.IdempotentOnly(gnmi.DecorrelatedJitter{MaxRetries: 5})),
+//	)
+func WithRetryPolicy(policy RetryPolicy) func(*Client) {
+	return func(c *Client) {
+		c.RetryPolicy = policy
+	}
+}
+
 // WithLogger configures a custom logger for the client
 //
 // By default, the client uses NoOpLogger which discards all log messages.
@@ -218,6 +250,20 @@ func Timeout(duration time.Duration) func(*Req) {
 	}
 }
 
+// RequestRetryPolicy returns a request modifier that sets the retry policy
+// of a single Get, Set, or Subscribe operation, overriding the client policy.
+//
+// Example:
+//
+//	// Never retry this Set
+//	res, err := client.Set(ctx, ops,
+//	    gnmi.RequestRetryPolicy(gnmi.NoRetry()))
+func RequestRetryPolicy(policy RetryPolicy) func(*Req) {
+	return func(req *Req) {
+		req.RetryPolicy = policy
+	}
+}
+
 // GetEncoding returns a request modifier that sets the encoding for Get operations.
 //
 // Valid encodings: json, json_ietf (default), proto, ascii, bytes
@@ -255,6 +301,86 @@ func GetEncoding(encoding string) func(*Req) {
 	}
 }
 
+// GetDataType returns a request modifier that sets the data type for Get operations.
+//
+// Valid data types: all (default), config, state, operational
+//
+// Restricting the data type reduces response size on large devices:
+//   - config: read-write configuration only (e.g., configuration backups)
+//   - state: read-only state data (e.g., monitoring)
+//   - operational: read-only state not derived from configuration (e.g., counters)
+//
+// The modifier validates the data type at request time. If an invalid data type
+// is provided, the operation will fail with an error.
+//
+// Example:
+//
+//	// Back up configuration only
+//	res, err := client.Get(ctx, []string{"/"},
+//	    gnmi.GetDataType(gnmi.DataTypeConfig))
+//
+//	// Monitor interface state
+//	res, err := client.Get(ctx, []string{"/interfaces"},
+//	    gnmi.GetDataType(gnmi.DataTypeState))
+func GetDataType(dataType DataType) func(*Req) {
+	return func(req *Req) {
+		req.DataType = dataType
+	}
+}
+
+// Prefix returns a request modifier that sets the request prefix path.
+//
+// The prefix is sent in GetRequest.prefix, SetRequest.prefix, or
+// SubscriptionList.prefix and the target prepends it to all paths of the
+// request, so paths and Set operations can be written relative to it.
+// The prefix may carry an origin ("openconfig:/interfaces").
+//
+// Example:
+//
+//	// Get config and state of one interface
+//	res, err := client.Get(ctx, []string{"/config", "/state"},
+//	    gnmi.Prefix("/interfaces/interface[name=Gi0/0/0/0]"))
+func Prefix(path string) func(*Req) {
+	return func(req *Req) {
+		req.Prefix = path
+	}
+}
+
+// Origin returns a request modifier that sets the origin of all paths.
+//
+// Common origins: openconfig, cli, rfc7951, or vendor-specific origins.
+//
+// If a prefix is sent (see Prefix and Target), the origin is set on the prefix
+// as required by the gNMI specification. Otherwise it is set on every path
+// that does not carry its own origin ("origin:/path").
+//
+// Example:
+//
+//	res, err := client.Get(ctx, []string{"/interfaces"},
+//	    gnmi.Origin("openconfig"))
+func Origin(origin string) func(*Req) {
+	return func(req *Req) {
+		req.Origin = origin
+	}
+}
+
+// Target returns a request modifier that sets the gNMI target name.
+//
+// The target name is sent in the request prefix and selects the device when
+// talking to a gNMI gateway or proxy that multiplexes several targets. It is
+// unrelated to the network address given to NewClient.
+//
+// Example:
+//
+//	// Client connected to a gNMI gateway
+//	res, err := client.Get(ctx, []string{"/system/config"},
+//	    gnmi.Target("router1"))
+func Target(name string) func(*Req) {
+	return func(req *Req) {
+		req.Target = name
+	}
+}
+
 // SetEncoding returns a modifier that sets the encoding for individual Set operations.
 //
 // Valid encodings: json, json_ietf (default), proto, ascii, bytes
@@ -295,3 +421,107 @@ func SetEncoding(encoding string) func(*SetOperation) {
 		}
 	}
 }
+
+// SubscribeMode returns a request modifier that sets the subscription list mode
+// for Subscribe operations.
+//
+// Valid modes: stream (default), once, poll
+//
+// The modifier validates the mode at request time. If an invalid mode is
+// provided, the operation will fail with an error.
+//
+// Example:
+//
+//	// Retrieve the current state once
+//	stream, err := client.Subscribe(ctx, subs,
+//	    gnmi.SubscribeMode(gnmi.SubscribeModeOnce))
+//
+//	// Poll on demand
+//	stream, err := client.Subscribe(ctx, subs,
+//	    gnmi.SubscribeMode(gnmi.SubscribeModePoll))
+func SubscribeMode(mode SubscriptionListMode) func(*Req) {
+	return func(req *Req) {
+		req.Mode = mode
+	}
+}
+
+// SubscribeEncoding returns a request modifier that sets the encoding for Subscribe operations.
+//
+// Valid encodings: json, json_ietf (default), proto, ascii, bytes
+//
+// Example:
+//
+//	stream, err := client.Subscribe(ctx, subs,
+//	    gnmi.SubscribeEncoding("proto"))
+func SubscribeEncoding(encoding string) func(*Req) {
+	return func(req *Req) {
+		req.Encoding = encoding
+	}
+}
+
+// UpdatesOnly returns a request modifier that suppresses the initial state of
+// Subscribe operations.
+//
+// When enabled, the target sends a sync response immediately and only streams
+// changes that occur after the subscription was created.
+//
+// Example:
+//
+//	stream, err := client.Subscribe(ctx, subs,
+//	    gnmi.UpdatesOnly(true))
+func UpdatesOnly(enabled bool) func(*Req) {
+	return func(req *Req) {
+		req.UpdatesOnly = enabled
+	}
+}
+
+// Resubscribe returns a request modifier that enables or disables automatic
+// resubscription of Subscribe operations (default: enabled).
+//
+// When enabled, a stream that drops with a transient gRPC error is
+// re-established using the client's backoff settings, and a SubscribeRes with
+// Reconnected set is delivered. The target then resends its full state,
+// except with UpdatesOnly(true): only changes after the resubscription are
+// streamed, so changes made while the stream was down are missed. When
+// disabled, the stream ends and Err() reports the error.
+//
+// Example:
+//
+//	// Fail fast instead of resubscribing
+//	stream, err := client.Subscribe(ctx, subs,
+//	    gnmi.Resubscribe(false))
+func Resubscribe(enabled bool) func(*Req) {
+	return func(req *Req) {
+		req.Resubscribe = enabled
+	}
+}
+
+// HeartbeatInterval returns a modifier that sets the heartbeat interval of a Subscription.
+//
+// The target resends the value at this interval even if it did not change.
+// Used with OnChange() subscriptions and with Sample() subscriptions that
+// suppress redundant updates.
+//
+// Example:
+//
+//	sub := gnmi.OnChange("/interfaces/interface/state/oper-status",
+//	    gnmi.HeartbeatInterval(5*time.Minute))
+func HeartbeatInterval(interval time.Duration) func(*Subscription) {
+	return func(sub *Subscription) {
+		sub.HeartbeatInterval = interval
+	}
+}
+
+// SuppressRedundant returns a modifier that suppresses unchanged values in
+// Sample() subscriptions.
+//
+// Example:
+//
+//	sub := gnmi.Sample("/system/state", 10*time.Second,
+//	    gnmi.SuppressRedundant(true),
+//	    gnmi.HeartbeatInterval(time.Minute))
+func SuppressRedundant(enabled bool) func(*Subscription) {
+	return func(sub *Subscription) {
+		sub.SuppressRedundant = enabled
+	}
+}
@@ -0,0 +1,452 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmic/pkg/api"
+	"google.golang.org/protobuf/proto"
+)
+
+// Path is a structured gNMI path
+//
+// A Path is either parsed from an XPath-style string with ParsePath() or
+// built element by element with NewPath(). Like Body, the builder tracks
+// errors internally to enable method chaining; check Err() before use.
+//
+// Path values are immutable: every builder method returns a new Path.
+//
+// Example:
+//
+//	path := gnmi.NewPath().
+//	    Elem("interfaces").
+//	    Elem("interface", "name", "Gi0/0/0/0").
+//	    Elem("config")
+//	if err := path.Err(); err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(path) // /interfaces/interface[name=Gi0/0/0/0]/config
+type Path struct {
+	// origin is the gNMI path origin (e.g., "openconfig", "cli")
+	origin string
+	// elems are the path elements (never modified after creation)
+	elems []*gnmipb.PathElem
+	// err tracks the first error encountered during building
+	err error
+}
+
+// NewPath creates an empty (root) Path for use with the builder methods
+//
+// Example:
+//
+//	path := gnmi.NewPath().Elem("system").Elem("config").Elem("hostname")
+func NewPath() Path {
+	return Path{}
+}
+
+// ParsePath parses an XPath-style gNMI path string into a Path
+//
+// Supported syntax:
+//   - Optional origin prefix: "openconfig:/interfaces"
+//   - Elements separated by "/": "/interfaces/interface/config"
+//   - List keys in brackets: "/interface[name=Gi0/0/0/0]" (multiple keys
+//     are written as consecutive brackets)
+//   - Backslash escapes for special characters: "\]", "\[", "\=", "\/", and "\\"
+//
+// Inside key values only "]" and "\" need escaping; "/" and "=" are taken
+// literally, so "[name=Gi0/0/0/0]" and "[expr=a=b]" parse as expected.
+//
+// Example:
+//
+//	path, err := gnmi.ParsePath(`/interfaces/interface[name=Gi0/0/0/0]/config`)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Returns the parsed Path or an error describing the malformed part.
+func ParsePath(s string) (Path, error) {
+	if s == "" {
+		return Path{}, fmt.Errorf("path cannot be empty")
+	}
+
+	p := Path{}
+	origin, rest, _ := splitOrigin(s)
+	p.origin = origin
+
+	elems, err := parsePathElems(rest)
+	if err != nil {
+		return Path{}, fmt.Errorf("malformed path %q: %w", s, err)
+	}
+	p.elems = elems
+
+	return p, nil
+}
+
+// splitOrigin splits an "origin:/path" string into origin and path
+//
+// The origin must be non-empty, must not contain "/", "[" or "\", and must be
+// followed by "/" or the end of the string. Element names with module prefixes
+// ("/openconfig-interfaces:interfaces") are therefore not mistaken for origins.
+//
+// Returns ok=false and the unchanged string if s has no origin.
+func splitOrigin(s string) (origin, rest string, ok bool) {
+	idx := strings.IndexByte(s, ':')
+	if idx <= 0 || strings.ContainsAny(s[:idx], `/[\`) {
+		return "", s, false
+	}
+	if idx != len(s)-1 && s[idx+1] != '/' {
+		return "", s, false
+	}
+	return s[:idx], s[idx+1:], true
+}
+
+// validateOrigin checks that an origin name can be rendered in a path string
+//
+// Returns an error if the origin contains ":", "/", "[", or "\".
+func validateOrigin(origin string) error {
+	if strings.ContainsAny(origin, `:/[\`) {
+		return fmt.Errorf("origin cannot contain ':', '/', '[', or '\\': %s", origin)
+	}
+	return nil
+}
+
+// parsePathElems parses the element part of a path string (without origin)
+func parsePathElems(s string) ([]*gnmipb.PathElem, error) {
+	var elems []*gnmipb.PathElem
+
+	i := 0
+	if strings.HasPrefix(s, "/") {
+		i = 1
+	}
+
+	for i < len(s) {
+		name, next, err := scanPathToken(s, i, "/[")
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, fmt.Errorf("empty element name at offset %d", i)
+		}
+		elem := &gnmipb.PathElem{Name: name}
+		i = next
+
+		// Keys: [k=v][k2=v2]...
+		for i < len(s) && s[i] == '[' {
+			key, next, err := scanPathToken(s, i+1, "=]")
+			if err != nil {
+				return nil, err
+			}
+			if next >= len(s) || s[next] != '=' {
+				return nil, fmt.Errorf("key %q of element %q has no value", key, name)
+			}
+			if key == "" {
+				return nil, fmt.Errorf("empty key name in element %q", name)
+			}
+
+			value, next, err := scanPathToken(s, next+1, "]")
+			if err != nil {
+				return nil, err
+			}
+			if next >= len(s) {
+				return nil, fmt.Errorf("unterminated key %q in element %q", key, name)
+			}
+
+			if elem.Key == nil {
+				elem.Key = make(map[string]string)
+			}
+			if _, ok := elem.Key[key]; ok {
+				return nil, fmt.Errorf("duplicate key %q in element %q", key, name)
+			}
+			elem.Key[key] = value
+			i = next + 1
+		}
+
+		elems = append(elems, elem)
+
+		if i < len(s) {
+			if s[i] != '/' {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", s[i], i)
+			}
+			i++
+		}
+	}
+
+	return elems, nil
+}
+
+// scanPathToken reads an unescaped token starting at offset start until one of
+// the stop characters or the end of the string
+//
+// Returns the unescaped token and the offset of the stop character (or len(s)).
+func scanPathToken(s string, start int, stop string) (string, int, error) {
+	var b strings.Builder
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' {
+			if i+1 >= len(s) {
+				return "", 0, fmt.Errorf("trailing escape character at offset %d", i)
+			}
+			i++
+			b.WriteByte(s[i])
+			continue
+		}
+		if strings.IndexByte(stop, c) >= 0 {
+			return b.String(), i, nil
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), len(s), nil
+}
+
+// Origin sets the path origin and returns a new Path
+//
+// Example:
+//
+//	path := gnmi.NewPath().Origin("openconfig").Elem("interfaces")
+//	fmt.Println(path) // openconfig:/interfaces
+//
+// Returns the Path for method chaining.
+func (p Path) Origin(origin string) Path {
+	if p.err != nil {
+		return p
+	}
+	if err := validateOrigin(origin); err != nil {
+		p.err = fmt.Errorf("Origin(%q): %w", origin, err)
+		return p
+	}
+	p.origin = origin
+	return p
+}
+
+// Elem appends a path element and returns a new Path
+//
+// List keys are given as alternating name/value pairs. Key values are used
+// verbatim, so no escaping is needed when building paths.
+//
+// If an error occurs (empty name, odd number of key arguments, empty or
+// duplicate key name), the error is stored and returned by Err(). Once an
+// error occurs, all subsequent operations are no-ops that preserve the error.
+//
+// Example:
+//
+//	path := gnmi.NewPath().
+//	    Elem("interfaces").
+//	    Elem("interface", "name", "Gi0/0/0/0")
+//
+// Returns the Path for method chaining.
+func (p Path) Elem(name string, keyValues ...string) Path {
+	if p.err != nil {
+		return p
+	}
+	if name == "" {
+		p.err = fmt.Errorf("Elem(): element name cannot be empty")
+		return p
+	}
+	if len(keyValues)%2 != 0 {
+		p.err = fmt.Errorf("Elem(%q): keys must be name/value pairs, got %d arguments", name, len(keyValues))
+		return p
+	}
+
+	elem := &gnmipb.PathElem{Name: name}
+	for i := 0; i < len(keyValues); i += 2 {
+		key := keyValues[i]
+		if key == "" {
+			p.err = fmt.Errorf("Elem(%q): key name cannot be empty", name)
+			return p
+		}
+		if elem.Key == nil {
+			elem.Key = make(map[string]string, len(keyValues)/2)
+		}
+		if _, ok := elem.Key[key]; ok {
+			p.err = fmt.Errorf("Elem(%q): duplicate key %q", name, key)
+			return p
+		}
+		elem.Key[key] = keyValues[i+1]
+	}
+
+	// Copy elements so that Paths derived from the same parent do not share
+	// the backing array
+	elems := make([]*gnmipb.PathElem, len(p.elems), len(p.elems)+1)
+	copy(elems, p.elems)
+	p.elems = append(elems, elem)
+	return p
+}
+
+// Err returns the first error encountered while building the Path
+//
+// Returns nil if the Path is valid.
+func (p Path) Err() error {
+	return p.err
+}
+
+// Proto returns the Path as a gNMI protobuf path
+//
+// The returned message is a copy and may be modified by the caller.
+func (p Path) Proto() *gnmipb.Path {
+	gp := &gnmipb.Path{Origin: p.origin}
+	for _, elem := range p.elems {
+		gp.Elem = append(gp.Elem, proto.Clone(elem).(*gnmipb.PathElem))
+	}
+	return gp
+}
+
+// String renders the Path in canonical XPath-style form
+//
+// List keys are sorted by name and special characters are escaped, so the
+// output parses back to the same Path with ParsePath().
+//
+// Example output: openconfig:/interfaces/interface[name=Gi0/0/0/0]/config
+func (p Path) String() string {
+	var b strings.Builder
+	if p.origin != "" {
+		b.WriteString(p.origin)
+		b.WriteString(":")
+	}
+	if len(p.elems) == 0 {
+		b.WriteString("/")
+	}
+
+	for _, elem := range p.elems {
+		b.WriteString("/")
+		b.WriteString(escapePathToken(elem.GetName(), `/[]`))
+
+		keys := make([]string, 0, len(elem.GetKey()))
+		for k := range elem.GetKey() {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			b.WriteString("[")
+			b.WriteString(escapePathToken(k, `=[]`))
+			b.WriteString("=")
+			b.WriteString(escapePathToken(elem.GetKey()[k], `[]`))
+			b.WriteString("]")
+		}
+	}
+
+	return b.String()
+}
+
+// escapePathToken escapes backslashes and the given special characters
+func escapePathToken(s, special string) string {
+	if !strings.ContainsAny(s, special+`\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' || strings.IndexByte(special, s[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// buildPrefix builds the request prefix from the Prefix, Origin, and Target
+// request modifiers
+//
+// The request origin is placed in the prefix whenever a prefix is sent, as
+// required by the gNMI specification; see requestPath for the other case.
+//
+// PRECONDITION: The request must be validated (see validateRequestPrefix).
+//
+// Returns nil if the request has neither a prefix path nor a target.
+func buildPrefix(req *Req) (*gnmipb.Path, error) {
+	if req.Prefix == "" && req.Target == "" {
+		return nil, nil
+	}
+
+	prefix := &gnmipb.Path{}
+	if req.Prefix != "" {
+		p, err := ParsePath(req.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("prefix: %w", err)
+		}
+		prefix = p.Proto()
+	}
+	if prefix.Origin == "" {
+		prefix.Origin = req.Origin
+	}
+	prefix.Target = req.Target
+
+	return prefix, nil
+}
+
+// requestPath parses a path string into a gNMI protobuf path
+//
+// If the request has an Origin but no prefix carries it, the origin is set
+// on the path unless the path has its own origin.
+func requestPath(path string, req *Req, prefix *gnmipb.Path) (*gnmipb.Path, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	gp := p.Proto()
+	if prefix == nil && gp.Origin == "" {
+		gp.Origin = req.Origin
+	}
+	return gp, nil
+}
+
+// protoPrefix returns a gnmic option that sets an already built prefix
+func protoPrefix(prefix *gnmipb.Path) api.GNMIOption {
+	return func(msg proto.Message) error {
+		if prefix == nil {
+			return nil
+		}
+		switch msg := msg.ProtoReflect().Interface().(type) {
+		case *gnmipb.GetRequest:
+			msg.Prefix = prefix
+		case *gnmipb.SetRequest:
+			msg.Prefix = prefix
+		case *gnmipb.SubscribeRequest:
+			list := msg.GetSubscribe()
+			if list == nil {
+				return fmt.Errorf("prefix option: subscribe request has no subscription list")
+			}
+			list.Prefix = prefix
+		default:
+			return fmt.Errorf("prefix option: unsupported message type %T", msg)
+		}
+		return nil
+	}
+}
+
+// protoPath returns a gnmic option that sets an already parsed path
+//
+// Used instead of api.Path() so that all requests are built from paths
+// parsed by ParsePath(), which handles more escape sequences than gnmic.
+func protoPath(gp *gnmipb.Path) api.GNMIOption {
+	return func(msg proto.Message) error {
+		switch msg := msg.ProtoReflect().Interface().(type) {
+		case *gnmipb.GetRequest:
+			msg.Path = append(msg.Path, gp)
+		case *gnmipb.Update:
+			msg.Path = gp
+		case *gnmipb.Subscription:
+			msg.Path = gp
+		default:
+			return fmt.Errorf("path option: unsupported message type %T", msg)
+		}
+		return nil
+	}
+}
+
+// protoDelete returns a gnmic option that adds an already parsed delete path
+// to a SetRequest
+func protoDelete(gp *gnmipb.Path) api.GNMIOption {
+	return func(msg proto.Message) error {
+		setReq, ok := msg.ProtoReflect().Interface().(*gnmipb.SetRequest)
+		if !ok {
+			return fmt.Errorf("delete option: unsupported message type %T", msg)
+		}
+		setReq.Delete = append(setReq.Delete, gp)
+		return nil
+	}
+}
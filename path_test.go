@@ -0,0 +1,301 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"strings"
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestParsePath tests parsing of XPath-style path strings
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    *gnmipb.Path
+		wantErr string
+	}{
+		{
+			name: "root",
+			path: "/",
+			want: &gnmipb.Path{},
+		},
+		{
+			name: "simple",
+			path: "/system/config/hostname",
+			want: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "system"}, {Name: "config"}, {Name: "hostname"}}},
+		},
+		{
+			name: "key with slashes",
+			path: "/interfaces/interface[name=Gi0/0/0/0]/config",
+			want: &gnmipb.Path{Elem: []*gnmipb.PathElem{
+				{Name: "interfaces"},
+				{Name: "interface", Key: map[string]string{"name": "Gi0/0/0/0"}},
+				{Name: "config"},
+			}},
+		},
+		{
+			name: "multiple keys",
+			path: "/protocols/protocol[identifier=BGP][name=65000]",
+			want: &gnmipb.Path{Elem: []*gnmipb.PathElem{
+				{Name: "protocols"},
+				{Name: "protocol", Key: map[string]string{"identifier": "BGP", "name": "65000"}},
+			}},
+		},
+		{
+			name: "origin",
+			path: "openconfig:/interfaces",
+			want: &gnmipb.Path{Origin: "openconfig", Elem: []*gnmipb.PathElem{{Name: "interfaces"}}},
+		},
+		{
+			name: "origin root",
+			path: "cli:/",
+			want: &gnmipb.Path{Origin: "cli"},
+		},
+		{
+			name: "module-qualified element",
+			path: "/openconfig-interfaces:interfaces",
+			want: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "openconfig-interfaces:interfaces"}}},
+		},
+		{
+			name: "escaped characters in key",
+			path: `/acl/entry[match\=exp=a\]b=c\\d]`,
+			want: &gnmipb.Path{Elem: []*gnmipb.PathElem{
+				{Name: "acl"},
+				{Name: "entry", Key: map[string]string{"match=exp": `a]b=c\d`}},
+			}},
+		},
+		{
+			name: "escaped slash in name",
+			path: `/a\/b/c`,
+			want: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "a/b"}, {Name: "c"}}},
+		},
+		{
+			name: "wildcards",
+			path: "/interfaces/interface[name=*]/...",
+			want: &gnmipb.Path{Elem: []*gnmipb.PathElem{
+				{Name: "interfaces"},
+				{Name: "interface", Key: map[string]string{"name": "*"}},
+				{Name: "..."},
+			}},
+		},
+		{
+			name: "trailing slash",
+			path: "/system/",
+			want: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "system"}}},
+		},
+		{name: "empty", path: "", wantErr: "cannot be empty"},
+		{name: "empty element", path: "/a//b", wantErr: "empty element name"},
+		{name: "unterminated key", path: "/a[name=x", wantErr: "unterminated key"},
+		{name: "key without value", path: "/a[name]", wantErr: "has no value"},
+		{name: "empty key name", path: "/a[=x]", wantErr: "empty key name"},
+		{name: "duplicate key", path: "/a[k=1][k=2]", wantErr: "duplicate key"},
+		{name: "text after key", path: "/a[k=1]b", wantErr: "unexpected character"},
+		{name: "trailing escape", path: `/a\`, wantErr: "trailing escape"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePath(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParsePath(%q) error = %v, want containing %q", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePath(%q) unexpected error: %v", tt.path, err)
+			}
+			if !proto.Equal(got.Proto(), tt.want) {
+				t.Errorf("ParsePath(%q) = %v, want %v", tt.path, got.Proto(), tt.want)
+			}
+
+			// Canonical rendering must parse back to the same path
+			again, err := ParsePath(got.String())
+			if err != nil {
+				t.Fatalf("ParsePath(%q) round trip error: %v", got.String(), err)
+			}
+			if !proto.Equal(again.Proto(), tt.want) {
+				t.Errorf("round trip of %q = %v, want %v", got.String(), again.Proto(), tt.want)
+			}
+		})
+	}
+}
+
+// TestPathString tests canonical rendering of paths
+func TestPathString(t *testing.T) {
+	tests := []struct {
+		name string
+		path Path
+		want string
+	}{
+		{
+			name: "root",
+			path: NewPath(),
+			want: "/",
+		},
+		{
+			name: "sorted keys",
+			path: NewPath().Elem("protocol", "name", "65000", "identifier", "BGP"),
+			want: "/protocol[identifier=BGP][name=65000]",
+		},
+		{
+			name: "origin",
+			path: NewPath().Origin("openconfig").Elem("interfaces"),
+			want: "openconfig:/interfaces",
+		},
+		{
+			name: "escaped key value",
+			path: NewPath().Elem("entry", "match=exp", `a]b\c`),
+			want: `/entry[match\=exp=a\]b\\c]`,
+		},
+		{
+			name: "unescaped slash in key value",
+			path: NewPath().Elem("interface", "name", "Gi0/0/0/0"),
+			want: "/interface[name=Gi0/0/0/0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.path.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPathBuilder tests the Path builder methods and error tracking
+func TestPathBuilder(t *testing.T) {
+	base := NewPath().Elem("interfaces")
+	a := base.Elem("interface", "name", "eth0")
+	b := base.Elem("interface", "name", "eth1")
+
+	if a.String() != "/interfaces/interface[name=eth0]" || b.String() != "/interfaces/interface[name=eth1]" {
+		t.Errorf("derived paths share state: %s, %s", a, b)
+	}
+	if base.String() != "/interfaces" {
+		t.Errorf("base path modified: %s", base)
+	}
+
+	gp := a.Proto()
+	gp.Elem[1].Key["name"] = "changed"
+	if a.String() != "/interfaces/interface[name=eth0]" {
+		t.Errorf("Proto() returned shared message")
+	}
+
+	errTests := []struct {
+		name    string
+		path    Path
+		wantErr string
+	}{
+		{name: "odd key arguments", path: NewPath().Elem("interface", "name"), wantErr: "name/value pairs"},
+		{name: "empty name", path: NewPath().Elem(""), wantErr: "cannot be empty"},
+		{name: "empty key", path: NewPath().Elem("interface", "", "eth0"), wantErr: "key name cannot be empty"},
+		{name: "duplicate key", path: NewPath().Elem("interface", "name", "a", "name", "b"), wantErr: "duplicate key"},
+		{name: "invalid origin", path: NewPath().Origin("a:b"), wantErr: "origin cannot contain"},
+		{name: "error preserved", path: NewPath().Elem("").Elem("system"), wantErr: "cannot be empty"},
+	}
+
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.path.Err(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Err() = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestPathHelpers tests that Path-based helpers carry the canonical path and builder errors
+func TestPathHelpers(t *testing.T) {
+	path := NewPath().Elem("interfaces").Elem("interface", "name", "Gi0/0/0/0")
+	invalid := NewPath().Elem("interface", "name")
+
+	if op := UpdatePath(path, `{"mtu": 9000}`); op.Path != path.String() || op.OperationType != OperationUpdate {
+		t.Errorf("UpdatePath() = %+v", op)
+	}
+	if op := DeletePath(path); op.Path != path.String() || op.OperationType != OperationDelete {
+		t.Errorf("DeletePath() = %+v", op)
+	}
+	if sub := OnChangePath(path); sub.Path != path.String() || sub.Mode != SubscriptionModeOnChange {
+		t.Errorf("OnChangePath() = %+v", sub)
+	}
+
+	if err := validateSetOperations([]SetOperation{ReplacePath(invalid, `{}`)}); err == nil {
+		t.Errorf("validateSetOperations() accepted invalid Path")
+	}
+	if err := validateSubscriptions([]Subscription{SamplePath(invalid, 0)}); err == nil {
+		t.Errorf("validateSubscriptions() accepted invalid Path")
+	}
+	if err := validateSubscriptions([]Subscription{TargetDefinedPath(path)}); err != nil {
+		t.Errorf("validateSubscriptions() error = %v", err)
+	}
+}
+
+// TestBuildPrefix tests placement of the request origin and target
+func TestBuildPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        Req
+		path       string
+		wantPrefix *gnmipb.Path
+		wantPath   *gnmipb.Path
+	}{
+		{
+			name:     "no prefix",
+			req:      Req{},
+			path:     "/system",
+			wantPath: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "system"}}},
+		},
+		{
+			name:     "origin on path without prefix",
+			req:      Req{Origin: "openconfig"},
+			path:     "/system",
+			wantPath: &gnmipb.Path{Origin: "openconfig", Elem: []*gnmipb.PathElem{{Name: "system"}}},
+		},
+		{
+			name:     "path origin wins",
+			req:      Req{Origin: "openconfig"},
+			path:     "cli:/",
+			wantPath: &gnmipb.Path{Origin: "cli"},
+		},
+		{
+			name:       "origin and target on prefix",
+			req:        Req{Prefix: "/system", Origin: "openconfig", Target: "router1"},
+			path:       "/config",
+			wantPrefix: &gnmipb.Path{Origin: "openconfig", Target: "router1", Elem: []*gnmipb.PathElem{{Name: "system"}}},
+			wantPath:   &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "config"}}},
+		},
+		{
+			name:       "target only",
+			req:        Req{Target: "router1"},
+			path:       "/system",
+			wantPrefix: &gnmipb.Path{Target: "router1"},
+			wantPath:   &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "system"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, err := buildPrefix(&tt.req)
+			if err != nil {
+				t.Fatalf("buildPrefix() error = %v", err)
+			}
+			if !proto.Equal(prefix, tt.wantPrefix) {
+				t.Errorf("buildPrefix() = %v, want %v", prefix, tt.wantPrefix)
+			}
+
+			gp, err := requestPath(tt.path, &tt.req, prefix)
+			if err != nil {
+				t.Fatalf("requestPath() error = %v", err)
+			}
+			if !proto.Equal(gp, tt.wantPath) {
+				t.Errorf("requestPath() = %v, want %v", gp, tt.wantPath)
+			}
+		})
+	}
+}
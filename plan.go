@@ -0,0 +1,436 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+)
+
+// Plan is a preview of the configuration changes of Set operations
+//
+// Changes are reported per leaf, sorted by path. Paths are rendered without
+// origin and with module prefixes stripped.
+type Plan struct {
+	// Added contains leafs that do not exist yet (Before is nil)
+	Added []Change
+
+	// Removed contains leafs that will be deleted (After is nil)
+	Removed []Change
+
+	// Modified contains leafs whose value changes
+	Modified []Change
+}
+
+// Change is a change of a single leaf
+type Change struct {
+	// Path is the full path of the leaf
+	Path string
+
+	// Before is the current value (nil for added leafs)
+	Before any
+
+	// After is the value after the Set (nil for removed leafs)
+	After any
+}
+
+// HasChanges reports whether the plan contains any change
+func (p Plan) HasChanges() bool {
+	return len(p.Added)+len(p.Removed)+len(p.Modified) > 0
+}
+
+// String renders the plan as a human-readable diff
+//
+// Each change is rendered on its own line: "+ path: value" for added leafs,
+// "- path: value" for removed leafs, and "~ path: before -> after" for
+// modified leafs. Values are rendered as JSON.
+func (p Plan) String() string {
+	var b strings.Builder
+	for _, c := range p.Added {
+		fmt.Fprintf(&b, "+ %s: %s\n", c.Path, planValue(c.After))
+	}
+	for _, c := range p.Removed {
+		fmt.Fprintf(&b, "- %s: %s\n", c.Path, planValue(c.Before))
+	}
+	for _, c := range p.Modified {
+		fmt.Fprintf(&b, "~ %s: %s -> %s\n", c.Path, planValue(c.Before), planValue(c.After))
+	}
+	return b.String()
+}
+
+// Plan previews the changes Set would apply for ops without modifying the target
+//
+// Plan performs a CONFIG Get of every path affected by ops, applies the
+// operations locally with gNMI semantics (deletes, then replaces and union
+// replaces, then updates), and compares the configuration before and after
+// leaf by leaf. Paths that do not exist on the target are treated as empty.
+// The request modifiers (e.g. Prefix, Origin, Timeout) are applied to the Get
+// requests and the paths of ops like for Set.
+//
+// List entries are matched by the keys of the paths of ops and the Get
+// responses, or by a "name" member. Lists whose keys are unknown, and
+// leaf-lists, are compared as a whole. Only JSON values can be previewed; CLI
+// configuration is rejected. All operations must have the same origin, since
+// paths are compared without origin; use one Plan per origin otherwise.
+//
+// Example:
+//
+//	plan, err := client.Plan(ctx, ops)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Print(plan)
+//	if plan.HasChanges() && approve() {
+//	    _, err = client.Set(ctx, ops)
+//	}
+//
+// Returns the Plan or an error if validation or a Get fails.
+func (c *Client) Plan(ctx context.Context, ops []SetOperation, mods ...func(*Req)) (Plan, error) {
+	start := time.Now()
+
+	if err := validateSetOperations(ops); err != nil {
+		return Plan{}, newGnmiError("Plan", ErrValidation, err, start)
+	}
+	req := &Req{}
+	for _, mod := range mods {
+		mod(req)
+	}
+	if err := validateRequestPrefix(req); err != nil {
+		return Plan{}, newGnmiError("Plan", ErrValidation, err, start)
+	}
+	prefix, err := buildPrefix(req)
+	if err != nil {
+		return Plan{}, newGnmiError("Plan", ErrValidation, err, start)
+	}
+
+	// Parse operation paths and values
+	listKeys := make(map[string][]string)
+	paths := make([][]*gnmipb.PathElem, len(ops))
+	values := make([]any, len(ops))
+	var origin string
+	for i, op := range ops {
+		if op.Encoding != EncodingJSON && op.Encoding != EncodingJSONIETF && op.Encoding != "" {
+			err := fmt.Errorf("operation at index %d: plan supports json and json_ietf values only, got: %s", i, op.Encoding)
+			return Plan{}, newGnmiError("Plan", ErrValidation, err, start)
+		}
+		gp, err := requestPath(op.Path, req, prefix)
+		if err != nil {
+			return Plan{}, newGnmiError("Plan", ErrValidation, fmt.Errorf("operation at index %d: %w", i, err), start)
+		}
+		paths[i] = joinPathElems(prefix, gp)
+		recordListKeys(listKeys, paths[i])
+
+		// Trees are merged by element path, so all operations must share an origin
+		opOrigin := gp.GetOrigin()
+		if opOrigin == "" {
+			opOrigin = prefix.GetOrigin()
+		}
+		if opOrigin == OriginCLI {
+			err := fmt.Errorf("operation at index %d: plan does not support the %q origin", i, OriginCLI)
+			return Plan{}, newGnmiError("Plan", ErrValidation, err, start)
+		}
+		if i > 0 && opOrigin != origin {
+			err := fmt.Errorf("operation at index %d: plan requires a single origin, got %q and %q", i, origin, opOrigin)
+			return Plan{}, newGnmiError("Plan", ErrValidation, err, start)
+		}
+		origin = opOrigin
+
+		if op.OperationType != OperationDelete {
+			if values[i], err = decodePlanValue(op.Value); err != nil {
+				return Plan{}, newGnmiError("Plan", ErrValidation, fmt.Errorf("operation at index %d: %w", i, err), start)
+			}
+		}
+	}
+
+	// Fetch the current configuration of every affected path
+	before := map[string]any{}
+	getMods := append(mods[:len(mods):len(mods)], GetDataType(DataTypeConfig))
+	fetched := make(map[string]bool)
+	for _, op := range ops {
+		if fetched[op.Path] {
+			continue
+		}
+		fetched[op.Path] = true
+
+		res, err := c.Get(ctx, []string{op.Path}, getMods...)
+		if OperationCode(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return Plan{}, err
+		}
+		root, updatePaths, err := res.mergeUpdates()
+		if err != nil {
+			return Plan{}, newGnmiError("Plan", nil, fmt.Errorf("path %s: %w", op.Path, err), start)
+		}
+		for _, p := range updatePaths {
+			recordListKeys(listKeys, p)
+		}
+		mergeTreeObjects(before, root)
+	}
+
+	// Apply the operations in gNMI order
+	after, _ := cloneTree(before).(map[string]any)
+	order := []SetOperationType{OperationDelete, OperationReplace, OperationUnionReplace, OperationUpdate}
+	for _, opType := range order {
+		for i, op := range ops {
+			if op.OperationType != opType {
+				continue
+			}
+			if opType != OperationUpdate {
+				deleteTreeAt(after, paths[i])
+			}
+			if opType == OperationDelete {
+				continue
+			}
+			if err := mergeTreeAt(after, paths[i], values[i]); err != nil {
+				return Plan{}, newGnmiError("Plan", ErrValidation, fmt.Errorf("operation at index %d: %w", i, err), start)
+			}
+		}
+	}
+
+	return diffTrees(before, after, listKeys), nil
+}
+
+// decodePlanValue decodes a JSON operation value, preserving numbers as json.Number
+func decodePlanValue(value string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid JSON value: %w", err)
+	}
+	return v, nil
+}
+
+// recordListKeys records the key names of the keyed elements of a path by list name
+func recordListKeys(listKeys map[string][]string, elems []*gnmipb.PathElem) {
+	for _, elem := range elems {
+		if len(elem.GetKey()) == 0 {
+			continue
+		}
+		name := localName(elem.GetName())
+		if _, ok := listKeys[name]; ok {
+			continue
+		}
+		keys := make([]string, 0, len(elem.GetKey()))
+		for k := range elem.GetKey() {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		listKeys[name] = keys
+	}
+}
+
+// cloneTree returns a deep copy of a JSON tree
+func cloneTree(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for k, child := range v {
+			obj[k] = cloneTree(child)
+		}
+		return obj
+	case []any:
+		list := make([]any, len(v))
+		for i, child := range v {
+			list[i] = cloneTree(child)
+		}
+		return list
+	default:
+		return v
+	}
+}
+
+// deleteTreeAt removes the node at the given path
+//
+// Missing nodes are ignored. An empty path clears the tree.
+func deleteTreeAt(root map[string]any, elems []*gnmipb.PathElem) {
+	if len(elems) == 0 {
+		for k := range root {
+			delete(root, k)
+		}
+		return
+	}
+
+	parent, ok := subtree(root, elems[:len(elems)-1])
+	if !ok {
+		return
+	}
+	obj, ok := parent.(map[string]any)
+	if !ok {
+		return
+	}
+	last := elems[len(elems)-1]
+	name, ok := lookupTreeMember(obj, last.GetName())
+	if !ok {
+		return
+	}
+	if len(last.GetKey()) == 0 {
+		delete(obj, name)
+		return
+	}
+
+	list, _ := obj[name].([]any)
+	kept := make([]any, 0, len(list))
+	for _, item := range list {
+		if findTreeEntry([]any{item}, last.GetKey()) == nil {
+			kept = append(kept, item)
+		}
+	}
+	obj[name] = kept
+}
+
+// diffTrees compares two JSON trees leaf by leaf
+func diffTrees(before, after map[string]any, listKeys map[string][]string) Plan {
+	old := make(map[string]any)
+	flattenTree(before, nil, listKeys, old)
+	updated := make(map[string]any)
+	flattenTree(after, nil, listKeys, updated)
+
+	var plan Plan
+	for path, v := range updated {
+		prev, ok := old[path]
+		switch {
+		case !ok:
+			plan.Added = append(plan.Added, Change{Path: path, After: v})
+		case !equalLeaf(prev, v):
+			plan.Modified = append(plan.Modified, Change{Path: path, Before: prev, After: v})
+		}
+	}
+	for path, v := range old {
+		if _, ok := updated[path]; !ok {
+			plan.Removed = append(plan.Removed, Change{Path: path, Before: v})
+		}
+	}
+
+	for _, changes := range [][]Change{plan.Added, plan.Removed, plan.Modified} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	}
+	return plan
+}
+
+// flattenTree collects the leafs of a JSON tree keyed by path
+//
+// List entries become keyed path elements if the list keys are known (see
+// listEntryKeys); other lists are collected as leafs. Empty containers and
+// lists have no leafs.
+func flattenTree(node any, elems []*gnmipb.PathElem, listKeys map[string][]string, out map[string]any) {
+	switch v := node.(type) {
+	case map[string]any:
+		for name, child := range v {
+			flattenTree(child, appendElem(elems, &gnmipb.PathElem{Name: localName(name)}), listKeys, out)
+		}
+	case []any:
+		if len(v) == 0 || len(elems) == 0 {
+			return
+		}
+		last := elems[len(elems)-1]
+		keys := listEntryKeys(v, listKeys[last.GetName()])
+		if keys == nil {
+			out[pathToString(nil, &gnmipb.Path{Elem: elems})] = v
+			return
+		}
+		for _, item := range v {
+			entry := item.(map[string]any)
+			keyed := &gnmipb.PathElem{Name: last.GetName(), Key: make(map[string]string, len(keys))}
+			for _, k := range keys {
+				name, _ := lookupTreeMember(entry, k)
+				keyed.Key[k] = fmt.Sprint(entry[name])
+			}
+			flattenTree(entry, appendElem(elems[:len(elems)-1], keyed), listKeys, out)
+		}
+	default:
+		out[pathToString(nil, &gnmipb.Path{Elem: elems})] = v
+	}
+}
+
+// listEntryKeys returns the key names identifying the entries of list
+//
+// The known keys are used if every entry carries them as scalar members;
+// otherwise a "name" member is used if it is present and unique in every
+// entry. Returns nil if the entries cannot be identified (e.g. leaf-lists).
+func listEntryKeys(list []any, known []string) []string {
+	candidates := [][]string{known, {"name"}}
+	for _, keys := range candidates {
+		if len(keys) == 0 {
+			continue
+		}
+		seen := make(map[string]bool, len(list))
+		ok := len(list) > 0
+		for _, item := range list {
+			entry, isObj := item.(map[string]any)
+			if !isObj {
+				ok = false
+				break
+			}
+			var id []string
+			for _, k := range keys {
+				name, found := lookupTreeMember(entry, k)
+				if !found {
+					ok = false
+					break
+				}
+				switch entry[name].(type) {
+				case map[string]any, []any, nil:
+					ok = false
+				}
+				id = append(id, fmt.Sprint(entry[name]))
+			}
+			if !ok || seen[strings.Join(id, "\x00")] {
+				ok = false
+				break
+			}
+			seen[strings.Join(id, "\x00")] = true
+		}
+		if ok {
+			return keys
+		}
+	}
+	return nil
+}
+
+// appendElem returns a new slice with elem appended to elems
+func appendElem(elems []*gnmipb.PathElem, elem *gnmipb.PathElem) []*gnmipb.PathElem {
+	out := make([]*gnmipb.PathElem, len(elems), len(elems)+1)
+	copy(out, elems)
+	return append(out, elem)
+}
+
+// equalLeaf reports whether two leaf values are equal
+//
+// Scalars are compared in their string form, since key leafs created from
+// paths are strings while JSON numbers are json.Number.
+func equalLeaf(a, b any) bool {
+	switch a.(type) {
+	case map[string]any, []any:
+	default:
+		switch b.(type) {
+		case map[string]any, []any:
+			return false
+		}
+		return fmt.Sprint(a) == fmt.Sprint(b)
+	}
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
+
+// planValue renders a value as JSON for Plan.String
+func planValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
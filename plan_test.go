@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestDiffTrees tests the leaf diff of configuration trees
+func TestDiffTrees(t *testing.T) {
+	before := map[string]any{
+		"system": map[string]any{"hostname": "router1", "domain": "lab"},
+		"interfaces": map[string]any{"interface": []any{
+			map[string]any{"name": "eth0", "mtu": "1500"},
+			map[string]any{"name": "eth1", "mtu": "1500"},
+		}},
+		"dns": map[string]any{"servers": []any{"10.0.0.1"}},
+	}
+	after := map[string]any{
+		"system": map[string]any{"hostname": "router2", "openconfig-system:motd": "hi"},
+		"interfaces": map[string]any{"interface": []any{
+			map[string]any{"name": "eth1", "mtu": "9000"},
+		}},
+		"dns": map[string]any{"servers": []any{"10.0.0.1", "10.0.0.2"}},
+	}
+
+	plan := diffTrees(before, after, map[string][]string{})
+	want := "" +
+		"+ /system/motd: \"hi\"\n" +
+		"- /interfaces/interface[name=eth0]/mtu: \"1500\"\n" +
+		"- /interfaces/interface[name=eth0]/name: \"eth0\"\n" +
+		"- /system/domain: \"lab\"\n" +
+		"~ /dns/servers: [\"10.0.0.1\"] -> [\"10.0.0.1\",\"10.0.0.2\"]\n" +
+		"~ /interfaces/interface[name=eth1]/mtu: \"1500\" -> \"9000\"\n" +
+		"~ /system/hostname: \"router1\" -> \"router2\"\n"
+	if got := plan.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+	if !plan.HasChanges() {
+		t.Errorf("HasChanges() = false, want true")
+	}
+	if diffTrees(before, before, nil).HasChanges() {
+		t.Errorf("HasChanges() of identical trees = true, want false")
+	}
+}
+
+// TestListEntryKeys tests identification of list entries
+func TestListEntryKeys(t *testing.T) {
+	entries := []any{
+		map[string]any{"id": "1", "name": "a"},
+		map[string]any{"id": "2", "name": "a"},
+	}
+	tests := []struct {
+		name  string
+		list  []any
+		known []string
+		want  []string
+	}{
+		{"known keys", entries, []string{"id"}, []string{"id"}},
+		{"duplicate name", entries, nil, nil},
+		{"name fallback", []any{map[string]any{"name": "a"}, map[string]any{"name": "b"}}, []string{"id"}, []string{"name"}},
+		{"leaf-list", []any{"a", "b"}, nil, nil},
+		{"composite key", []any{map[string]any{"name": map[string]any{}}}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := listEntryKeys(tt.list, tt.known)
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("listEntryKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIntegration_Plan tests Plan against the fake server
+func TestIntegration_Plan(t *testing.T) {
+	client, srv := newIntegrationClient(t)
+	ctx := context.Background()
+	if err := srv.Load("/interfaces/interface[name=eth0]/config", `{"name": "eth0", "mtu": 1500}`); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	ops := []SetOperation{
+		Update("/system/config/hostname", `"router2"`),
+		Update("/system/config/domain-name", `"example.com"`),
+		Replace("/interfaces/interface[name=eth0]/config", `{"name": "eth0", "mtu": 9000}`),
+		Delete("/ntp"),
+	}
+	plan, err := client.Plan(ctx, ops)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := "" +
+		"+ /system/config/domain-name: \"example.com\"\n" +
+		"~ /interfaces/interface[name=eth0]/config/mtu: 1500 -> 9000\n" +
+		"~ /system/config/hostname: \"router1\" -> \"router2\"\n"
+	if got := plan.String(); got != want {
+		t.Errorf("Plan() =\n%s\nwant\n%s", got, want)
+	}
+
+	if len(srv.SetRequests()) != 0 {
+		t.Errorf("Plan() sent %d Set requests, want none", len(srv.SetRequests()))
+	}
+	for _, req := range srv.GetRequests() {
+		if req.GetType().String() != "CONFIG" {
+			t.Errorf("Get data type = %s, want CONFIG", req.GetType())
+		}
+	}
+
+	t.Run("delete", func(t *testing.T) {
+		plan, err := client.Plan(ctx, []SetOperation{Delete("/interfaces/interface[name=eth0]")})
+		if err != nil {
+			t.Fatalf("Plan() error = %v", err)
+		}
+		if len(plan.Removed) != 3 || len(plan.Added)+len(plan.Modified) != 0 {
+			t.Errorf("Plan() = %+v, want 3 removed leafs", plan)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		plan, err := client.Plan(ctx, []SetOperation{Update("/system/config", `{"hostname": "router1"}`)})
+		if err != nil || plan.HasChanges() {
+			t.Errorf("Plan() = %+v, %v, want no changes", plan, err)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		if _, err := client.Plan(ctx, []SetOperation{UnionReplaceCLI("hostname r1")}); !errors.Is(err, ErrValidation) {
+			t.Errorf("Plan() CLI error = %v, want ErrValidation", err)
+		}
+		mixed := []SetOperation{Update("openconfig:/system/config/hostname", `"r2"`), Update("/system/config/hostname", `"r3"`)}
+		if _, err := client.Plan(ctx, mixed); !errors.Is(err, ErrValidation) {
+			t.Errorf("Plan() mixed origins error = %v, want ErrValidation", err)
+		}
+		if _, err := client.Plan(ctx, []SetOperation{Replace("/", `"text"`)}); !errors.Is(err, ErrValidation) {
+			t.Errorf("Plan() scalar root error = %v, want ErrValidation", err)
+		}
+	})
+}
@@ -0,0 +1,396 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default connection pool configuration values
+const (
+	DefaultPoolIdleTimeout         = 5 * time.Minute
+	DefaultPoolHealthCheckInterval = 30 * time.Second
+)
+
+// Pool manages the connections of a set of Clients
+//
+// Clients join a pool via the ConnectionPool option. The pool:
+//   - disconnects clients that have been idle for IdleTimeout
+//   - health-checks idle connections every HealthCheckInterval using the
+//     gRPC connectivity state and a Capabilities RPC, disconnecting
+//     unhealthy ones
+//   - caps the number of open connections across all its clients at
+//     MaxConnections, disconnecting the least recently used idle client
+//     or waiting for a free slot when the limit is reached
+//
+// Disconnected clients reconnect automatically on their next operation.
+// Clients with an operation in progress or an open subscription are never
+// disconnected by the pool.
+//
+// Example:
+//
+//	pool := gnmi.NewPool(
+//	    gnmi.IdleTimeout(2*time.Minute),
+//	    gnmi.MaxConnections(100),
+//	)
+//	defer pool.Close()
+//
+//	mgr := gnmi.NewManager(gnmi.ClientOptions(
+//	    gnmi.Username("admin"),
+//	    gnmi.Password("secret"),
+//	    gnmi.ConnectionPool(pool),
+//	))
+//
+// Thread-safe: safe for concurrent use by its clients.
+type Pool struct {
+	// IdleTimeout is the time after its last operation a client is disconnected (0 disables)
+	IdleTimeout time.Duration
+
+	// HealthCheckInterval is the interval between health checks of idle connections (0 disables)
+	HealthCheckInterval time.Duration
+
+	// MaxConnections is the maximum number of open connections (0 means unlimited)
+	MaxConnections int
+
+	mu      sync.Mutex
+	clients map[*Client]struct{}
+	open    map[*Client]struct{}
+
+	// released is closed and replaced whenever a connection slot is freed
+	// or a client holding a slot becomes idle
+	released chan struct{}
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// IdleTimeout sets the idle time after which pooled clients are disconnected (default: 5m)
+//
+// A value of 0 disables idle eviction.
+func IdleTimeout(d time.Duration) func(*Pool) {
+	return func(p *Pool) {
+		p.IdleTimeout = d
+	}
+}
+
+// HealthCheckInterval sets the interval between health checks of idle connections (default: 30s)
+//
+// A value of 0 disables health checks.
+func HealthCheckInterval(d time.Duration) func(*Pool) {
+	return func(p *Pool) {
+		p.HealthCheckInterval = d
+	}
+}
+
+// MaxConnections sets the maximum number of open connections across all pooled clients
+//
+// A value of 0 (default) means unlimited.
+func MaxConnections(n int) func(*Pool) {
+	return func(p *Pool) {
+		p.MaxConnections = n
+	}
+}
+
+// NewPool creates a connection pool and starts its background maintenance
+//
+// Call Close to stop background eviction and health checks.
+//
+// Example:
+//
+//	pool := gnmi.NewPool(gnmi.IdleTimeout(time.Minute), gnmi.MaxConnections(50))
+//	defer pool.Close()
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.Username("admin"),
+//	    gnmi.Password("secret"),
+//	    gnmi.ConnectionPool(pool),
+//	)
+func NewPool(opts ...func(*Pool)) *Pool {
+	p := &Pool{
+		IdleTimeout:         DefaultPoolIdleTimeout,
+		HealthCheckInterval: DefaultPoolHealthCheckInterval,
+		clients:             make(map[*Client]struct{}),
+		open:                make(map[*Client]struct{}),
+		released:            make(chan struct{}),
+		stop:                make(chan struct{}),
+		done:                make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	go p.maintain()
+
+	return p
+}
+
+// OpenConnections returns the number of open (or opening) connections
+func (p *Pool) OpenConnections() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.open)
+}
+
+// Close stops background eviction and health checks
+//
+// Pooled clients are not closed and the connection limit remains in effect.
+//
+// Thread-safe: safe to call multiple times (subsequent calls are no-ops).
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
+
+// add registers a client with the pool
+func (p *Pool) add(c *Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clients[c] = struct{}{}
+}
+
+// remove unregisters a client and frees its connection slot
+func (p *Pool) remove(c *Client) {
+	p.mu.Lock()
+	delete(p.clients, c)
+	p.mu.Unlock()
+
+	p.release(c)
+}
+
+// acquire reserves a connection slot for c
+//
+// If the pool is full, the least recently used idle client is disconnected.
+// If no client is idle, acquire waits until a slot is freed or a client
+// becomes idle.
+//
+// Returns nil if c already holds a slot, or an error if ctx is done first.
+func (p *Pool) acquire(ctx context.Context, c *Client) error {
+	for {
+		p.mu.Lock()
+		if _, ok := p.open[c]; ok || p.MaxConnections <= 0 || len(p.open) < p.MaxConnections {
+			p.open[c] = struct{}{}
+			p.mu.Unlock()
+			return nil
+		}
+		candidates := p.leastRecentlyUsed(c)
+		released := p.released
+		p.mu.Unlock()
+
+		evicted := false
+		for _, candidate := range candidates {
+			if candidate.evictIfIdle(0, "connection limit reached") {
+				evicted = true
+				break
+			}
+		}
+		if evicted {
+			continue
+		}
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for connection slot: %w", ctx.Err())
+		}
+	}
+}
+
+// release frees the connection slot of c and wakes waiting clients
+func (p *Pool) release(c *Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.open[c]; !ok {
+		return
+	}
+	delete(p.open, c)
+	p.wakeLocked()
+}
+
+// idle wakes clients waiting for a slot after c finished its last operation,
+// so they can disconnect it
+func (p *Pool) idle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.wakeLocked()
+}
+
+// wakeLocked wakes all clients waiting in acquire
+//
+// PRECONDITION: Caller must hold p.mu.
+func (p *Pool) wakeLocked() {
+	close(p.released)
+	p.released = make(chan struct{})
+}
+
+// leastRecentlyUsed returns the clients holding a slot, except exclude,
+// ordered by last use (oldest first)
+//
+// PRECONDITION: Caller must hold p.mu.
+func (p *Pool) leastRecentlyUsed(exclude *Client) []*Client {
+	clients := make([]*Client, 0, len(p.open))
+	for c := range p.open {
+		if c != exclude {
+			clients = append(clients, c)
+		}
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].lastUsed.Load() < clients[j].lastUsed.Load()
+	})
+	return clients
+}
+
+// maintain runs idle eviction and health checks until Close is called
+func (p *Pool) maintain() {
+	defer close(p.done)
+
+	interval := p.HealthCheckInterval
+	if p.IdleTimeout > 0 && (interval <= 0 || p.IdleTimeout/2 < interval) {
+		interval = p.IdleTimeout / 2
+	}
+	if interval <= 0 {
+		// Nothing to do periodically
+		<-p.stop
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastHealthCheck := time.Now()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		p.mu.Lock()
+		clients := make([]*Client, 0, len(p.clients))
+		for c := range p.clients {
+			clients = append(clients, c)
+		}
+		p.mu.Unlock()
+
+		if p.IdleTimeout > 0 {
+			for _, c := range clients {
+				c.evictIfIdle(p.IdleTimeout, "idle timeout")
+			}
+		}
+
+		if p.HealthCheckInterval > 0 && time.Since(lastHealthCheck) >= p.HealthCheckInterval {
+			lastHealthCheck = time.Now()
+			for _, c := range clients {
+				c.checkHealth(context.Background())
+			}
+		}
+	}
+}
+
+// beginOperation marks the start of an operation for pool bookkeeping
+//
+// Returns a function that marks the end of the operation.
+func (c *Client) beginOperation() func() {
+	c.lastUsed.Store(time.Now().UnixNano())
+	c.inflight.Add(1)
+	return func() {
+		c.lastUsed.Store(time.Now().UnixNano())
+		if c.inflight.Add(-1) == 0 && c.pool != nil {
+			c.pool.idle()
+		}
+	}
+}
+
+// evictIfIdle disconnects the client if it has no operation in progress and
+// was last used at least idle ago
+//
+// Busy clients (lock held) are skipped rather than waited for.
+//
+// Returns true if the connection was closed.
+func (c *Client) evictIfIdle(idle time.Duration, reason string) bool {
+	if !c.mu.TryLock() {
+		return false
+	}
+	defer c.mu.Unlock()
+
+	if !c.connected || c.target == nil || c.inflight.Load() > 0 {
+		return false
+	}
+	if time.Since(time.Unix(0, c.lastUsed.Load())) < idle {
+		return false
+	}
+
+	if err := c.target.Close(); err != nil {
+		c.logger.Warn(context.Background(), "gNMI connection close returned error during eviction",
+			"target", c.Target,
+			"error", err.Error())
+	}
+	c.connected = false
+	if c.pool != nil {
+		c.pool.release(c)
+	}
+
+	c.logger.Info(context.Background(), "gNMI pooled connection closed",
+		"target", c.Target,
+		"reason", reason)
+
+	return true
+}
+
+// checkHealth verifies an idle connection and disconnects it if unhealthy
+//
+// The connection is unhealthy if the gRPC connectivity state is
+// TRANSIENT_FAILURE or SHUTDOWN, or a Capabilities RPC fails within
+// OperationTimeout. Health checks do not count as use for idle eviction.
+func (c *Client) checkHealth(ctx context.Context) {
+	c.mu.RLock()
+	if !c.connected || c.target == nil || c.inflight.Load() > 0 {
+		c.mu.RUnlock()
+		return
+	}
+	target := c.target
+	used := c.lastUsed.Load()
+	c.mu.RUnlock()
+
+	// Run the check without holding the lock, so a hung target does not
+	// block Close, reconnects, or operations for OperationTimeout
+	var err error
+	switch state := target.ConnState(); state {
+	case "TRANSIENT_FAILURE", "SHUTDOWN":
+		err = fmt.Errorf("connection state %s", state)
+	default:
+		checkCtx, cancel := context.WithTimeout(ctx, c.OperationTimeout)
+		_, err = target.Capabilities(checkCtx)
+		cancel()
+	}
+
+	if err == nil {
+		return
+	}
+
+	// The connection was closed, replaced, or used during the check; an
+	// operation that succeeded since proves the connection healthy
+	c.mu.RLock()
+	changed := c.target != target || c.lastUsed.Load() != used
+	c.mu.RUnlock()
+	if changed {
+		return
+	}
+
+	c.logger.Warn(ctx, "gNMI health check failed",
+		"target", c.Target,
+		"error", err.Error())
+	c.evictIfIdle(0, "health check failed")
+}
@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+	"google.golang.org/grpc/codes"
+)
+
+// newPoolTestServer starts a gnmitest server with a hostname loaded
+func newPoolTestServer(t *testing.T) *gnmitest.Server {
+	t.Helper()
+	srv, err := gnmitest.NewServer()
+	if err != nil {
+		t.Fatalf("gnmitest.NewServer() error = %v", err)
+	}
+	t.Cleanup(srv.Close)
+	if err := srv.Load("/system/config", `{"hostname": "r1"}`); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return srv
+}
+
+// newPooledClient creates a client for srv in pool
+func newPooledClient(t *testing.T, pool *Pool, srv *gnmitest.Server) *Client {
+	t.Helper()
+	c, err := NewClient(srv.Addr(), TLS(false), MaxRetries(0), ConnectionPool(pool))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+// isConnected reports whether the client has an open connection
+func isConnected(c *Client) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connected
+}
+
+// waitFor polls cond until it holds or the timeout expires
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return cond()
+}
+
+// TestPool_Options tests pool defaults and options
+func TestPool_Options(t *testing.T) {
+	p := NewPool()
+	defer p.Close()
+	if p.IdleTimeout != DefaultPoolIdleTimeout || p.HealthCheckInterval != DefaultPoolHealthCheckInterval || p.MaxConnections != 0 {
+		t.Errorf("NewPool() = %+v, want defaults", p)
+	}
+
+	p = NewPool(IdleTimeout(0), HealthCheckInterval(0), MaxConnections(3))
+	if p.IdleTimeout != 0 || p.HealthCheckInterval != 0 || p.MaxConnections != 3 {
+		t.Errorf("NewPool() = %+v, want options applied", p)
+	}
+	p.Close()
+	p.Close() // second call is a no-op
+}
+
+// TestIntegration_PoolIdleTimeout tests that idle clients are disconnected and reconnect on use
+func TestIntegration_PoolIdleTimeout(t *testing.T) {
+	srv := newPoolTestServer(t)
+	pool := NewPool(IdleTimeout(50*time.Millisecond), HealthCheckInterval(0))
+	defer pool.Close()
+	c := newPooledClient(t, pool, srv)
+
+	ctx := context.Background()
+	if _, err := c.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if pool.OpenConnections() != 1 {
+		t.Errorf("OpenConnections() = %d, want 1", pool.OpenConnections())
+	}
+
+	if !waitFor(t, 2*time.Second, func() bool { return !isConnected(c) }) {
+		t.Fatalf("client not disconnected after idle timeout")
+	}
+	if pool.OpenConnections() != 0 {
+		t.Errorf("OpenConnections() = %d, want 0 after eviction", pool.OpenConnections())
+	}
+
+	if _, err := c.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+		t.Fatalf("Get() after eviction error = %v", err)
+	}
+	if !isConnected(c) {
+		t.Errorf("client not reconnected after eviction")
+	}
+}
+
+// TestIntegration_PoolMaxConnections tests the connection cap across targets
+func TestIntegration_PoolMaxConnections(t *testing.T) {
+	pool := NewPool(IdleTimeout(0), HealthCheckInterval(0), MaxConnections(1))
+	defer pool.Close()
+	c1 := newPooledClient(t, pool, newPoolTestServer(t))
+	c2 := newPooledClient(t, pool, newPoolTestServer(t))
+
+	ctx := context.Background()
+	paths := []string{"/system/config/hostname"}
+
+	t.Run("least recently used is evicted", func(t *testing.T) {
+		if _, err := c1.Get(ctx, paths); err != nil {
+			t.Fatalf("Get() c1 error = %v", err)
+		}
+		if _, err := c2.Get(ctx, paths); err != nil {
+			t.Fatalf("Get() c2 error = %v", err)
+		}
+		if isConnected(c1) || !isConnected(c2) {
+			t.Errorf("connected = (%v, %v), want (false, true)", isConnected(c1), isConnected(c2))
+		}
+		if pool.OpenConnections() != 1 {
+			t.Errorf("OpenConnections() = %d, want 1", pool.OpenConnections())
+		}
+	})
+
+	t.Run("busy client keeps its slot", func(t *testing.T) {
+		stream, err := c2.Subscribe(ctx, []Subscription{OnChange("/system/config")})
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+
+		getCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_, err = c1.Get(getCtx, paths)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Get() error = %v, want deadline exceeded while slot is in use", err)
+		}
+
+		// Closing the stream makes c2 idle, so the waiting c1 takes over the slot
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := c1.Get(ctx, paths)
+			errCh <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+		_ = stream.Close()
+		select {
+		case err := <-errCh:
+			if err != nil {
+				t.Fatalf("Get() after stream close error = %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Get() still waiting after stream close")
+		}
+		if !isConnected(c1) || isConnected(c2) {
+			t.Errorf("connected = (%v, %v), want (true, false)", isConnected(c1), isConnected(c2))
+		}
+	})
+
+	t.Run("closed client frees its slot", func(t *testing.T) {
+		if err := c1.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if pool.OpenConnections() != 0 {
+			t.Errorf("OpenConnections() = %d, want 0", pool.OpenConnections())
+		}
+	})
+}
+
+// TestIntegration_PoolHealthCheck tests that unhealthy connections are disconnected
+func TestIntegration_PoolHealthCheck(t *testing.T) {
+	srv := newPoolTestServer(t)
+	pool := NewPool(IdleTimeout(0), HealthCheckInterval(20*time.Millisecond))
+	defer pool.Close()
+	c := newPooledClient(t, pool, srv)
+
+	if _, err := c.Get(context.Background(), []string{"/system/config/hostname"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// Healthy connections stay open
+	time.Sleep(100 * time.Millisecond)
+	if !isConnected(c) {
+		t.Fatalf("healthy client disconnected")
+	}
+
+	srv.Close()
+	if !waitFor(t, 5*time.Second, func() bool { return !isConnected(c) }) {
+		t.Errorf("client not disconnected after failed health check")
+	}
+	if pool.OpenConnections() != 0 {
+		t.Errorf("OpenConnections() = %d, want 0", pool.OpenConnections())
+	}
+}
+
+// TestIntegration_PoolHealthCheckConcurrentUse tests that a failed health check does not evict a client used during the check
+func TestIntegration_PoolHealthCheckConcurrentUse(t *testing.T) {
+	srv := newPoolTestServer(t)
+	pool := NewPool(IdleTimeout(0), HealthCheckInterval(0))
+	defer pool.Close()
+	c := newPooledClient(t, pool, srv)
+	ctx := context.Background()
+
+	if _, err := c.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	srv.SetLatency(gnmitest.RPCCapabilities, 200*time.Millisecond)
+	srv.FailNext(gnmitest.RPCCapabilities, codes.Unavailable, 1)
+	done := make(chan struct{})
+	go func() {
+		c.checkHealth(ctx)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if _, err := c.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	<-done
+
+	if !isConnected(c) {
+		t.Errorf("client used during a failed health check was disconnected")
+	}
+}
+
+// TestIntegration_PoolReconnect tests that a reconnect reserves a connection slot
+func TestIntegration_PoolReconnect(t *testing.T) {
+	pool := NewPool(IdleTimeout(0), HealthCheckInterval(0), MaxConnections(1))
+	defer pool.Close()
+	c := newPooledClient(t, pool, newPoolTestServer(t))
+	ctx := context.Background()
+
+	if _, err := c.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// A failed reconnect releases the slot; the next reconnect takes it again
+	pool.release(c)
+	c.mu.Lock()
+	err := c.reconnect(ctx)
+	c.mu.Unlock()
+	if err != nil {
+		t.Fatalf("reconnect() error = %v", err)
+	}
+	if !isConnected(c) || pool.OpenConnections() != 1 {
+		t.Errorf("connected = %v, OpenConnections() = %d, want tracked connection", isConnected(c), pool.OpenConnections())
+	}
+}
+
+// TestIntegration_PoolReconnectWaitUnlocked tests that a reconnect waiting for a slot does not block Close
+func TestIntegration_PoolReconnectWaitUnlocked(t *testing.T) {
+	srv := newPoolTestServer(t)
+	pool := NewPool(IdleTimeout(0), HealthCheckInterval(0), MaxConnections(1))
+	defer pool.Close()
+	a := newPooledClient(t, pool, srv)
+	b := newPooledClient(t, pool, srv)
+	ctx := context.Background()
+
+	if _, err := b.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	pool.release(b)
+	if _, err := a.Get(ctx, []string{"/system/config/hostname"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	// Keep a busy so the reconnect of b waits for its slot
+	end := a.beginOperation()
+
+	done := make(chan error, 1)
+	go func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		done <- b.reconnect(ctx)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// The connection of b is already closed, so only the return matters
+	closed := make(chan struct{})
+	go func() {
+		_ = b.Close() //nolint:errcheck // Closing the closed connection may fail
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatalf("Close() blocked by a reconnect waiting for a connection slot")
+	}
+
+	end()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("reconnect() error = %v, want ErrClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("reconnect() did not return after a slot was freed")
+	}
+	if n := pool.OpenConnections(); n > 1 {
+		t.Errorf("OpenConnections() = %d, want at most 1", n)
+	}
+}
+
+// TestIntegration_PoolHealthCheckUnlocked tests that a slow health check does not block the client
+func TestIntegration_PoolHealthCheckUnlocked(t *testing.T) {
+	srv := newPoolTestServer(t)
+	pool := NewPool(IdleTimeout(0), HealthCheckInterval(20*time.Millisecond))
+	defer pool.Close()
+	c := newPooledClient(t, pool, srv)
+
+	if _, err := c.Get(context.Background(), []string{"/system/config/hostname"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	srv.SetLatency(gnmitest.RPCCapabilities, 2*time.Second)
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close() took %v during a health check, want immediate", elapsed)
+	}
+}
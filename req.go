@@ -3,7 +3,11 @@
 
 package gnmi
 
-import "time"
+import (
+	"time"
+
+	"github.com/openconfig/gnmic/pkg/api"
+)
 
 // Req represents a gNMI request modifier
 //
@@ -21,11 +25,76 @@ type Req struct {
 	// Valid values: json, json_ietf (default), proto, ascii, bytes
 	Encoding string
 
+	// DataType selects the data returned by Get operations
+	// Valid values: all (default), config, state, operational
+	DataType DataType
+
+	// Prefix is the request prefix path prepended by the target to all paths
+	// of Get, Set, and Subscribe operations
+	Prefix string
+
+	// Origin is the request origin (e.g., openconfig, cli, rfc7951)
+	// Set on the prefix if one is sent, otherwise on each path without origin
+	Origin string
+
+	// Target is the gNMI target name set in the prefix
+	// Used to address a device behind a gNMI gateway or proxy
+	Target string
+
 	// Timeout is the request-specific timeout
 	// Overrides client default timeout if set
 	Timeout time.Duration
+
+	// RetryPolicy is the request-specific retry policy
+	// Overrides the client retry policy if set
+	RetryPolicy RetryPolicy
+
+	// Mode specifies the subscription list mode for Subscribe operations
+	// Valid values: stream (default), once, poll
+	Mode SubscriptionListMode
+
+	// UpdatesOnly suppresses the initial state of Subscribe operations
+	// Only changes after the subscription was created are sent
+	UpdatesOnly bool
+
+	// Resubscribe enables automatic resubscription of Subscribe operations
+	// after transient errors (default: true)
+	Resubscribe bool
+
+	// Extensions are the gNMI extensions attached to Get, Set, and Subscribe
+	// requests (see Extension)
+	Extensions Extensions
+
+	// RollbackDuration makes Set a commit-confirmed transaction
+	// The target rolls back the Set unless confirmed within the duration
+	RollbackDuration time.Duration
+
+	// CommitID is the ID of a commit-confirmed Set (default: generated)
+	CommitID string
+
+	// commitAction is the commit extension of Commit.Confirm, Commit.Cancel,
+	// and Commit.SetRollbackDuration, sent without operations
+	commitAction api.GNMIOption
 }
 
+// DataType represents the type of data requested by a gNMI Get operation
+type DataType string
+
+const (
+	// DataTypeAll requests config and state data (default)
+	DataTypeAll DataType = "all"
+
+	// DataTypeConfig requests read-write configuration data only
+	DataTypeConfig DataType = "config"
+
+	// DataTypeState requests read-only state data only
+	DataTypeState DataType = "state"
+
+	// DataTypeOperational requests read-only operational data only
+	// (state data not derived from configuration, e.g. counters)
+	DataTypeOperational DataType = "operational"
+)
+
 // SetOperationType represents the type of Set operation
 type SetOperationType string
 
@@ -38,21 +107,33 @@ const (
 
 	// OperationDelete removes configuration at the specified path
 	OperationDelete SetOperationType = "delete"
+
+	// OperationUnionReplace replaces configuration together with the
+	// configuration of other origins in the same request (gNMI 0.10), e.g.
+	// OpenConfig and CLI configuration in one transaction
+	OperationUnionReplace SetOperationType = "union_replace"
 )
 
-// SetOperation represents a single gNMI Set operation (Update, Replace, or Delete)
+// OriginCLI is the path origin of native CLI configuration sent as ASCII text
+const OriginCLI = "cli"
+
+// SetOperation represents a single gNMI Set operation (Update, Replace, Delete, or UnionReplace)
 type SetOperation struct {
-	// OperationType specifies the operation type (update, replace, delete)
+	// OperationType specifies the operation type (update, replace, delete, union_replace)
 	OperationType SetOperationType
 
 	// Path is the gNMI path
 	Path string
 
-	// Value is the JSON value for Update/Replace operations
-	// Empty for Delete operations
+	// Value is the JSON value for Update/Replace/UnionReplace operations
+	// (CLI text for the cli origin). Empty for Delete operations
 	Value string
 
 	// Encoding specifies the value encoding
 	// Valid values: json, json_ietf (default), proto, ascii, bytes
 	Encoding string
+
+	// pathErr is the builder error of a Path passed to UpdatePath, ReplacePath,
+	// or DeletePath (reported by Set)
+	pathErr error
 }
@@ -90,6 +90,19 @@ func TestGetEncoding(t *testing.T) {
 	}
 }
 
+func TestGetDataType(t *testing.T) {
+	for _, dataType := range []DataType{DataTypeAll, DataTypeConfig, DataTypeState, DataTypeOperational} {
+		t.Run(string(dataType), func(t *testing.T) {
+			req := &Req{}
+			GetDataType(dataType)(req)
+
+			if req.DataType != dataType {
+				t.Errorf("GetDataType() data type = %v, want %v", req.DataType, dataType)
+			}
+		})
+	}
+}
+
 func TestMultipleModifiers(t *testing.T) {
 	// Test that multiple modifiers can be applied
 	req := &Req{}
@@ -23,6 +23,12 @@ type GetRes struct {
 
 	// Errors contains any error information
 	Errors []ErrorModel
+
+	// Extensions are the gNMI extensions returned by the target
+	Extensions Extensions
+
+	// request is the GetRequest that produced the response (used by Tree)
+	request *gnmi.GetRequest
 }
 
 // GetValue retrieves a value from the response notifications using a gjson path.
@@ -35,6 +41,7 @@ type GetRes struct {
 //
 // Note: The JSON structure uses protobuf JSON marshaling conventions where
 // field names are lowercase and TypedValue.Value is nested with capitalized names.
+// Use Values() or DecodedNotifications() to access decoded values instead.
 //
 // Returns gjson.Result which can be converted to specific types:
 //   - result.String() for string values
@@ -58,6 +65,56 @@ func (r GetRes) GetValue(path string) gjson.Result {
 	return gjson.Get(jsonStr, path)
 }
 
+// DecodedNotifications converts the response notifications into Notifications
+// with XPath-style paths and values decoded into plain Go values.
+//
+// See NotificationUpdate.Value for the Go type of each TypedValue variant.
+//
+// Example:
+//
+//	res, err := client.Get(ctx, []string{"/interfaces/interface[name=Gi0/0/0/0]/state"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, notif := range res.DecodedNotifications() {
+//	    for _, u := range notif.Updates {
+//	        fmt.Printf("%s = %v\n", u.Path, u.Value)
+//	    }
+//	}
+//
+// Returns an empty slice if the response has no notifications.
+func (r GetRes) DecodedNotifications() []Notification {
+	notifs := make([]Notification, 0, len(r.Notifications))
+	for _, n := range r.Notifications {
+		notifs = append(notifs, newNotification(n))
+	}
+	return notifs
+}
+
+// Values returns the decoded update values of all notifications keyed by
+// full path (prefix + path).
+//
+// If several notifications update the same path, the last value wins.
+//
+// Example:
+//
+//	res, err := client.Get(ctx, []string{"/system/config/hostname"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	hostname := res.Values()["/system/config/hostname"] // "router1"
+//
+// Returns an empty map if the response has no updates.
+func (r GetRes) Values() map[string]any {
+	values := make(map[string]any)
+	for _, n := range r.Notifications {
+		for path, value := range newNotification(n).Values() {
+			values[path] = value
+		}
+	}
+	return values
+}
+
 // JSON returns the response notifications as a formatted JSON string.
 // This is useful for debugging, logging, or custom parsing.
 // Returns an empty string if marshaling fails.
@@ -105,6 +162,15 @@ type SetRes struct {
 
 	// Errors contains any error information
 	Errors []ErrorModel
+
+	// Extensions are the gNMI extensions returned by the target
+	Extensions Extensions
+
+	// Commit is the handle of a commit-confirmed Set (see CommitConfirmed)
+	// Nil for other Set operations. Also set if a commit-confirmed Set fails,
+	// since the target may have applied it before the error; Cancel it to
+	// roll back immediately instead of waiting for the rollback duration.
+	Commit *Commit
 }
 
 // GetValue retrieves a value from the SetResponse using a gjson path.
@@ -191,3 +257,37 @@ type CapabilitiesRes struct {
 	// Errors contains any error information
 	Errors []ErrorModel
 }
+
+// SubscribeRes represents a single response received on a gNMI Subscribe stream
+//
+// Each response carries either a Notification, a sync marker, or a reconnect
+// event. SyncResponse is true once the target has sent the complete initial
+// state (and after every poll for POLL subscriptions).
+type SubscribeRes struct {
+	// Notification contains the decoded notification (empty for sync responses)
+	Notification Notification
+
+	// SyncResponse indicates the initial state has been sent completely
+	SyncResponse bool
+
+	// Reconnected indicates the stream dropped and was re-established
+	// The target resends its full state followed by a new sync response,
+	// so consumers should discard state built from earlier responses.
+	// With UpdatesOnly(true), the target sends only the sync response and
+	// later changes; changes made while the stream was down are not resent,
+	// so consumers must refresh their state (e.g. with Get) instead.
+	// Errors contains the error that caused the stream to drop.
+	Reconnected bool
+
+	// Timestamp is the time the response was received (nanoseconds since Unix epoch)
+	Timestamp int64
+
+	// OK indicates if the response was received successfully
+	OK bool
+
+	// Errors contains any error information
+	Errors []ErrorModel
+
+	// Extensions are the gNMI extensions returned with the response
+	Extensions Extensions
+}
@@ -5,6 +5,7 @@ package gnmi
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/openconfig/gnmi/proto/gnmi"
@@ -402,3 +403,49 @@ func ExampleGetRes_GetValue() {
 
 // Suppress unused import warning
 var _ = anypb.Any{}
+
+// TestGetRes_Values tests decoding of Get notifications into a path/value map
+func TestGetRes_Values(t *testing.T) {
+	res := GetRes{
+		Notifications: []*gnmi.Notification{
+			{
+				Prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interfaces"}, {Name: "interface", Key: map[string]string{"name": "Gi0/0/0/0"}}}},
+				Update: []*gnmi.Update{
+					{
+						Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "state"}, {Name: "mtu"}}},
+						Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 9000}},
+					},
+					{
+						Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "config"}}},
+						Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"enabled": true}`)}},
+					},
+				},
+			},
+			{
+				Update: []*gnmi.Update{{
+					Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "config"}, {Name: "hostname"}}},
+					Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "router1"}},
+				}},
+			},
+		},
+	}
+
+	values := res.Values()
+	want := map[string]any{
+		"/interfaces/interface[name=Gi0/0/0/0]/state/mtu": uint64(9000),
+		"/interfaces/interface[name=Gi0/0/0/0]/config":    map[string]any{"enabled": true},
+		"/system/config/hostname":                         "router1",
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Values() = %v, want %v", values, want)
+	}
+
+	notifs := res.DecodedNotifications()
+	if len(notifs) != 2 || notifs[0].Prefix != "/interfaces/interface[name=Gi0/0/0/0]" || len(notifs[0].Updates) != 2 {
+		t.Errorf("DecodedNotifications() = %+v", notifs)
+	}
+
+	if empty := (GetRes{}).Values(); len(empty) != 0 {
+		t.Errorf("Values() of empty response = %v, want empty map", empty)
+	}
+}
@@ -0,0 +1,347 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy decides whether a failed attempt is retried and how long to wait
+//
+// A policy is consulted after every failed attempt of Get and Set, and after
+// every dropped stream or failed resubscription of Subscribe. Set a policy
+// per client with WithRetryPolicy or per request with RequestRetryPolicy.
+// Without a policy, the client retries transient errors (see TransientErrors)
+// up to MaxRetries times using Client.Backoff, and resubscribes indefinitely.
+//
+// Shipped policies: ExponentialBackoff, DecorrelatedJitter, NoRetry, and
+// IdempotentOnly.
+//
+// Example:
+//
+//	// Retry Unavailable only, at most twice, without delay
+//	type quickRetry struct{}
+//
+//	func (quickRetry) Retry(a gnmi.RetryAttempt) (time.Duration, bool) {
+//	    return 0, a.Code == codes.Unavailable && a.Attempt < 2
+//	}
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400", gnmi.WithRetryPolicy(quickRetry{}))
+//
+// Implementations must be safe for concurrent use.
+type RetryPolicy interface {
+	// Retry returns the delay before the next attempt and whether to retry
+	Retry(attempt RetryAttempt) (time.Duration, bool)
+}
+
+// RetryAttempt describes a failed attempt passed to RetryPolicy.Retry
+type RetryAttempt struct {
+	// Operation is the failed operation ("Get", "Set", or "Subscribe")
+	Operation string
+
+	// Attempt is the number of retries made so far (0 after the first failure)
+	Attempt int
+
+	// Err is the error of the failed attempt
+	Err error
+
+	// Code is the gRPC status code of Err
+	//
+	// Failures to connect to the target are reported as codes.Unavailable.
+	Code codes.Code
+
+	// Idempotent reports whether repeating the request is safe even if the
+	// failed attempt was (partially) applied by the target
+	//
+	// Get and Subscribe are always idempotent. A Set is idempotent if it
+	// consists of Replace and Delete operations only; Update operations merge
+	// into existing state and may not be safe to repeat.
+	Idempotent bool
+
+	// PrevDelay is the delay returned for the previous retry (0 on the first)
+	PrevDelay time.Duration
+}
+
+// ExponentialBackoff retries transient errors with exponential backoff
+//
+// The delay is min(MinDelay * Factor^attempt, MaxDelay) plus up to 10%
+// random jitter, matching Client.Backoff. Zero values of MinDelay, MaxDelay,
+// and Factor use the client defaults.
+//
+// Example:
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.WithRetryPolicy(gnmi.ExponentialBackoff{
+//	        MaxRetries: 5,
+//	        MinDelay:   500 * time.Millisecond,
+//	        Codes:      []codes.Code{codes.Unavailable},
+//	    }),
+//	)
+type ExponentialBackoff struct {
+	// MaxRetries is the maximum number of retries
+	MaxRetries int
+
+	// MinDelay is the delay before the first retry (default: 1s)
+	MinDelay time.Duration
+
+	// MaxDelay caps the delay between retries (default: 60s)
+	MaxDelay time.Duration
+
+	// Factor is the multiplier applied per retry (default: 2)
+	Factor float64
+
+	// Codes are the retryable gRPC status codes (default: TransientErrors)
+	Codes []codes.Code
+}
+
+// Retry implements RetryPolicy
+func (p ExponentialBackoff) Retry(attempt RetryAttempt) (time.Duration, bool) {
+	if attempt.Attempt >= p.MaxRetries || !retryableCode(attempt.Code, p.Codes) {
+		return 0, false
+	}
+
+	minDelay := defaultDuration(p.MinDelay, DefaultBackoffMinDelay)
+	maxDelay := defaultDuration(p.MaxDelay, DefaultBackoffMaxDelay)
+	factor := p.Factor
+	if factor == 0 {
+		factor = DefaultBackoffDelayFactor
+	}
+
+	delay, _, _, _ := exponentialDelay(minDelay, maxDelay, factor, attempt.Attempt) //nolint:dogsled // Only the delay is needed
+	return delay, true
+}
+
+// DecorrelatedJitter retries transient errors with decorrelated jitter backoff
+//
+// Each delay is a random value between BaseDelay and three times the
+// previous delay, capped at MaxDelay. Compared to ExponentialBackoff, this
+// spreads retries of many clients failing at the same time (e.g., after a
+// controller restart) more evenly. Zero values of BaseDelay and MaxDelay use
+// the client defaults.
+//
+// Example:
+//
+//	mgr := gnmi.NewManager(gnmi.ClientOptions(
+//	    gnmi.WithRetryPolicy(gnmi.DecorrelatedJitter{MaxRetries: 5}),
+//	))
+type DecorrelatedJitter struct {
+	// MaxRetries is the maximum number of retries
+	MaxRetries int
+
+	// BaseDelay is the minimum delay between retries (default: 1s)
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between retries (default: 60s)
+	MaxDelay time.Duration
+
+	// Codes are the retryable gRPC status codes (default: TransientErrors)
+	Codes []codes.Code
+}
+
+// Retry implements RetryPolicy
+func (p DecorrelatedJitter) Retry(attempt RetryAttempt) (time.Duration, bool) {
+	if attempt.Attempt >= p.MaxRetries || !retryableCode(attempt.Code, p.Codes) {
+		return 0, false
+	}
+
+	base := defaultDuration(p.BaseDelay, DefaultBackoffMinDelay)
+	maxDelay := defaultDuration(p.MaxDelay, DefaultBackoffMaxDelay)
+
+	upper := max(base, 3*min(attempt.PrevDelay, maxDelay))
+	delay := base
+	if upper > base {
+		n, _ := randInt63n(int64(upper - base)) //nolint:errcheck // Falls back to timestamp-based randomness
+		delay += time.Duration(n)
+	}
+	return min(delay, maxDelay), true
+}
+
+// noRetry is the RetryPolicy returned by NoRetry
+type noRetry struct{}
+
+// Retry implements RetryPolicy
+func (noRetry) Retry(RetryAttempt) (time.Duration, bool) {
+	return 0, false
+}
+
+// NoRetry returns a RetryPolicy that never retries
+//
+// Subscriptions using it are not resubscribed after the stream drops.
+//
+// Example:
+//
+//	res, err := client.Get(ctx, paths, gnmi.RequestRetryPolicy(gnmi.NoRetry()))
+func NoRetry() RetryPolicy {
+	return noRetry{}
+}
+
+// idempotentOnly is the RetryPolicy returned by IdempotentOnly
+type idempotentOnly struct {
+	policy RetryPolicy
+}
+
+// Retry implements RetryPolicy
+func (p idempotentOnly) Retry(attempt RetryAttempt) (time.Duration, bool) {
+	if !attempt.Idempotent {
+		return 0, false
+	}
+	return p.policy.Retry(attempt)
+}
+
+// IdempotentOnly returns a RetryPolicy that retries like policy but never
+// retries non-idempotent requests
+//
+// Use it when a failed Set may have been partially committed by the target
+// (e.g., Aborted during commit) and repeating Update operations is unsafe.
+// See RetryAttempt.Idempotent. A nil policy uses ExponentialBackoff with
+// DefaultMaxRetries.
+//
+// Example:
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.WithRetryPolicy(gnmi.IdempotentOnly(gnmi.ExponentialBackoff{MaxRetries: 3})),
+//	)
+func IdempotentOnly(policy RetryPolicy) RetryPolicy {
+	if policy == nil {
+		policy = ExponentialBackoff{MaxRetries: DefaultMaxRetries}
+	}
+	return idempotentOnly{policy: policy}
+}
+
+// clientRetryPolicy is the RetryPolicy used when none is configured
+//
+// It retries transient errors up to Client.MaxRetries times using
+// Client.Backoff.
+type clientRetryPolicy struct {
+	client *Client
+}
+
+// Retry implements RetryPolicy
+func (p clientRetryPolicy) Retry(attempt RetryAttempt) (time.Duration, bool) {
+	c := p.client
+	if attempt.Attempt >= c.MaxRetries || !c.checkTransientErrorModels(c.extractErrorDetails(attempt.Err)) {
+		return 0, false
+	}
+	return c.Backoff(attempt.Attempt), true
+}
+
+// retryPolicy returns the RetryPolicy for a request
+//
+// The request policy takes precedence over the client policy.
+//
+// Returns the policy and whether it was configured explicitly.
+func (c *Client) retryPolicy(req *Req) (RetryPolicy, bool) {
+	if req.RetryPolicy != nil {
+		return req.RetryPolicy, true
+	}
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy, true
+	}
+	return clientRetryPolicy{client: c}, false
+}
+
+// newRetryAttempt describes a failed attempt for a RetryPolicy
+func newRetryAttempt(operation string, attempt int, err error, idempotent bool, prevDelay time.Duration) RetryAttempt {
+	return RetryAttempt{
+		Operation:  operation,
+		Attempt:    attempt,
+		Err:        err,
+		Code:       attemptCode(err),
+		Idempotent: idempotent,
+		PrevDelay:  prevDelay,
+	}
+}
+
+// attemptCode returns the gRPC status code of a failed attempt
+//
+// Errors without a gRPC status are failures to connect and map to codes.Unavailable.
+func attemptCode(err error) codes.Code {
+	if st, ok := status.FromError(err); ok {
+		return st.Code()
+	}
+	return codes.Unavailable
+}
+
+// isIdempotentSet reports whether a Set with ops can safely be repeated
+func isIdempotentSet(ops []SetOperation) bool {
+	for _, op := range ops {
+		if op.OperationType == OperationUpdate {
+			return false
+		}
+	}
+	return true
+}
+
+// retryableCode reports whether code is in retryable, or in TransientErrors
+// if retryable is nil
+func retryableCode(code codes.Code, retryable []codes.Code) bool {
+	if retryable == nil {
+		for _, pattern := range TransientErrors {
+			if pattern.Code == uint32(code) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, c := range retryable {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultDuration returns d, or def if d is not positive
+func defaultDuration(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
+
+// exponentialDelay calculates an exponential backoff delay with up to 10% jitter
+//
+// Returns the delay, the base delay without jitter, the jitter, and the
+// crypto/rand error if timestamp-based jitter was used instead.
+func exponentialDelay(minDelay, maxDelay time.Duration, factor float64, attempt int) (time.Duration, time.Duration, time.Duration, error) {
+	// Calculate base delay: minDelay * (factor ^ attempt)
+	delay := float64(minDelay) * math.Pow(factor, float64(attempt))
+
+	// Check for overflow and cap at max delay
+	if math.IsInf(delay, 1) || delay > float64(maxDelay) {
+		delay = float64(maxDelay)
+	}
+
+	// Add jitter (0-10% of delay) to prevent thundering herd
+	var jitter int64
+	var err error
+	if jitterMax := int64(delay * 0.1); jitterMax > 0 {
+		jitter, err = randInt63n(jitterMax)
+	}
+
+	return time.Duration(delay) + time.Duration(jitter), time.Duration(delay), time.Duration(jitter), err
+}
+
+// randInt63n returns a cryptographically secure random value in [0, n)
+//
+// Security Note: Uses crypto/rand to prevent timing attack predictability.
+// If crypto/rand fails, falls back to timestamp-based randomness, which is
+// not cryptographically secure but sufficient for retry dispersal, and
+// returns the crypto/rand error.
+func randInt63n(n int64) (int64, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		timestamp := time.Now().UnixNano()
+		return (timestamp%n + n) % n, err // Ensure positive
+	}
+	// Mask off sign bit to ensure positive value within int64 range
+	//nolint:gosec // G115: False positive - explicitly masked to prevent overflow
+	return int64(binary.BigEndian.Uint64(b[:])&0x7FFFFFFFFFFFFFFF) % n, nil
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestRetryPolicies tests the retry decisions of the shipped policies
+func TestRetryPolicies(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	invalid := status.Error(codes.InvalidArgument, "invalid")
+
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		attempt   RetryAttempt
+		wantRetry bool
+	}{
+		{
+			name:      "exponential transient error",
+			policy:    ExponentialBackoff{MaxRetries: 2},
+			attempt:   newRetryAttempt("Get", 1, unavailable, true, 0),
+			wantRetry: true,
+		},
+		{
+			name:    "exponential retries exhausted",
+			policy:  ExponentialBackoff{MaxRetries: 2},
+			attempt: newRetryAttempt("Get", 2, unavailable, true, 0),
+		},
+		{
+			name:    "exponential permanent error",
+			policy:  ExponentialBackoff{MaxRetries: 2},
+			attempt: newRetryAttempt("Get", 0, invalid, true, 0),
+		},
+		{
+			name:      "exponential custom codes",
+			policy:    ExponentialBackoff{MaxRetries: 2, Codes: []codes.Code{codes.InvalidArgument}},
+			attempt:   newRetryAttempt("Get", 0, invalid, true, 0),
+			wantRetry: true,
+		},
+		{
+			name:    "exponential custom codes exclude transient errors",
+			policy:  ExponentialBackoff{MaxRetries: 2, Codes: []codes.Code{codes.InvalidArgument}},
+			attempt: newRetryAttempt("Get", 0, unavailable, true, 0),
+		},
+		{
+			name:      "decorrelated jitter transient error",
+			policy:    DecorrelatedJitter{MaxRetries: 1},
+			attempt:   newRetryAttempt("Subscribe", 0, unavailable, true, 0),
+			wantRetry: true,
+		},
+		{
+			name:    "decorrelated jitter retries exhausted",
+			policy:  DecorrelatedJitter{MaxRetries: 1},
+			attempt: newRetryAttempt("Subscribe", 1, unavailable, true, 0),
+		},
+		{
+			name:    "no retry",
+			policy:  NoRetry(),
+			attempt: newRetryAttempt("Get", 0, unavailable, true, 0),
+		},
+		{
+			name:      "idempotent only retries idempotent request",
+			policy:    IdempotentOnly(ExponentialBackoff{MaxRetries: 1}),
+			attempt:   newRetryAttempt("Set", 0, status.Error(codes.Aborted, "aborted"), true, 0),
+			wantRetry: true,
+		},
+		{
+			name:    "idempotent only rejects non-idempotent request",
+			policy:  IdempotentOnly(ExponentialBackoff{MaxRetries: 1}),
+			attempt: newRetryAttempt("Set", 0, status.Error(codes.Aborted, "aborted"), false, 0),
+		},
+		{
+			name:      "idempotent only with default policy",
+			policy:    IdempotentOnly(nil),
+			attempt:   newRetryAttempt("Get", DefaultMaxRetries-1, unavailable, true, 0),
+			wantRetry: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, retry := tt.policy.Retry(tt.attempt); retry != tt.wantRetry {
+				t.Errorf("Retry() = %v, want %v", retry, tt.wantRetry)
+			}
+		})
+	}
+}
+
+// TestRetryPolicies_Delay tests the delays of the backoff policies
+func TestRetryPolicies_Delay(t *testing.T) {
+	err := status.Error(codes.Unavailable, "unavailable")
+
+	t.Run("exponential", func(t *testing.T) {
+		p := ExponentialBackoff{MaxRetries: 10, MinDelay: 100 * time.Millisecond, MaxDelay: time.Second, Factor: 3}
+		for attempt, want := range []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, time.Second} {
+			delay, _ := p.Retry(newRetryAttempt("Get", attempt, err, true, 0))
+			if delay < want || delay > want+want/10 {
+				t.Errorf("attempt %d delay = %v, want %v plus up to 10%% jitter", attempt, delay, want)
+			}
+		}
+
+		delay, _ := ExponentialBackoff{MaxRetries: 1}.Retry(newRetryAttempt("Get", 0, err, true, 0))
+		if delay < DefaultBackoffMinDelay || delay > DefaultBackoffMinDelay*11/10 {
+			t.Errorf("default delay = %v, want %v plus jitter", delay, DefaultBackoffMinDelay)
+		}
+	})
+
+	t.Run("decorrelated jitter", func(t *testing.T) {
+		p := DecorrelatedJitter{MaxRetries: 100, BaseDelay: 10 * time.Millisecond, MaxDelay: 200 * time.Millisecond}
+		var prev time.Duration
+		for attempt := 0; attempt < 50; attempt++ {
+			delay, retry := p.Retry(newRetryAttempt("Get", attempt, err, true, prev))
+			if !retry {
+				t.Fatalf("attempt %d not retried", attempt)
+			}
+			upper := min(max(p.BaseDelay, 3*prev), p.MaxDelay)
+			if delay < p.BaseDelay || delay > upper {
+				t.Errorf("attempt %d delay = %v, want within [%v, %v]", attempt, delay, p.BaseDelay, upper)
+			}
+			prev = delay
+		}
+	})
+}
+
+// TestIsIdempotentSet tests the idempotency classification of Set requests
+func TestIsIdempotentSet(t *testing.T) {
+	if !isIdempotentSet([]SetOperation{Replace("/a", "{}"), Delete("/b")}) {
+		t.Errorf("Replace and Delete classified as non-idempotent")
+	}
+	if isIdempotentSet([]SetOperation{Replace("/a", "{}"), Update("/b", "{}")}) {
+		t.Errorf("Update classified as idempotent")
+	}
+}
+
+// TestIntegration_RetryPolicy tests client and request retry policies against the fake server
+func TestIntegration_RetryPolicy(t *testing.T) {
+	quick := ExponentialBackoff{MaxRetries: 3, MinDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	tests := []struct {
+		name      string
+		opts      []func(*Client)
+		mods      []func(*Req)
+		rpc       gnmitest.RPC
+		ops       []SetOperation
+		code      codes.Code
+		wantErr   bool
+		wantCalls int
+	}{
+		{
+			name:      "idempotent only does not retry update",
+			opts:      []func(*Client){WithRetryPolicy(IdempotentOnly(quick))},
+			rpc:       gnmitest.RPCSet,
+			ops:       []SetOperation{Update("/system/config", `{"hostname": "router2"}`)},
+			code:      codes.Aborted,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "idempotent only retries replace",
+			opts:      []func(*Client){WithRetryPolicy(IdempotentOnly(quick))},
+			rpc:       gnmitest.RPCSet,
+			ops:       []SetOperation{Replace("/system/config", `{"hostname": "router2"}`)},
+			code:      codes.Aborted,
+			wantCalls: 2,
+		},
+		{
+			name:      "idempotent only retries get",
+			opts:      []func(*Client){WithRetryPolicy(IdempotentOnly(quick))},
+			rpc:       gnmitest.RPCGet,
+			code:      codes.Unavailable,
+			wantCalls: 2,
+		},
+		{
+			name:      "request policy overrides client policy",
+			opts:      []func(*Client){WithRetryPolicy(quick)},
+			mods:      []func(*Req){RequestRetryPolicy(NoRetry())},
+			rpc:       gnmitest.RPCGet,
+			code:      codes.Unavailable,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "custom retryable codes",
+			opts:      []func(*Client){WithRetryPolicy(DecorrelatedJitter{MaxRetries: 1, BaseDelay: time.Millisecond, Codes: []codes.Code{codes.Internal}})},
+			rpc:       gnmitest.RPCGet,
+			code:      codes.Internal,
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, srv := newIntegrationClient(t, tt.opts...)
+			ctx := context.Background()
+
+			// Establish the connection before injecting failures
+			if err := client.Ping(ctx); err != nil {
+				t.Fatalf("Ping() error = %v", err)
+			}
+			srv.FailNext(tt.rpc, tt.code, 1)
+
+			var err error
+			if tt.rpc == gnmitest.RPCGet {
+				_, err = client.Get(ctx, []string{"/system/config"}, tt.mods...)
+			} else {
+				_, err = client.Set(ctx, tt.ops, tt.mods...)
+			}
+
+			if tt.wantErr {
+				var gnmiErr *GnmiError
+				if !errors.As(err, &gnmiErr) || gnmiErr.Code != tt.code || gnmiErr.Retries != 0 {
+					t.Errorf("error = %v, want %v without retries", err, tt.code)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if calls := srv.Calls(tt.rpc); calls != tt.wantCalls {
+				t.Errorf("server calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+
+	t.Run("subscribe without retry", func(t *testing.T) {
+		client, srv := newIntegrationClient(t, WithRetryPolicy(NoRetry()))
+
+		stream, err := client.Subscribe(testContext(t), []Subscription{OnChange("/system/config/hostname")})
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		waitForSync(t, stream)
+
+		srv.DropConnections()
+
+		for res := range stream.Responses() {
+			if res.Reconnected {
+				t.Errorf("stream resubscribed with NoRetry policy")
+			}
+		}
+		if stream.Err() == nil {
+			t.Errorf("Err() = nil, want stream failure")
+		}
+	})
+
+	t.Run("subscribe with policy resubscribes", func(t *testing.T) {
+		client, srv := newIntegrationClient(t, WithRetryPolicy(quick))
+
+		stream, err := client.Subscribe(testContext(t), []Subscription{OnChange("/system/config/hostname")})
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		defer stream.Close()
+		waitForSync(t, stream)
+
+		srv.DropConnections()
+
+		if res := nextResponse(t, stream); !res.Reconnected {
+			t.Errorf("response = %+v, want Reconnected", res)
+		}
+	})
+}
+
+// testContext returns a context canceled when the test ends
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	c, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return c
+}
@@ -0,0 +1,983 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmic/pkg/api"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// SubscriptionListMode represents the mode of a gNMI SubscriptionList
+type SubscriptionListMode string
+
+const (
+	// SubscribeModeStream streams updates until the subscription is closed (default)
+	SubscribeModeStream SubscriptionListMode = "stream"
+
+	// SubscribeModeOnce sends the current state once and closes the stream
+	SubscribeModeOnce SubscriptionListMode = "once"
+
+	// SubscribeModePoll sends the current state each time the client polls
+	SubscribeModePoll SubscriptionListMode = "poll"
+)
+
+// SubscriptionMode represents the mode of a single STREAM subscription
+type SubscriptionMode string
+
+const (
+	// SubscriptionModeTargetDefined lets the target choose the best mode per leaf (default)
+	SubscriptionModeTargetDefined SubscriptionMode = "target_defined"
+
+	// SubscriptionModeOnChange sends updates only when the value changes
+	SubscriptionModeOnChange SubscriptionMode = "on_change"
+
+	// SubscriptionModeSample sends updates periodically at the sample interval
+	SubscriptionModeSample SubscriptionMode = "sample"
+)
+
+// DefaultSubscribeBufferSize is the number of responses buffered per subscription
+// before the receive loop blocks waiting for the consumer.
+const DefaultSubscribeBufferSize = 100
+
+// Subscription represents a single path subscription within a Subscribe request
+//
+// Subscription modes and intervals only apply to STREAM subscriptions. For ONCE
+// and POLL subscriptions only the path is used.
+type Subscription struct {
+	// Path is the gNMI path to subscribe to
+	Path string
+
+	// Mode specifies the STREAM subscription mode (target_defined, on_change, sample)
+	Mode SubscriptionMode
+
+	// SampleInterval is the sampling interval for SAMPLE subscriptions
+	// Zero lets the target choose the lowest supported interval
+	SampleInterval time.Duration
+
+	// HeartbeatInterval forces the target to resend values at this interval
+	// even if they did not change (ON_CHANGE and SAMPLE with suppress redundant)
+	HeartbeatInterval time.Duration
+
+	// SuppressRedundant suppresses SAMPLE updates for values that did not change
+	SuppressRedundant bool
+
+	// pathErr is the builder error of a Path passed to SamplePath, OnChangePath,
+	// or TargetDefinedPath (reported by Subscribe)
+	pathErr error
+}
+
+// SubscribeStream represents an active gNMI Subscribe RPC
+//
+// Responses are delivered on the channel returned by Responses(). The channel
+// is closed when the subscription ends, after which Err() reports the reason
+// (nil if the stream was closed by the client or completed normally).
+//
+// If the stream drops with a transient gRPC error, the subscription is
+// re-established automatically (see Client.Subscribe) and a SubscribeRes with
+// Reconnected set is delivered before the target resends its state (only
+// the sync response with UpdatesOnly).
+//
+// Thread-safe: Responses, Err, Poll, and Close may be called concurrently.
+type SubscribeStream struct {
+	client  *Client
+	mode    SubscriptionListMode
+	request *gnmipb.SubscribeRequest
+	start   time.Time
+	cancel  context.CancelFunc
+
+	// release ends the client operation held by the stream
+	release func()
+
+	// resubscribe enables automatic resubscription on transient errors
+	resubscribe bool
+
+	// policy decides about resubscription (nil resubscribes on transient
+	// errors indefinitely using Client.Backoff)
+	policy RetryPolicy
+
+	// credentialsRefreshed is set while the stream was reopened with reloaded
+	// credentials and no response was received yet (receive goroutine only)
+	credentialsRefreshed bool
+
+	// stream and gnmiClient are replaced on resubscription (guarded by sendMu)
+	stream     gnmipb.GNMI_SubscribeClient
+	gnmiClient gnmipb.GNMIClient
+
+	responses chan SubscribeRes
+	done      chan struct{}
+
+	// sendMu serializes Send calls on the stream (gRPC forbids concurrent sends)
+	// and guards stream replacement during resubscription
+	sendMu sync.Mutex
+
+	mu     sync.Mutex
+	err    error
+	closed bool
+}
+
+// Sample creates a Subscription with SAMPLE mode for the given path
+//
+// Parameters:
+//   - path: gNMI path string (e.g., "/interfaces/interface/state/counters")
+//   - interval: sampling interval (zero lets the target choose)
+//   - opts: optional modifiers (HeartbeatInterval, SuppressRedundant)
+//
+// Example:
+//
+//	sub := gnmi.Sample("/interfaces/interface/state/counters", 10*time.Second)
+func Sample(path string, interval time.Duration, opts ...func(*Subscription)) Subscription {
+	sub := Subscription{
+		Path:           path,
+		Mode:           SubscriptionModeSample,
+		SampleInterval: interval,
+	}
+
+	// Apply functional options
+	for _, opt := range opts {
+		opt(&sub)
+	}
+
+	return sub
+}
+
+// OnChange creates a Subscription with ON_CHANGE mode for the given path
+//
+// Parameters:
+//   - path: gNMI path string (e.g., "/interfaces/interface/state/oper-status")
+//   - opts: optional modifiers (HeartbeatInterval)
+//
+// Example:
+//
+//	sub := gnmi.OnChange("/interfaces/interface/state/oper-status")
+func OnChange(path string, opts ...func(*Subscription)) Subscription {
+	sub := Subscription{
+		Path: path,
+		Mode: SubscriptionModeOnChange,
+	}
+
+	// Apply functional options
+	for _, opt := range opts {
+		opt(&sub)
+	}
+
+	return sub
+}
+
+// TargetDefined creates a Subscription with TARGET_DEFINED mode for the given path
+//
+// This is also the subscription to use for ONCE and POLL subscriptions, where
+// the subscription mode is ignored by the target.
+//
+// Example:
+//
+//	sub := gnmi.TargetDefined("/system/state")
+func TargetDefined(path string, opts ...func(*Subscription)) Subscription {
+	sub := Subscription{
+		Path: path,
+		Mode: SubscriptionModeTargetDefined,
+	}
+
+	// Apply functional options
+	for _, opt := range opts {
+		opt(&sub)
+	}
+
+	return sub
+}
+
+// SamplePath is like Sample but takes a structured Path
+//
+// Example:
+//
+//	path := gnmi.NewPath().Elem("interfaces").Elem("interface", "name", "Gi0/0/0/0").Elem("state")
+//	sub := gnmi.SamplePath(path, 10*time.Second)
+func SamplePath(path Path, interval time.Duration, opts ...func(*Subscription)) Subscription {
+	sub := Sample(path.String(), interval, opts...)
+	sub.pathErr = path.Err()
+	return sub
+}
+
+// OnChangePath is like OnChange but takes a structured Path
+//
+// Example:
+//
+//	sub := gnmi.OnChangePath(gnmi.NewPath().Elem("system").Elem("state"))
+func OnChangePath(path Path, opts ...func(*Subscription)) Subscription {
+	sub := OnChange(path.String(), opts...)
+	sub.pathErr = path.Err()
+	return sub
+}
+
+// TargetDefinedPath is like TargetDefined but takes a structured Path
+//
+// Example:
+//
+//	sub := gnmi.TargetDefinedPath(gnmi.NewPath().Elem("system").Elem("state"))
+func TargetDefinedPath(path Path, opts ...func(*Subscription)) Subscription {
+	sub := TargetDefined(path.String(), opts...)
+	sub.pathErr = path.Err()
+	return sub
+}
+
+// validateSubscriptions validates a slice of Subscription structs
+//
+// Checks:
+//   - Subscriptions slice is not empty
+//   - Each subscription has a valid path
+//   - Each subscription has a valid mode (empty defaults to target_defined)
+//   - Intervals are not negative
+//
+// Returns an error if any subscription is invalid with a descriptive message.
+func validateSubscriptions(subs []Subscription) error {
+	if len(subs) == 0 {
+		return fmt.Errorf("subscriptions cannot be empty")
+	}
+
+	for i, sub := range subs {
+		// Validate path
+		if sub.pathErr != nil {
+			return fmt.Errorf("subscription at index %d: %w", i, sub.pathErr)
+		}
+		if err := validatePaths([]string{sub.Path}); err != nil {
+			return fmt.Errorf("subscription at index %d: %w", i, err)
+		}
+
+		// Validate mode
+		switch sub.Mode {
+		case "", SubscriptionModeTargetDefined, SubscriptionModeOnChange, SubscriptionModeSample:
+		default:
+			return fmt.Errorf("subscription mode invalid: %s (must be 'target_defined', 'on_change', or 'sample', at index %d)", sub.Mode, i)
+		}
+
+		// Validate intervals
+		if sub.SampleInterval < 0 {
+			return fmt.Errorf("sample interval must be non-negative, got: %v (at index %d)", sub.SampleInterval, i)
+		}
+		if sub.HeartbeatInterval < 0 {
+			return fmt.Errorf("heartbeat interval must be non-negative, got: %v (at index %d)", sub.HeartbeatInterval, i)
+		}
+	}
+
+	return nil
+}
+
+// validateSubscriptionListMode validates a SubscriptionListMode
+//
+// Empty mode is valid (will default to stream).
+//
+// Returns an error if the mode is not supported.
+func validateSubscriptionListMode(mode SubscriptionListMode) error {
+	switch mode {
+	case "", SubscribeModeStream, SubscribeModeOnce, SubscribeModePoll:
+		return nil
+	default:
+		return fmt.Errorf("invalid subscribe mode: %s (valid values: stream, once, poll)", mode)
+	}
+}
+
+// buildSubscribeRequest builds a gNMI SubscribeRequest from subscriptions and request modifiers
+//
+// PRECONDITION: Subscriptions and request must be validated.
+//
+// Returns the SubscribeRequest or an error if gnmic rejects an option.
+func buildSubscribeRequest(subs []Subscription, req *Req) (*gnmipb.SubscribeRequest, error) {
+	gnmicOpts := []api.GNMIOption{
+		api.SubscriptionListMode(string(req.Mode)),
+		api.Encoding(req.Encoding),
+		api.UpdatesOnly(req.UpdatesOnly),
+	}
+
+	prefix, err := buildPrefix(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range subs {
+		gp, err := requestPath(sub.Path, req, prefix)
+		if err != nil {
+			return nil, err
+		}
+		subOpts := []api.GNMIOption{
+			protoPath(gp),
+		}
+
+		// Subscription modes and intervals only apply to STREAM subscriptions
+		if req.Mode == SubscribeModeStream {
+			mode := sub.Mode
+			if mode == "" {
+				mode = SubscriptionModeTargetDefined
+			}
+			subOpts = append(subOpts,
+				api.SubscriptionMode(string(mode)),
+				api.SampleInterval(sub.SampleInterval),
+				api.HeartbeatInterval(sub.HeartbeatInterval),
+				api.SuppressRedundant(sub.SuppressRedundant))
+		}
+
+		gnmicOpts = append(gnmicOpts, api.Subscription(subOpts...))
+	}
+	for _, ext := range req.Extensions {
+		gnmicOpts = append(gnmicOpts, api.Extension(ext))
+	}
+
+	subReq, err := api.NewSubscribeRequest(gnmicOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := protoPrefix(prefix)(subReq); err != nil {
+		return nil, err
+	}
+
+	return subReq, nil
+}
+
+// Subscribe performs a gNMI Subscribe operation and returns the active stream
+//
+// Subscribe supports the three gNMI subscription list modes, selected via the
+// SubscribeMode() request modifier:
+//   - stream (default): updates are streamed until the stream is closed
+//   - once: the current state is sent once, then the stream completes
+//   - poll: the current state is sent each time Poll() is called
+//
+// For STREAM subscriptions, each Subscription selects SAMPLE, ON_CHANGE, or
+// TARGET_DEFINED mode (see Sample(), OnChange(), TargetDefined()).
+//
+// The connection is established lazily, like Get. The subscription lives until
+// ctx is canceled, Close() is called, or the target ends the stream. Unlike Get,
+// the OperationTimeout does not bound the lifetime of the stream.
+//
+// Automatic resubscription: if the stream drops with a transient gRPC error
+// (see TransientErrors), the client waits according to its backoff settings,
+// reconnects if the error is a transport error, and re-sends the same
+// SubscriptionList. Consumers receive a SubscribeRes with Reconnected set,
+// followed by the full state and a new sync response, so caches built from
+// the stream can be discarded and rebuilt. With UpdatesOnly(true) the full
+// state is not resent, so changes made while the stream was down are missed.
+// Attempts continue until ctx is canceled or Close() is called. Use
+// Resubscribe(false) to disable. With a RetryPolicy (WithRetryPolicy or
+// RequestRetryPolicy), the policy decides which errors are resubscribed, how
+// long to wait, and when to give up.
+//
+// Example:
+//
+//	subs := []gnmi.Subscription{
+//	    gnmi.Sample("/interfaces/interface/state/counters", 10*time.Second),
+//	    gnmi.OnChange("/interfaces/interface/state/oper-status"),
+//	}
+//	stream, err := client.Subscribe(ctx, subs)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer stream.Close()
+//
+//	for res := range stream.Responses() {
+//	    if res.SyncResponse {
+//	        fmt.Println("initial sync complete")
+//	        continue
+//	    }
+//	    for _, u := range res.Notification.Updates {
+//	        fmt.Printf("%s = %v\n", u.Path, u.Value)
+//	    }
+//	}
+//	if err := stream.Err(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Returns a SubscribeStream or an error if validation, connection, or the
+// initial request fails.
+func (c *Client) Subscribe(ctx context.Context, subs []Subscription, mods ...func(*Req)) (*SubscribeStream, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "Subscribe")
+	s, err := c.doSubscribe(ctx, start, subs, mods)
+	c.observeOperation(ctx, "Subscribe", start, err)
+	endSpan(span, err)
+	return s, err
+}
+
+// doSubscribe implements Subscribe; start is the start time of the operation
+func (c *Client) doSubscribe(ctx context.Context, start time.Time, subs []Subscription, mods []func(*Req)) (*SubscribeStream, error) {
+	// The operation lasts until the stream ends
+	endOperation := c.beginOperation()
+	started := false
+	defer func() {
+		if !started {
+			endOperation()
+		}
+	}()
+
+	// Validate subscriptions (before acquiring lock)
+	if err := validateSubscriptions(subs); err != nil {
+		return nil, newGnmiError("Subscribe", ErrValidation, err, start)
+	}
+
+	// Build request with default encoding, mode, and resubscription
+	req := &Req{
+		Encoding:    EncodingJSONIETF,
+		Mode:        SubscribeModeStream,
+		Resubscribe: true,
+	}
+
+	// Apply modifiers
+	for _, mod := range mods {
+		mod(req)
+	}
+	c.setSpanAttributes(ctx, attrPaths.Int(len(subs)), attrEncoding.String(req.Encoding), attrMode.String(string(req.Mode)))
+
+	// Validate encoding and mode (before acquiring lock)
+	if err := validateEncoding(req.Encoding); err != nil {
+		return nil, newGnmiError("Subscribe", ErrValidation, err, start)
+	}
+	if err := validateSubscriptionListMode(req.Mode); err != nil {
+		return nil, newGnmiError("Subscribe", ErrValidation, err, start)
+	}
+	if err := validateRequestPrefix(req); err != nil {
+		return nil, newGnmiError("Subscribe", ErrValidation, err, start)
+	}
+	if err := validateExtensions("Subscribe", req); err != nil {
+		return nil, newGnmiError("Subscribe", ErrValidation, err, start)
+	}
+	if req.Encoding == "" {
+		req.Encoding = EncodingJSONIETF
+	}
+	if req.Mode == "" {
+		req.Mode = SubscribeModeStream
+	}
+
+	// Check context cancellation first (before acquiring lock)
+	if err := checkContextCancellation(ctx); err != nil {
+		return nil, newGnmiError("Subscribe", nil, err, start)
+	}
+
+	subReq, err := buildSubscribeRequest(subs, req)
+	if err != nil {
+		c.logger.Error(ctx, "gNMI Subscribe request creation failed",
+			"target", c.Target,
+			"error", err.Error())
+		return nil, newGnmiError("Subscribe", ErrValidation, fmt.Errorf("failed to create request: %w", err), start)
+	}
+
+	// Fail fast while the circuit breaker is open
+	if err := c.checkCircuit(ctx); err != nil {
+		return nil, newGnmiError("Subscribe", ErrCircuitOpen, err, start)
+	}
+
+	// Wait for the request limiter; the slot is held until the stream is open
+	release, err := c.waitLimiter(ctx, "Subscribe")
+	if err != nil {
+		return nil, newGnmiError("Subscribe", nil, err, start)
+	}
+	defer release()
+
+	// Ensure connection is established (lazy connection)
+	if err := c.ensureConnected(ctx); err != nil {
+		c.recordOutcome(ctx, err)
+		return nil, newGnmiError("Subscribe", ErrNotConnected, fmt.Errorf("connection failed: %w", err), start)
+	}
+
+	// Log request
+	c.logger.Debug(ctx, "gNMI Subscribe request",
+		"target", c.Target,
+		"mode", req.Mode,
+		"subscriptions", len(subs),
+		"encoding", req.Encoding)
+
+	// Log each subscription (at Debug level)
+	for i, sub := range subs {
+		c.logger.Debug(ctx, "gNMI Subscribe path",
+			"index", i,
+			"path", sub.Path,
+			"mode", sub.Mode,
+			"sample_interval", sub.SampleInterval.String())
+	}
+
+	// The stream outlives this call, so it gets its own cancelable context
+	// derived from the caller's context (no operation timeout applied)
+	streamCtx, cancel := context.WithCancel(ctx)
+
+	stream, gnmiClient, err := c.openSubscribeStream(streamCtx, subReq)
+	c.recordOutcome(ctx, err)
+	if err != nil {
+		cancel()
+		c.logger.Error(ctx, "gNMI Subscribe failed",
+			"target", c.Target,
+			"error", err.Error())
+		gnmiErr := newGnmiError("Subscribe", nil, fmt.Errorf("request failed: %w", err), start)
+		gnmiErr.Errors = c.extractErrorDetails(err)
+		return nil, gnmiErr
+	}
+
+	var policy RetryPolicy
+	if p, custom := c.retryPolicy(req); custom {
+		policy = p
+	}
+
+	s := &SubscribeStream{
+		client:      c,
+		mode:        req.Mode,
+		request:     subReq,
+		start:       start,
+		cancel:      cancel,
+		release:     endOperation,
+		resubscribe: req.Resubscribe,
+		policy:      policy,
+		stream:      stream,
+		gnmiClient:  gnmiClient,
+		responses:   make(chan SubscribeRes, DefaultSubscribeBufferSize),
+		done:        make(chan struct{}),
+	}
+
+	started = true
+	c.observeSubscriptions(ctx, 1)
+	go s.receive(streamCtx)
+
+	c.logger.Info(ctx, "gNMI subscription started",
+		"target", c.Target,
+		"mode", req.Mode,
+		"subscriptions", len(subs))
+
+	return s, nil
+}
+
+// openSubscribeStream opens a Subscribe RPC on the current connection and sends the request
+//
+// The read lock is only held while the gNMI client handle is retrieved, so a
+// long-lived stream does not block Set, Disconnect, or Close.
+//
+// Returns the open stream and the gNMI client handle it was opened on, or an
+// error if the stream cannot be created.
+func (c *Client) openSubscribeStream(ctx context.Context, subReq *gnmipb.SubscribeRequest) (gnmipb.GNMI_SubscribeClient, gnmipb.GNMIClient, error) {
+	c.mu.RLock()
+	if c.target == nil || c.target.Client == nil {
+		c.mu.RUnlock()
+		return nil, nil, fmt.Errorf("client not connected")
+	}
+	gnmiClient := c.target.Client
+	username, password := c.username, c.password
+	c.mu.RUnlock()
+
+	// gnmic only attaches credentials for its own RPC wrappers, so add them here
+	if username != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "username", username)
+	}
+	if password != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "password", password)
+	}
+
+	stream, err := gnmiClient.Subscribe(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := stream.Send(subReq); err != nil {
+		return nil, nil, err
+	}
+	c.observeBytes(ctx, "Subscribe", subReq, nil)
+
+	return stream, gnmiClient, nil
+}
+
+// reconnectStale reconnects the client unless another caller already replaced
+// the connection that the stale gNMI client handle belongs to
+//
+// Several subscriptions on the same client typically fail together when the
+// connection drops. Only the first one reconnects; the others reuse the new
+// connection instead of tearing it down again.
+//
+// Returns an error if the client was closed or reconnection fails.
+func (c *Client) reconnectStale(ctx context.Context, stale gnmipb.GNMIClient) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.target == nil {
+		return ErrClosed
+	}
+
+	if c.connected && c.target.Client != nil && c.target.Client != stale {
+		// Connection was already replaced by another operation
+		return nil
+	}
+
+	return c.reconnect(ctx)
+}
+
+// Responses returns the channel on which subscription responses are delivered
+//
+// The channel is closed when the subscription ends. Check Err() after the
+// channel is closed to distinguish normal completion from failures.
+func (s *SubscribeStream) Responses() <-chan SubscribeRes {
+	return s.responses
+}
+
+// Err returns the error that terminated the subscription
+//
+// Returns nil while the subscription is active, after Close(), after a ONCE
+// subscription completes, or when the target ends the stream cleanly.
+func (s *SubscribeStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Poll requests a new snapshot on a POLL subscription
+//
+// The target answers with the current state followed by a sync response,
+// delivered on the Responses() channel.
+//
+// Example:
+//
+//	stream, err := client.Subscribe(ctx, subs, gnmi.SubscribeMode(gnmi.SubscribeModePoll))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer stream.Close()
+//
+//	if err := stream.Poll(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Returns an error if the subscription is not in poll mode or the request fails.
+func (s *SubscribeStream) Poll(ctx context.Context) error {
+	start := time.Now()
+
+	if s.mode != SubscribeModePoll {
+		return newGnmiError("Poll", ErrValidation, fmt.Errorf("poll requires subscribe mode %q, got %q", SubscribeModePoll, s.mode), start)
+	}
+
+	if err := checkContextCancellation(ctx); err != nil {
+		return newGnmiError("Poll", nil, err, start)
+	}
+
+	select {
+	case <-s.done:
+		return newGnmiError("Poll", ErrClosed, fmt.Errorf("subscription closed"), start)
+	default:
+	}
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	s.client.logger.Debug(ctx, "gNMI Subscribe poll",
+		"target", s.client.Target)
+
+	if err := s.stream.Send(&gnmipb.SubscribeRequest{
+		Request: &gnmipb.SubscribeRequest_Poll{Poll: &gnmipb.Poll{}},
+	}); err != nil {
+		return newGnmiError("Poll", nil, fmt.Errorf("poll failed: %w", err), start)
+	}
+
+	return nil
+}
+
+// Close terminates the subscription and waits for the receive loop to exit
+//
+// Thread-safe: safe to call multiple times (subsequent calls are no-ops).
+func (s *SubscribeStream) Close() error {
+	s.mu.Lock()
+	alreadyClosed := s.closed
+	s.closed = true
+	s.mu.Unlock()
+
+	s.cancel()
+	<-s.done
+
+	if !alreadyClosed {
+		s.client.logger.Info(context.Background(), "gNMI subscription closed",
+			"target", s.client.Target)
+	}
+
+	return nil
+}
+
+// receive reads responses from the stream until it ends and forwards them to the consumer
+//
+// Runs in its own goroutine. Closes the responses channel on exit.
+func (s *SubscribeStream) receive(ctx context.Context) {
+	defer close(s.done)
+	defer func() {
+		s.client.observeSubscriptions(ctx, -1)
+		if s.release != nil {
+			s.release()
+		}
+	}()
+	defer close(s.responses)
+	defer s.cancel()
+
+	for {
+		s.sendMu.Lock()
+		stream := s.stream
+		s.sendMu.Unlock()
+
+		resp, err := stream.Recv()
+		s.client.observeBytes(ctx, "Subscribe", nil, resp)
+		if err != nil {
+			if s.refreshCredentials(ctx, err) {
+				continue
+			}
+			if !s.shouldResubscribe(ctx, err) {
+				s.finish(ctx, err)
+				return
+			}
+
+			resubCtx, span := s.client.startSpan(ctx, "Resubscribe", attribute.String("rpc.method", "Subscribe"))
+			resubErr := s.resubscribeWithBackoff(resubCtx, err)
+			endSpan(span, resubErr)
+			if resubErr != nil {
+				s.finish(ctx, resubErr)
+				return
+			}
+
+			// Tell the consumer the stream was re-established; the target
+			// resends its state followed by a new sync response
+			res := SubscribeRes{
+				Reconnected: true,
+				Timestamp:   time.Now().UnixNano(),
+				OK:          true,
+				Errors:      s.client.extractErrorDetails(err),
+			}
+			select {
+			case s.responses <- res:
+			case <-ctx.Done():
+				s.finish(ctx, ctx.Err())
+				return
+			}
+			continue
+		}
+
+		s.credentialsRefreshed = false
+		res, ok := s.convert(ctx, resp)
+		if !ok {
+			continue
+		}
+
+		select {
+		case s.responses <- res:
+		case <-ctx.Done():
+			s.finish(ctx, ctx.Err())
+			return
+		}
+
+		// ONCE subscriptions complete after the initial sync
+		if s.mode == SubscribeModeOnce && res.SyncResponse {
+			s.finish(ctx, nil)
+			return
+		}
+	}
+}
+
+// refreshCredentials reopens the stream once on a new connection with
+// reloaded credentials after the target rejected the current ones
+//
+// Returns true if the stream was reopened.
+func (s *SubscribeStream) refreshCredentials(ctx context.Context, err error) bool {
+	c := s.client
+	if s.credentialsRefreshed || ctx.Err() != nil || !c.shouldRefreshCredentials(err) {
+		return false
+	}
+	s.credentialsRefreshed = true
+
+	c.mu.Lock()
+	refreshErr := c.refreshCredentials(ctx)
+	c.mu.Unlock()
+	if refreshErr != nil {
+		return false
+	}
+
+	c.observeRetry(ctx, "Subscribe", 1, 0, err)
+	stream, gnmiClient, openErr := c.openSubscribeStream(ctx, s.request)
+	if openErr != nil {
+		return false
+	}
+
+	s.sendMu.Lock()
+	s.stream = stream
+	s.gnmiClient = gnmiClient
+	s.sendMu.Unlock()
+
+	return true
+}
+
+// shouldResubscribe checks if a stream error should trigger automatic resubscription
+//
+// Resubscription requires that it is enabled, the subscription was not closed,
+// the context is still valid, and the error is transient (see TransientErrors).
+// With a RetryPolicy, the policy decides instead of TransientErrors. A cleanly
+// ended stream (io.EOF) is never resubscribed.
+func (s *SubscribeStream) shouldResubscribe(ctx context.Context, err error) bool {
+	if !s.resubscribe || errors.Is(err, io.EOF) || ctx.Err() != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return false
+	}
+
+	if s.policy != nil {
+		return true
+	}
+	return s.client.checkTransientErrorModels(s.client.extractErrorDetails(err))
+}
+
+// resubscribeWithBackoff re-establishes the subscription after a transient failure
+//
+// Each attempt waits for Client.Backoff(attempt) (or the delay of the retry
+// policy), reconnects if the previous error was a transport error, and
+// re-sends the original SubscribeRequest. Attempts continue until one
+// succeeds, ctx is canceled, or a permanent error occurs (client closed,
+// non-transient gRPC error, or the retry policy gives up).
+//
+// Returns nil once a new stream is established, or cause if the retry policy
+// rejects it.
+func (s *SubscribeStream) resubscribeWithBackoff(ctx context.Context, cause error) error {
+	c := s.client
+	lastErr := cause
+	var delay time.Duration
+
+	for attempt := 0; ; attempt++ {
+		if s.policy == nil {
+			delay = c.Backoff(attempt)
+		} else {
+			var retry bool
+			delay, retry = s.policy.Retry(newRetryAttempt("Subscribe", attempt, lastErr, true, delay))
+			if !retry {
+				if attempt == 0 {
+					return cause
+				}
+				return fmt.Errorf("resubscribe: %w", lastErr)
+			}
+		}
+
+		c.observeRetry(ctx, "Subscribe", attempt+1, delay, lastErr)
+		c.logger.Warn(ctx, "gNMI subscription dropped, resubscribing",
+			"target", c.Target,
+			"attempt", attempt+1,
+			"backoff", delay,
+			"error", lastErr.Error())
+
+		// Sleep with context cancellation awareness
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		// Reconnect on transport errors and after failed reconnection attempts
+		s.sendMu.Lock()
+		stale := s.gnmiClient
+		s.sendMu.Unlock()
+
+		_, isGRPC := status.FromError(lastErr)
+		if c.isTransportError(lastErr) || !isGRPC {
+			if err := c.reconnectStale(ctx, stale); err != nil {
+				if errors.Is(err, ErrClosed) {
+					return fmt.Errorf("resubscribe: %w", err)
+				}
+				lastErr = err
+				continue
+			}
+		}
+
+		stream, gnmiClient, err := c.openSubscribeStream(ctx, s.request)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if _, isGRPC := status.FromError(err); isGRPC && s.policy == nil && !c.checkTransientErrorModels(c.extractErrorDetails(err)) {
+				return fmt.Errorf("resubscribe: %w", err)
+			}
+			lastErr = err
+			continue
+		}
+
+		s.sendMu.Lock()
+		s.stream = stream
+		s.gnmiClient = gnmiClient
+		s.sendMu.Unlock()
+
+		c.logger.Info(ctx, "gNMI subscription re-established",
+			"target", c.Target,
+			"attempts", attempt+1)
+
+		return nil
+	}
+}
+
+// convert converts a SubscribeResponse into a SubscribeRes and logs it
+//
+// Returns false if the response carries no update or sync marker.
+func (s *SubscribeStream) convert(ctx context.Context, resp *gnmipb.SubscribeResponse) (SubscribeRes, bool) {
+	c := s.client
+
+	switch r := resp.GetResponse().(type) {
+	case *gnmipb.SubscribeResponse_Update:
+		// Log notification with redacted JSON values (at Debug level)
+		if notifJSON, err := json.Marshal(r.Update); err == nil {
+			sanitizedNotif := c.prepareJSONForLogging(string(notifJSON))
+			c.logger.Debug(ctx, "gNMI Subscribe notification",
+				"target", c.Target,
+				"timestamp", r.Update.GetTimestamp(),
+				"updates", len(r.Update.GetUpdate()),
+				"deletes", len(r.Update.GetDelete()),
+				"notification", sanitizedNotif)
+		}
+
+		return SubscribeRes{
+			Notification: newNotification(r.Update),
+			Timestamp:    time.Now().UnixNano(),
+			OK:           true,
+			Extensions:   resp.GetExtension(),
+		}, true
+	case *gnmipb.SubscribeResponse_SyncResponse:
+		c.logger.Debug(ctx, "gNMI Subscribe sync response",
+			"target", c.Target)
+
+		return SubscribeRes{
+			SyncResponse: r.SyncResponse,
+			Timestamp:    time.Now().UnixNano(),
+			OK:           true,
+			Extensions:   resp.GetExtension(),
+		}, true
+	default:
+		return SubscribeRes{}, false
+	}
+}
+
+// finish records the terminal error of the subscription
+//
+// Errors caused by Close() or a cleanly ended stream (io.EOF) are not reported.
+func (s *SubscribeStream) finish(ctx context.Context, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || err == nil || errors.Is(err, io.EOF) {
+		return
+	}
+
+	// Report the caller's context error instead of the gRPC wrapping of it
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		err = ctxErr
+	}
+
+	s.client.logger.Error(ctx, "gNMI subscription failed",
+		"target", s.client.Target,
+		"error", err.Error())
+
+	gnmiErr := newGnmiError("Subscribe", nil, fmt.Errorf("stream failed: %w", err), s.start)
+	gnmiErr.Errors = s.client.extractErrorDetails(err)
+	gnmiErr.IsTransient = s.client.checkTransientErrorModels(gnmiErr.Errors)
+	s.err = gnmiErr
+}
@@ -0,0 +1,728 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	target "github.com/openconfig/gnmic/pkg/api/target"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeSubscribeClient is a minimal gnmipb.GNMI_SubscribeClient for receive loop tests
+type fakeSubscribeClient struct {
+	grpc.ClientStream
+
+	mu        sync.Mutex
+	responses []*gnmipb.SubscribeResponse
+	err       error
+	sent      []*gnmipb.SubscribeRequest
+	ctx       context.Context
+}
+
+func (f *fakeSubscribeClient) Send(req *gnmipb.SubscribeRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, req)
+	return nil
+}
+
+func (f *fakeSubscribeClient) Recv() (*gnmipb.SubscribeResponse, error) {
+	f.mu.Lock()
+	if len(f.responses) > 0 {
+		resp := f.responses[0]
+		f.responses = f.responses[1:]
+		f.mu.Unlock()
+		return resp, nil
+	}
+	err := f.err
+	f.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Block until the stream context is canceled (like a real idle stream)
+	<-f.ctx.Done()
+	return nil, status.Error(codes.Canceled, f.ctx.Err().Error())
+}
+
+// newTestSubscribeStream creates a SubscribeStream backed by a fake stream
+func newTestSubscribeStream(ctx context.Context, mode SubscriptionListMode, fake *fakeSubscribeClient) *SubscribeStream {
+	streamCtx, cancel := context.WithCancel(ctx)
+	fake.ctx = streamCtx
+	s := &SubscribeStream{
+		client: &Client{
+			Target: "test-device",
+			logger: &NoOpLogger{},
+		},
+		mode:      mode,
+		stream:    fake,
+		cancel:    cancel,
+		responses: make(chan SubscribeRes, DefaultSubscribeBufferSize),
+		done:      make(chan struct{}),
+	}
+	go s.receive(streamCtx)
+	return s
+}
+
+func updateResponse(path string, value string) *gnmipb.SubscribeResponse {
+	p := &gnmipb.Path{}
+	for _, name := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		p.Elem = append(p.Elem, &gnmipb.PathElem{Name: name})
+	}
+	return &gnmipb.SubscribeResponse{
+		Response: &gnmipb.SubscribeResponse_Update{
+			Update: &gnmipb.Notification{
+				Timestamp: 42,
+				Update: []*gnmipb.Update{{
+					Path: p,
+					Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: value}},
+				}},
+			},
+		},
+	}
+}
+
+func syncResponse() *gnmipb.SubscribeResponse {
+	return &gnmipb.SubscribeResponse{
+		Response: &gnmipb.SubscribeResponse_SyncResponse{SyncResponse: true},
+	}
+}
+
+// TestSubscriptionHelpers tests the Sample, OnChange and TargetDefined helpers
+func TestSubscriptionHelpers(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Subscription
+		want Subscription
+	}{
+		{
+			name: "sample",
+			got:  Sample("/interfaces", 10*time.Second),
+			want: Subscription{Path: "/interfaces", Mode: SubscriptionModeSample, SampleInterval: 10 * time.Second},
+		},
+		{
+			name: "sample with options",
+			got:  Sample("/system", time.Second, SuppressRedundant(true), HeartbeatInterval(time.Minute)),
+			want: Subscription{
+				Path:              "/system",
+				Mode:              SubscriptionModeSample,
+				SampleInterval:    time.Second,
+				HeartbeatInterval: time.Minute,
+				SuppressRedundant: true,
+			},
+		},
+		{
+			name: "on change",
+			got:  OnChange("/interfaces/interface/state/oper-status"),
+			want: Subscription{Path: "/interfaces/interface/state/oper-status", Mode: SubscriptionModeOnChange},
+		},
+		{
+			name: "on change with heartbeat",
+			got:  OnChange("/system", HeartbeatInterval(5*time.Minute)),
+			want: Subscription{Path: "/system", Mode: SubscriptionModeOnChange, HeartbeatInterval: 5 * time.Minute},
+		},
+		{
+			name: "target defined",
+			got:  TargetDefined("/system/state"),
+			want: Subscription{Path: "/system/state", Mode: SubscriptionModeTargetDefined},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateSubscriptions tests input validation for subscriptions
+func TestValidateSubscriptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		subs    []Subscription
+		wantErr string
+	}{
+		{
+			name:    "empty subscriptions",
+			subs:    nil,
+			wantErr: "subscriptions cannot be empty",
+		},
+		{
+			name:    "empty path",
+			subs:    []Subscription{{Path: ""}},
+			wantErr: "subscription at index 0",
+		},
+		{
+			name:    "invalid path",
+			subs:    []Subscription{OnChange("/ok"), OnChange("interfaces")},
+			wantErr: "subscription at index 1",
+		},
+		{
+			name:    "invalid mode",
+			subs:    []Subscription{{Path: "/interfaces", Mode: "invalid"}},
+			wantErr: "subscription mode invalid",
+		},
+		{
+			name:    "negative sample interval",
+			subs:    []Subscription{Sample("/interfaces", -time.Second)},
+			wantErr: "sample interval must be non-negative",
+		},
+		{
+			name:    "negative heartbeat interval",
+			subs:    []Subscription{OnChange("/interfaces", HeartbeatInterval(-time.Second))},
+			wantErr: "heartbeat interval must be non-negative",
+		},
+		{
+			name: "valid subscriptions",
+			subs: []Subscription{
+				Sample("/interfaces", 10*time.Second),
+				OnChange("/system"),
+				TargetDefined("openconfig-system:/system"),
+				{Path: "/components"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSubscriptions(tt.subs)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateSubscriptions() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateSubscriptions() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestBuildSubscribeRequest tests conversion of subscriptions into a gNMI SubscribeRequest
+func TestBuildSubscribeRequest(t *testing.T) {
+	subs := []Subscription{
+		Sample("/interfaces/interface[name=Gi0]/state/counters", 10*time.Second, SuppressRedundant(true)),
+		OnChange("/system/state", HeartbeatInterval(time.Minute)),
+		{Path: "/components"},
+	}
+
+	t.Run("stream mode", func(t *testing.T) {
+		req, err := buildSubscribeRequest(subs, &Req{Encoding: EncodingJSONIETF, Mode: SubscribeModeStream, UpdatesOnly: true})
+		if err != nil {
+			t.Fatalf("buildSubscribeRequest() error = %v", err)
+		}
+		list := req.GetSubscribe()
+		if list.GetMode() != gnmipb.SubscriptionList_STREAM {
+			t.Errorf("mode = %v, want STREAM", list.GetMode())
+		}
+		if list.GetEncoding() != gnmipb.Encoding_JSON_IETF {
+			t.Errorf("encoding = %v, want JSON_IETF", list.GetEncoding())
+		}
+		if !list.GetUpdatesOnly() {
+			t.Errorf("updates_only = false, want true")
+		}
+		if len(list.GetSubscription()) != 3 {
+			t.Fatalf("subscriptions = %d, want 3", len(list.GetSubscription()))
+		}
+
+		sample := list.GetSubscription()[0]
+		if sample.GetMode() != gnmipb.SubscriptionMode_SAMPLE {
+			t.Errorf("sub[0] mode = %v, want SAMPLE", sample.GetMode())
+		}
+		if sample.GetSampleInterval() != uint64(10*time.Second) {
+			t.Errorf("sub[0] sample interval = %d, want %d", sample.GetSampleInterval(), uint64(10*time.Second))
+		}
+		if !sample.GetSuppressRedundant() {
+			t.Errorf("sub[0] suppress redundant = false, want true")
+		}
+		if got := sample.GetPath().GetElem()[1].GetKey()["name"]; got != "Gi0" {
+			t.Errorf("sub[0] key name = %q, want Gi0", got)
+		}
+
+		onChange := list.GetSubscription()[1]
+		if onChange.GetMode() != gnmipb.SubscriptionMode_ON_CHANGE {
+			t.Errorf("sub[1] mode = %v, want ON_CHANGE", onChange.GetMode())
+		}
+		if onChange.GetHeartbeatInterval() != uint64(time.Minute) {
+			t.Errorf("sub[1] heartbeat interval = %d, want %d", onChange.GetHeartbeatInterval(), uint64(time.Minute))
+		}
+
+		if list.GetSubscription()[2].GetMode() != gnmipb.SubscriptionMode_TARGET_DEFINED {
+			t.Errorf("sub[2] mode = %v, want TARGET_DEFINED", list.GetSubscription()[2].GetMode())
+		}
+	})
+
+	t.Run("once mode ignores subscription modes", func(t *testing.T) {
+		req, err := buildSubscribeRequest(subs, &Req{Encoding: EncodingJSON, Mode: SubscribeModeOnce})
+		if err != nil {
+			t.Fatalf("buildSubscribeRequest() error = %v", err)
+		}
+		list := req.GetSubscribe()
+		if list.GetMode() != gnmipb.SubscriptionList_ONCE {
+			t.Errorf("mode = %v, want ONCE", list.GetMode())
+		}
+		if list.GetEncoding() != gnmipb.Encoding_JSON {
+			t.Errorf("encoding = %v, want JSON", list.GetEncoding())
+		}
+		if list.GetSubscription()[0].GetSampleInterval() != 0 {
+			t.Errorf("sample interval set for ONCE subscription")
+		}
+	})
+}
+
+// TestSubscribeValidation tests input validation for Subscribe operations
+func TestSubscribeValidation(t *testing.T) {
+	client := &Client{
+		Target: "test-device",
+		logger: &NoOpLogger{},
+	}
+
+	tests := []struct {
+		name    string
+		subs    []Subscription
+		mods    []func(*Req)
+		wantErr string
+	}{
+		{
+			name:    "empty subscriptions",
+			subs:    []Subscription{},
+			wantErr: "subscriptions cannot be empty",
+		},
+		{
+			name:    "invalid encoding",
+			subs:    []Subscription{OnChange("/interfaces")},
+			mods:    []func(*Req){SubscribeEncoding("invalid")},
+			wantErr: "invalid encoding",
+		},
+		{
+			name:    "invalid mode",
+			subs:    []Subscription{OnChange("/interfaces")},
+			mods:    []func(*Req){SubscribeMode("invalid")},
+			wantErr: "invalid subscribe mode",
+		},
+		{
+			name:    "not connected",
+			subs:    []Subscription{OnChange("/interfaces")},
+			wantErr: "not connected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.Subscribe(context.Background(), tt.subs, tt.mods...)
+			if err == nil {
+				t.Fatalf("Subscribe() expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Subscribe() error = %v, want error containing %q", err, tt.wantErr)
+			}
+			if stream != nil {
+				t.Errorf("Subscribe() stream = %v, want nil", stream)
+			}
+		})
+	}
+}
+
+// TestSubscribeValidationCanceledContext tests Subscribe with a canceled context
+func TestSubscribeValidationCanceledContext(t *testing.T) {
+	client := &Client{
+		Target: "test-device",
+		logger: &NoOpLogger{},
+	}
+
+	_, err := client.Subscribe(canceledContext(), []Subscription{OnChange("/interfaces")})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Subscribe() error = %v, want context.Canceled", err)
+	}
+}
+
+// TestSubscribeStream_Stream tests delivery of notifications and sync responses
+func TestSubscribeStream_Stream(t *testing.T) {
+	fake := &fakeSubscribeClient{
+		responses: []*gnmipb.SubscribeResponse{
+			updateResponse("/system/state/hostname", "router1"),
+			syncResponse(),
+			updateResponse("/system/state/hostname", "router2"),
+		},
+	}
+	s := newTestSubscribeStream(context.Background(), SubscribeModeStream, fake)
+
+	var got []SubscribeRes
+	for i := 0; i < 3; i++ {
+		select {
+		case res := <-s.Responses():
+			got = append(got, res)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for response %d", i)
+		}
+	}
+
+	if got[0].Notification.Updates[0].Path != "/system/state/hostname" {
+		t.Errorf("update path = %q, want /system/state/hostname", got[0].Notification.Updates[0].Path)
+	}
+	if got[0].Notification.Updates[0].Value != "router1" {
+		t.Errorf("update value = %v, want router1", got[0].Notification.Updates[0].Value)
+	}
+	if got[0].Notification.Timestamp != 42 {
+		t.Errorf("notification timestamp = %d, want 42", got[0].Notification.Timestamp)
+	}
+	if !got[1].SyncResponse {
+		t.Errorf("response[1].SyncResponse = false, want true")
+	}
+	if got[2].Notification.Updates[0].Value != "router2" {
+		t.Errorf("update value = %v, want router2", got[2].Notification.Updates[0].Value)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, ok := <-s.Responses(); ok {
+		t.Errorf("Responses() channel not closed after Close()")
+	}
+	if err := s.Err(); err != nil {
+		t.Errorf("Err() after Close() = %v, want nil", err)
+	}
+
+	// Close is idempotent
+	if err := s.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+// TestSubscribeStream_Once tests that ONCE subscriptions complete after the sync response
+func TestSubscribeStream_Once(t *testing.T) {
+	fake := &fakeSubscribeClient{
+		responses: []*gnmipb.SubscribeResponse{
+			updateResponse("/system/state/hostname", "router1"),
+			syncResponse(),
+		},
+	}
+	s := newTestSubscribeStream(context.Background(), SubscribeModeOnce, fake)
+
+	count := 0
+	for range s.Responses() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("received %d responses, want 2", count)
+	}
+	if err := s.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+// TestSubscribeStream_Error tests that stream failures are reported via Err()
+func TestSubscribeStream_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{
+			name:    "clean end of stream",
+			err:     io.EOF,
+			wantErr: false,
+		},
+		{
+			name:    "permission denied",
+			err:     status.Error(codes.PermissionDenied, "denied"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSubscribeClient{err: tt.err}
+			s := newTestSubscribeStream(context.Background(), SubscribeModeStream, fake)
+
+			for range s.Responses() {
+			}
+
+			err := s.Err()
+			if tt.wantErr && err == nil {
+				t.Errorf("Err() = nil, want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Err() = %v, want nil", err)
+			}
+			if tt.wantErr && status.Code(errors.Unwrap(err)) != codes.PermissionDenied {
+				t.Errorf("Err() = %v, want wrapped PermissionDenied", err)
+			}
+		})
+	}
+}
+
+// TestSubscribeStream_ContextCanceled tests that canceling the caller context ends the stream
+func TestSubscribeStream_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newTestSubscribeStream(ctx, SubscribeModeStream, &fakeSubscribeClient{})
+
+	cancel()
+
+	select {
+	case _, ok := <-s.Responses():
+		if ok {
+			t.Fatalf("unexpected response after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("stream did not end after context cancel")
+	}
+
+	if !errors.Is(s.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", s.Err())
+	}
+}
+
+// TestSubscribeStream_Poll tests sending poll requests
+func TestSubscribeStream_Poll(t *testing.T) {
+	t.Run("poll mode", func(t *testing.T) {
+		fake := &fakeSubscribeClient{}
+		s := newTestSubscribeStream(context.Background(), SubscribeModePoll, fake)
+		defer s.Close() //nolint:errcheck // Close always returns nil
+
+		if err := s.Poll(context.Background()); err != nil {
+			t.Fatalf("Poll() error = %v", err)
+		}
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		if len(fake.sent) != 1 || fake.sent[0].GetPoll() == nil {
+			t.Errorf("Poll() sent %v, want one poll request", fake.sent)
+		}
+	})
+
+	t.Run("stream mode rejects poll", func(t *testing.T) {
+		s := newTestSubscribeStream(context.Background(), SubscribeModeStream, &fakeSubscribeClient{})
+		defer s.Close() //nolint:errcheck // Close always returns nil
+
+		err := s.Poll(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "poll requires subscribe mode") {
+			t.Errorf("Poll() error = %v, want mode error", err)
+		}
+	})
+
+	t.Run("closed stream rejects poll", func(t *testing.T) {
+		s := newTestSubscribeStream(context.Background(), SubscribeModePoll, &fakeSubscribeClient{})
+		_ = s.Close() //nolint:errcheck // Close always returns nil
+
+		err := s.Poll(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "subscription closed") {
+			t.Errorf("Poll() error = %v, want closed error", err)
+		}
+	})
+}
+
+// fakeGNMIClient is a minimal gnmipb.GNMIClient that hands out fake Subscribe streams in order
+type fakeGNMIClient struct {
+	gnmipb.GNMIClient
+
+	mu      sync.Mutex
+	streams []*fakeSubscribeClient
+	opened  int
+}
+
+func (f *fakeGNMIClient) Subscribe(ctx context.Context, _ ...grpc.CallOption) (gnmipb.GNMI_SubscribeClient, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.opened >= len(f.streams) {
+		return nil, status.Error(codes.Unimplemented, "no more streams")
+	}
+	stream := f.streams[f.opened]
+	stream.ctx = ctx
+	f.opened++
+	return stream, nil
+}
+
+// newTestResubscribeStream creates a SubscribeStream with resubscription enabled
+//
+// The first fake stream is used as the initial stream, the remaining ones are
+// returned by the client's gNMI handle on resubscription.
+func newTestResubscribeStream(ctx context.Context, backoff time.Duration, streams ...*fakeSubscribeClient) (*SubscribeStream, *fakeGNMIClient) {
+	gnmiClient := &fakeGNMIClient{streams: streams[1:]}
+	client := &Client{
+		Target:             "test-device",
+		target:             &target.Target{Client: gnmiClient},
+		connected:          true,
+		BackoffMinDelay:    backoff,
+		BackoffMaxDelay:    10 * backoff,
+		BackoffDelayFactor: 2,
+		logger:             &NoOpLogger{},
+	}
+	request := &gnmipb.SubscribeRequest{
+		Request: &gnmipb.SubscribeRequest_Subscribe{Subscribe: &gnmipb.SubscriptionList{}},
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	streams[0].ctx = streamCtx
+	s := &SubscribeStream{
+		client:      client,
+		mode:        SubscribeModeStream,
+		request:     request,
+		cancel:      cancel,
+		resubscribe: true,
+		stream:      streams[0],
+		gnmiClient:  gnmiClient,
+		responses:   make(chan SubscribeRes, DefaultSubscribeBufferSize),
+		done:        make(chan struct{}),
+	}
+	go s.receive(streamCtx)
+	return s, gnmiClient
+}
+
+// TestSubscribeStream_Resubscribe tests that transient stream errors re-establish the subscription
+func TestSubscribeStream_Resubscribe(t *testing.T) {
+	first := &fakeSubscribeClient{
+		responses: []*gnmipb.SubscribeResponse{
+			updateResponse("/system/state/hostname", "router1"),
+			syncResponse(),
+		},
+		err: status.Error(codes.Aborted, "target reloading"),
+	}
+	second := &fakeSubscribeClient{
+		responses: []*gnmipb.SubscribeResponse{
+			updateResponse("/system/state/hostname", "router2"),
+			syncResponse(),
+		},
+	}
+	s, gnmiClient := newTestResubscribeStream(context.Background(), time.Millisecond, first, second)
+	defer s.Close() //nolint:errcheck // Close always returns nil
+
+	var got []SubscribeRes
+	for i := 0; i < 5; i++ {
+		select {
+		case res := <-s.Responses():
+			got = append(got, res)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for response %d", i)
+		}
+	}
+
+	if !got[2].Reconnected {
+		t.Fatalf("response[2].Reconnected = false, want true")
+	}
+	if len(got[2].Errors) != 1 || got[2].Errors[0].Code != uint32(codes.Aborted) {
+		t.Errorf("response[2].Errors = %v, want Aborted", got[2].Errors)
+	}
+	if got[3].Notification.Updates[0].Value != "router2" {
+		t.Errorf("update value after resubscribe = %v, want router2", got[3].Notification.Updates[0].Value)
+	}
+	if !got[4].SyncResponse {
+		t.Errorf("response[4].SyncResponse = false, want true")
+	}
+
+	second.mu.Lock()
+	sent := second.sent
+	second.mu.Unlock()
+	if len(sent) != 1 || sent[0] != s.request {
+		t.Errorf("resubscribe sent %v, want original request", sent)
+	}
+
+	gnmiClient.mu.Lock()
+	opened := gnmiClient.opened
+	gnmiClient.mu.Unlock()
+	if opened != 1 {
+		t.Errorf("streams opened = %d, want 1", opened)
+	}
+	if err := s.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+// TestSubscribeStream_ResubscribePermanentError tests that resubscription stops on permanent errors
+func TestSubscribeStream_ResubscribePermanentError(t *testing.T) {
+	tests := []struct {
+		name     string
+		first    *fakeSubscribeClient
+		wantCode codes.Code
+	}{
+		{
+			name:     "permanent stream error",
+			first:    &fakeSubscribeClient{err: status.Error(codes.PermissionDenied, "denied")},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "permanent resubscribe error",
+			first:    &fakeSubscribeClient{err: status.Error(codes.ResourceExhausted, "too many subscriptions")},
+			wantCode: codes.Unimplemented,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// No further streams: resubscription fails with Unimplemented
+			s, _ := newTestResubscribeStream(context.Background(), time.Millisecond, tt.first)
+
+			for res := range s.Responses() {
+				if res.Reconnected {
+					t.Errorf("unexpected reconnect event")
+				}
+			}
+
+			if err := s.Err(); status.Code(err) != tt.wantCode {
+				t.Errorf("Err() = %v, want wrapped %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+// TestSubscribeStream_ResubscribeDisabled tests that transient errors end the stream when resubscription is disabled
+func TestSubscribeStream_ResubscribeDisabled(t *testing.T) {
+	fake := &fakeSubscribeClient{err: status.Error(codes.Unavailable, "connection lost")}
+	s := newTestSubscribeStream(context.Background(), SubscribeModeStream, fake)
+
+	for range s.Responses() {
+	}
+
+	if status.Code(errors.Unwrap(s.Err())) != codes.Unavailable {
+		t.Errorf("Err() = %v, want wrapped Unavailable", s.Err())
+	}
+}
+
+// TestSubscribeStream_ResubscribeCanceled tests that Close() interrupts resubscription backoff
+func TestSubscribeStream_ResubscribeCanceled(t *testing.T) {
+	first := &fakeSubscribeClient{err: status.Error(codes.Aborted, "aborted")}
+	s, _ := newTestResubscribeStream(context.Background(), time.Minute, first)
+
+	done := make(chan struct{})
+	go func() {
+		_ = s.Close() //nolint:errcheck // Close always returns nil
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Close() did not interrupt resubscription")
+	}
+	if err := s.Err(); err != nil {
+		t.Errorf("Err() after Close() = %v, want nil", err)
+	}
+}
+
+// TestResubscribeModifier tests the Resubscribe request modifier
+func TestResubscribeModifier(t *testing.T) {
+	req := &Req{Resubscribe: true}
+	Resubscribe(false)(req)
+	if req.Resubscribe {
+		t.Errorf("Resubscribe(false) left Resubscribe enabled")
+	}
+	Resubscribe(true)(req)
+	if !req.Resubscribe {
+		t.Errorf("Resubscribe(true) left Resubscribe disabled")
+	}
+}
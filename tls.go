@@ -0,0 +1,224 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultTLSMinVersion is the minimum TLS version of connections using
+// in-memory TLS material or TLSConfig (unless set by TLSMinVersion)
+const DefaultTLSMinVersion = tls.VersionTLS12
+
+// TLSCertPEM sets the PEM-encoded client certificate and private key
+//
+// Use it instead of TLSCert and TLSKey for certificates fetched from a secret
+// store or generated at runtime. The key pair is validated by NewClient.
+//
+// Example:
+//
+//	secret, _ := vault.KVv2("secret").Get(ctx, "gnmi/client-cert")
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.TLSCertPEM([]byte(secret.Data["cert"].(string)), []byte(secret.Data["key"].(string))),
+//	    gnmi.TLSCAPEM(caPEM),
+//	)
+func TLSCertPEM(certPEM, keyPEM []byte) func(*Client) {
+	return func(c *Client) {
+		c.tlsCertPEM = certPEM
+		c.tlsKeyPEM = keyPEM
+	}
+}
+
+// TLSCAPEM sets the PEM-encoded CA certificates for server verification
+//
+// Use it instead of TLSCA for CA bundles held in memory. The bundle must
+// contain at least one certificate.
+func TLSCAPEM(caPEM []byte) func(*Client) {
+	return func(c *Client) {
+		c.tlsCAPEM = caPEM
+	}
+}
+
+// TLSConfig sets the base TLS configuration of the client
+//
+// The configuration is cloned when connecting. Other TLS options are applied
+// on top of it: TLSCA/TLSCAPEM replace RootCAs, TLSCert/TLSCertPEM replace
+// Certificates, TLSServerName replaces ServerName, and TLSMinVersion replaces
+// MinVersion. VerifyCertificate(false) sets InsecureSkipVerify.
+//
+// Example:
+//
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.TLSConfig(&tls.Config{
+//	        RootCAs:    pool,
+//	        MinVersion: tls.VersionTLS13,
+//	    }),
+//	)
+func TLSConfig(cfg *tls.Config) func(*Client) {
+	return func(c *Client) {
+		c.tlsConfig = cfg
+	}
+}
+
+// TLSGetClientCertificate sets a callback returning the client certificate
+// for each TLS handshake
+//
+// Use it to reload rotated certificates without recreating the client; new
+// connections (including reconnects) use the certificate returned at
+// handshake time. Cannot be combined with TLSCert or TLSCertPEM.
+//
+// Example:
+//
+//	var current atomic.Pointer[tls.Certificate]
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.TLSGetClientCertificate(func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+//	        return current.Load(), nil
+//	    }),
+//	)
+func TLSGetClientCertificate(fn func(*tls.CertificateRequestInfo) (*tls.Certificate, error)) func(*Client) {
+	return func(c *Client) {
+		c.tlsGetClientCertificate = fn
+	}
+}
+
+// TLSServerName overrides the server name used to verify the target
+// certificate (default: the host of the target address)
+//
+// Use it when connecting by IP address to a device whose certificate only
+// contains its DNS name.
+func TLSServerName(name string) func(*Client) {
+	return func(c *Client) {
+		c.tlsServerName = name
+	}
+}
+
+// TLSMinVersion sets the minimum TLS version, e.g. tls.VersionTLS13 (default: TLS 1.2)
+//
+// Versions below TLS 1.2 are accepted for legacy devices but logged as a warning.
+func TLSMinVersion(version uint16) func(*Client) {
+	return func(c *Client) {
+		c.tlsMinVersion = version
+	}
+}
+
+// customTLS reports whether TLS options beyond certificate file paths are
+// configured, so the client builds the TLS configuration itself
+func (c *Client) customTLS() bool {
+	return c.tlsCertPEM != nil || c.tlsKeyPEM != nil || c.tlsCAPEM != nil ||
+		c.tlsConfig != nil || c.tlsGetClientCertificate != nil ||
+		c.tlsServerName != "" || c.tlsMinVersion != 0
+}
+
+// validateTLS validates the TLS options
+//
+// Rejects TLS options with TLS disabled, conflicting certificate sources,
+// unknown TLS versions, and invalid PEM material.
+//
+// Returns an error if validation fails.
+func (c *Client) validateTLS() error {
+	if !c.customTLS() {
+		return nil
+	}
+	if !c.UseTLS {
+		return fmt.Errorf("TLS options configured, but TLS is disabled")
+	}
+
+	if c.tlsCertPEM != nil && (c.tlsCert != "" || c.tlsKey != "") {
+		return fmt.Errorf("TLS certificate configured both as file and PEM")
+	}
+	if c.tlsCAPEM != nil && c.tlsCA != "" {
+		return fmt.Errorf("TLS CA configured both as file and PEM")
+	}
+	if c.tlsGetClientCertificate != nil && (c.tlsCertPEM != nil || c.tlsCert != "") {
+		return fmt.Errorf("TLS client certificate callback cannot be combined with a certificate")
+	}
+	if (c.tlsCertPEM == nil) != (c.tlsKeyPEM == nil) {
+		return fmt.Errorf("TLS certificate PEM requires both certificate and key")
+	}
+
+	switch c.tlsMinVersion {
+	case 0, tls.VersionTLS12, tls.VersionTLS13:
+	case tls.VersionTLS10, tls.VersionTLS11:
+		c.logger.Warn(context.Background(), "TLS minimum version below 1.2 configured",
+			"target", c.Target,
+			"version", tls.VersionName(c.tlsMinVersion),
+			"security_risk", "deprecated TLS versions have known weaknesses")
+	default:
+		return fmt.Errorf("invalid TLS minimum version: 0x%04x", c.tlsMinVersion)
+	}
+
+	_, err := c.buildTLSConfig()
+	return err
+}
+
+// buildTLSConfig builds the TLS configuration from the TLS options
+//
+// Certificate files (TLSCert, TLSKey, TLSCA) are read on every call, so
+// reconnects pick up replaced files. Errors only contain file names to
+// prevent path disclosure.
+//
+// Returns nil if only certificate file paths are configured; gnmic then
+// builds the configuration from the file paths.
+func (c *Client) buildTLSConfig() (*tls.Config, error) {
+	if !c.customTLS() {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{MinVersion: DefaultTLSMinVersion}
+	if c.tlsConfig != nil {
+		cfg = c.tlsConfig.Clone()
+	}
+	if c.tlsMinVersion != 0 {
+		cfg.MinVersion = c.tlsMinVersion
+	}
+	if c.tlsServerName != "" {
+		cfg.ServerName = c.tlsServerName
+	}
+	if c.InsecureSkipVerify {
+		cfg.InsecureSkipVerify = true
+	}
+
+	// Server verification
+	caPEM := c.tlsCAPEM
+	if c.tlsCA != "" {
+		data, err := os.ReadFile(c.tlsCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS CA file: %s", filepath.Base(c.tlsCA))
+		}
+		caPEM = data
+	}
+	if caPEM != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("invalid TLS CA: no PEM certificates found")
+		}
+		cfg.RootCAs = pool
+	}
+
+	// Client certificate
+	switch {
+	case c.tlsCertPEM != nil:
+		cert, err := tls.X509KeyPair(c.tlsCertPEM, c.tlsKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS certificate PEM: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case c.tlsCert != "" && c.tlsKey != "":
+		cert, err := tls.LoadX509KeyPair(c.tlsCert, c.tlsKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %s", filepath.Base(c.tlsCert))
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case c.tlsGetClientCertificate != nil:
+		cfg.Certificates = nil
+		cfg.GetClientCertificate = c.tlsGetClientCertificate
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+)
+
+// testPKI holds an in-memory CA with a server and a client certificate
+type testPKI struct {
+	caPEM         []byte
+	serverCert    tls.Certificate
+	clientCertPEM []byte
+	clientKeyPEM  []byte
+	clientCert    tls.Certificate
+	pool          *x509.CertPool
+}
+
+// newTestPKI generates a CA, a server certificate for "gnmi.test", and a client certificate
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	issue := func(tmpl *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) ([]byte, []byte, *x509.Certificate, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateKey() error = %v", err)
+		}
+		if parent == nil {
+			parent, parentKey = tmpl, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+		if err != nil {
+			t.Fatalf("CreateCertificate() error = %v", err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("ParseCertificate() error = %v", err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatalf("MarshalECPrivateKey() error = %v", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), cert, key
+	}
+	keyPair := func(certPEM, keyPEM []byte) tls.Certificate {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			t.Fatalf("X509KeyPair() error = %v", err)
+		}
+		return cert
+	}
+
+	now := time.Now()
+	caPEM, _, ca, caKey := issue(&x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	serverPEM, serverKeyPEM, _, _ := issue(&x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "gnmi.test"},
+		DNSNames:     []string{"gnmi.test"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+	clientPEM, clientKeyPEM, _, _ := issue(&x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "admin"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return &testPKI{
+		caPEM:         caPEM,
+		serverCert:    keyPair(serverPEM, serverKeyPEM),
+		clientCertPEM: clientPEM,
+		clientKeyPEM:  clientKeyPEM,
+		clientCert:    keyPair(clientPEM, clientKeyPEM),
+		pool:          pool,
+	}
+}
+
+// TestValidateTLS tests validation of the TLS options
+func TestValidateTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	getCert := func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return &pki.clientCert, nil }
+
+	tests := []struct {
+		name    string
+		opts    []func(*Client)
+		wantErr string
+	}{
+		{"PEM material", []func(*Client){TLSCertPEM(pki.clientCertPEM, pki.clientKeyPEM), TLSCAPEM(pki.caPEM)}, ""},
+		{"server name and version", []func(*Client){TLSServerName("gnmi.test"), TLSMinVersion(tls.VersionTLS13)}, ""},
+		{"legacy version", []func(*Client){TLSMinVersion(tls.VersionTLS11)}, ""},
+		{"TLS disabled", []func(*Client){TLS(false), TLSCAPEM(pki.caPEM)}, "TLS is disabled"},
+		{"invalid CA", []func(*Client){TLSCAPEM([]byte("not a certificate"))}, "invalid TLS CA"},
+		{"invalid key pair", []func(*Client){TLSCertPEM(pki.clientCertPEM, pki.caPEM)}, "invalid TLS certificate PEM"},
+		{"missing key", []func(*Client){TLSCertPEM(pki.clientCertPEM, nil)}, "requires both certificate and key"},
+		{"invalid version", []func(*Client){TLSMinVersion(0x0999)}, "invalid TLS minimum version"},
+		{"CA file and PEM", []func(*Client){TLSCA("tls_test.go"), TLSCAPEM(pki.caPEM)}, "both as file and PEM"},
+		{"callback and certificate", []func(*Client){TLSGetClientCertificate(getCert), TLSCertPEM(pki.clientCertPEM, pki.clientKeyPEM)}, "cannot be combined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient("192.168.1.1", tt.opts...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("NewClient() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewClient() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestBuildTLSConfig tests the TLS configuration built from the options
+func TestBuildTLSConfig(t *testing.T) {
+	pki := newTestPKI(t)
+
+	t.Run("file paths only", func(t *testing.T) {
+		c := &Client{tlsCA: "ca.pem"}
+		if cfg, err := c.buildTLSConfig(); cfg != nil || err != nil {
+			t.Errorf("buildTLSConfig() = %v, %v, want nil (built by gnmic)", cfg, err)
+		}
+	})
+
+	t.Run("defaults and overrides", func(t *testing.T) {
+		c := &Client{tlsCAPEM: pki.caPEM, tlsServerName: "gnmi.test"}
+		cfg, err := c.buildTLSConfig()
+		if err != nil {
+			t.Fatalf("buildTLSConfig() error = %v", err)
+		}
+		if cfg.MinVersion != tls.VersionTLS12 || cfg.ServerName != "gnmi.test" || cfg.RootCAs == nil || cfg.InsecureSkipVerify {
+			t.Errorf("buildTLSConfig() = %+v, want TLS 1.2, server name, and root CAs", cfg)
+		}
+	})
+
+	t.Run("base config", func(t *testing.T) {
+		base := &tls.Config{MinVersion: tls.VersionTLS13, ServerName: "base"}
+		c := &Client{tlsConfig: base, tlsCertPEM: pki.clientCertPEM, tlsKeyPEM: pki.clientKeyPEM, InsecureSkipVerify: true}
+		cfg, err := c.buildTLSConfig()
+		if err != nil {
+			t.Fatalf("buildTLSConfig() error = %v", err)
+		}
+		if cfg == base || cfg.MinVersion != tls.VersionTLS13 || cfg.ServerName != "base" || len(cfg.Certificates) != 1 || !cfg.InsecureSkipVerify {
+			t.Errorf("buildTLSConfig() = %+v, want clone of base with certificate", cfg)
+		}
+		if base.InsecureSkipVerify || len(base.Certificates) != 0 {
+			t.Errorf("base config modified")
+		}
+	})
+
+	t.Run("certificate files", func(t *testing.T) {
+		dir := t.TempDir()
+		files := map[string][]byte{"ca.pem": pki.caPEM, "client.pem": pki.clientCertPEM, "client.key": pki.clientKeyPEM}
+		for name, data := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		c := &Client{
+			tlsCA:         filepath.Join(dir, "ca.pem"),
+			tlsCert:       filepath.Join(dir, "client.pem"),
+			tlsKey:        filepath.Join(dir, "client.key"),
+			tlsMinVersion: tls.VersionTLS13,
+		}
+		cfg, err := c.buildTLSConfig()
+		if err != nil {
+			t.Fatalf("buildTLSConfig() error = %v", err)
+		}
+		if cfg.RootCAs == nil || len(cfg.Certificates) != 1 {
+			t.Errorf("buildTLSConfig() = %+v, want root CAs and certificate from files", cfg)
+		}
+	})
+}
+
+// TestIntegration_TLS tests TLS connections with in-memory material against the fake server
+func TestIntegration_TLS(t *testing.T) {
+	pki := newTestPKI(t)
+	srv, err := gnmitest.NewServer(gnmitest.TLSConfig(&tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		ClientCAs:    pki.pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}))
+	if err != nil {
+		t.Fatalf("gnmitest.NewServer() error = %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+	newClient := func(t *testing.T, opts ...func(*Client)) *Client {
+		t.Helper()
+		client, err := NewClient(srv.Addr(), append([]func(*Client){MaxRetries(0), ConnectTimeout(2 * time.Second)}, opts...)...)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		t.Cleanup(func() { _ = client.Close() })
+		return client
+	}
+
+	t.Run("PEM material", func(t *testing.T) {
+		client := newClient(t,
+			TLSCAPEM(pki.caPEM),
+			TLSCertPEM(pki.clientCertPEM, pki.clientKeyPEM),
+			TLSServerName("gnmi.test"),
+		)
+		if err := client.Ping(ctx); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+	})
+
+	t.Run("server name mismatch", func(t *testing.T) {
+		client := newClient(t, TLSCAPEM(pki.caPEM), TLSCertPEM(pki.clientCertPEM, pki.clientKeyPEM))
+		if err := client.Ping(ctx); err == nil {
+			t.Errorf("Ping() expected certificate verification error")
+		}
+	})
+
+	t.Run("client certificate callback", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newClient(t,
+			TLSConfig(&tls.Config{RootCAs: pki.pool, ServerName: "gnmi.test"}),
+			TLSGetClientCertificate(func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				calls.Add(1)
+				return &pki.clientCert, nil
+			}),
+		)
+		if err := client.Ping(ctx); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+
+		// Reconnects request the certificate again
+		if err := client.Disconnect(); err != nil {
+			t.Fatalf("Disconnect() error = %v", err)
+		}
+		if err := client.Ping(ctx); err != nil {
+			t.Fatalf("Ping() after reconnect error = %v", err)
+		}
+		if n := calls.Load(); n != 2 {
+			t.Errorf("GetClientCertificate calls = %d, want 2", n)
+		}
+	})
+}
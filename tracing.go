@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// TracerName is the instrumentation scope name of the spans created by the client
+const TracerName = "github.com/netascode/go-gnmi"
+
+// Span attribute keys
+const (
+	attrTarget     = attribute.Key("gnmi.target")
+	attrPaths      = attribute.Key("gnmi.paths")
+	attrOperations = attribute.Key("gnmi.operations")
+	attrEncoding   = attribute.Key("gnmi.encoding")
+	attrMode       = attribute.Key("gnmi.mode")
+	attrAttempt    = attribute.Key("gnmi.attempt")
+	attrBackoff    = attribute.Key("gnmi.backoff")
+	attrGRPCCode   = attribute.Key("rpc.grpc.status_code")
+)
+
+// Tracing configures OpenTelemetry tracing of a client
+//
+// With tracing enabled, Get, Set, Capabilities, and Subscribe each create a
+// client span named "gnmi.<Operation>" with the attributes gnmi.target,
+// gnmi.paths (number of paths or subscriptions), gnmi.operations (number of
+// Set operations), gnmi.encoding, gnmi.mode (Subscribe), and
+// rpc.grpc.status_code (see OperationCode). Retries and reconnects are
+// recorded as "retry" and "reconnect" span events, and the trace context is
+// propagated to the target in gRPC metadata. Resubscriptions of a dropped
+// stream create a "gnmi.Resubscribe" span in the trace of the Subscribe call.
+//
+// A Tracing is created per client by the WithTracing option.
+type Tracing struct {
+	// TracerProvider creates the tracer (default: otel.GetTracerProvider())
+	TracerProvider trace.TracerProvider
+
+	// Propagator injects the trace context into gRPC metadata
+	// (default: otel.GetTextMapPropagator())
+	Propagator propagation.TextMapPropagator
+
+	tracer trace.Tracer
+}
+
+// TracerProvider sets the tracer provider used to create spans (default: the global provider)
+func TracerProvider(tp trace.TracerProvider) func(*Tracing) {
+	return func(t *Tracing) {
+		t.TracerProvider = tp
+	}
+}
+
+// Propagator sets the propagator injecting the trace context into gRPC
+// metadata (default: the global propagator)
+//
+// Use a no-op propagator (propagation.NewCompositeTextMapPropagator()) to
+// keep trace context from being sent to the target.
+func Propagator(p propagation.TextMapPropagator) func(*Tracing) {
+	return func(t *Tracing) {
+		t.Propagator = p
+	}
+}
+
+// WithTracing enables OpenTelemetry tracing for the client
+//
+// See Tracing. Without options, the global tracer provider and propagator
+// are used, so configure them with otel.SetTracerProvider and
+// otel.SetTextMapPropagator before creating the client.
+//
+// Example:
+//
+//	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+//	client, err := gnmi.NewClient("192.168.1.1:57400",
+//	    gnmi.WithTracing(gnmi.TracerProvider(tp)),
+//	)
+//
+//	// Spans of the client become children of the caller's span
+//	ctx, span := tracer.Start(ctx, "provision-device")
+//	defer span.End()
+//	res, err := client.Set(ctx, ops)
+func WithTracing(opts ...func(*Tracing)) func(*Client) {
+	return func(c *Client) {
+		t := &Tracing{}
+		for _, opt := range opts {
+			opt(t)
+		}
+		if t.TracerProvider == nil {
+			t.TracerProvider = otel.GetTracerProvider()
+		}
+		if t.Propagator == nil {
+			t.Propagator = otel.GetTextMapPropagator()
+		}
+		t.tracer = t.TracerProvider.Tracer(TracerName)
+		c.tracing = t
+	}
+}
+
+// startSpan starts the client span of an operation and injects its trace
+// context into the outgoing gRPC metadata of ctx
+//
+// Returns ctx unchanged and a nil span if tracing is disabled.
+func (c *Client) startSpan(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if c.tracing == nil {
+		return ctx, nil
+	}
+
+	ctx, span := c.tracing.tracer.Start(ctx, "gnmi."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", "gnmi.gNMI"),
+			attribute.String("rpc.method", operation),
+			attrTarget.String(c.Target),
+		),
+		trace.WithAttributes(attrs...))
+
+	// Replace trace context injected by an enclosing operation (e.g., the
+	// Subscribe call of a resubscription)
+	carrier := propagation.MapCarrier{}
+	c.tracing.Propagator.Inject(ctx, carrier)
+	if len(carrier) > 0 {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		for key, value := range carrier {
+			md.Set(key, value)
+		}
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	return ctx, span
+}
+
+// endSpan records the result of an operation and ends its span
+//
+// A nil span (tracing disabled) is ignored.
+func endSpan(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+
+	code := OperationCode(err)
+	span.SetAttributes(attrGRPCCode.Int(int(code)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+// setSpanAttributes adds attributes to the operation span in ctx
+//
+// Does nothing if tracing is disabled, so the span of the caller is never modified.
+func (c *Client) setSpanAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	if c.tracing == nil {
+		return
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// addSpanEvent adds an event to the operation span in ctx
+//
+// Does nothing if tracing is disabled, so the span of the caller is never modified.
+func (c *Client) addSpanEvent(ctx context.Context, name string, attrs ...attribute.KeyValue) {
+	if c.tracing == nil {
+		return
+	}
+	trace.SpanFromContext(ctx).AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// retryEventAttributes returns the attributes of a retry span event
+func retryEventAttributes(attempt int, delay time.Duration, err error) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attrAttempt.Int(attempt),
+		attrBackoff.String(delay.String()),
+		attrGRPCCode.Int(int(attemptCode(err))),
+		attribute.String("exception.message", err.Error()),
+	}
+}
@@ -0,0 +1,358 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/netascode/go-gnmi/gnmitest"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// spanRecorder is a tracer provider recording spans in memory
+type spanRecorder struct {
+	embedded.TracerProvider
+
+	ids   atomic.Uint64
+	mu    sync.Mutex
+	ended []*recordedSpan
+}
+
+// recordingTracer is the tracer of a spanRecorder
+type recordingTracer struct {
+	embedded.Tracer
+
+	rec *spanRecorder
+}
+
+// recordedSpan is a span created by spanRecorder
+type recordedSpan struct {
+	embedded.Span
+
+	rec    *spanRecorder
+	sc     trace.SpanContext
+	parent trace.SpanContext
+	kind   trace.SpanKind
+
+	mu     sync.Mutex
+	name   string
+	attrs  []attribute.KeyValue
+	events []string
+	status otelcodes.Code
+}
+
+// newTracerProvider returns a tracer provider recording spans in memory
+func newTracerProvider(t *testing.T) (trace.TracerProvider, *spanRecorder) {
+	t.Helper()
+	rec := &spanRecorder{}
+	return rec, rec
+}
+
+// Tracer implements trace.TracerProvider
+func (r *spanRecorder) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &recordingTracer{rec: r}
+}
+
+// Start implements trace.Tracer
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	r := t.rec
+	cfg := trace.NewSpanStartConfig(opts...)
+	parent := trace.SpanContextFromContext(ctx)
+
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], r.ids.Add(1))
+	traceID := parent.TraceID()
+	if !parent.IsValid() {
+		binary.BigEndian.PutUint64(traceID[8:], r.ids.Add(1))
+	}
+
+	span := &recordedSpan{
+		rec:    r,
+		name:   name,
+		parent: parent,
+		kind:   cfg.SpanKind(),
+		attrs:  cfg.Attributes(),
+		sc: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}),
+	}
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+// Ended returns the ended spans
+func (r *spanRecorder) Ended() []*recordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*recordedSpan(nil), r.ended...)
+}
+
+// End implements trace.Span
+func (s *recordedSpan) End(...trace.SpanEndOption) {
+	s.rec.mu.Lock()
+	defer s.rec.mu.Unlock()
+	s.rec.ended = append(s.rec.ended, s)
+}
+
+// AddEvent implements trace.Span
+func (s *recordedSpan) AddEvent(name string, _ ...trace.EventOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, name)
+}
+
+// AddLink implements trace.Span
+func (s *recordedSpan) AddLink(trace.Link) {}
+
+// IsRecording implements trace.Span
+func (s *recordedSpan) IsRecording() bool { return true }
+
+// RecordError implements trace.Span
+func (s *recordedSpan) RecordError(error, ...trace.EventOption) {
+	s.AddEvent("exception")
+}
+
+// SpanContext implements trace.Span
+func (s *recordedSpan) SpanContext() trace.SpanContext { return s.sc }
+
+// SetStatus implements trace.Span
+func (s *recordedSpan) SetStatus(code otelcodes.Code, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = code
+}
+
+// SetName implements trace.Span
+func (s *recordedSpan) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.name = name
+}
+
+// SetAttributes implements trace.Span
+func (s *recordedSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, kv...)
+}
+
+// TracerProvider implements trace.Span
+func (s *recordedSpan) TracerProvider() trace.TracerProvider { return s.rec }
+
+// Name returns the span name
+func (s *recordedSpan) Name() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.name
+}
+
+// Parent returns the span context of the parent span
+func (s *recordedSpan) Parent() trace.SpanContext { return s.parent }
+
+// SpanKind returns the span kind
+func (s *recordedSpan) SpanKind() trace.SpanKind { return s.kind }
+
+// Attributes returns the span attributes
+func (s *recordedSpan) Attributes() []attribute.KeyValue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]attribute.KeyValue(nil), s.attrs...)
+}
+
+// Events returns the names of the span events
+func (s *recordedSpan) Events() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.events...)
+}
+
+// Status returns the span status code
+func (s *recordedSpan) Status() otelcodes.Code {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// findSpan returns the ended span with the given name
+func findSpan(t *testing.T, rec *spanRecorder, name string) *recordedSpan {
+	t.Helper()
+	for _, span := range rec.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("span %q not recorded", name)
+	return nil
+}
+
+// spanAttribute returns the value of a span attribute
+func spanAttribute(span *recordedSpan, key attribute.Key) attribute.Value {
+	var value attribute.Value
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			value = kv.Value
+		}
+	}
+	return value
+}
+
+// hasEvent reports whether the span has an event with the given name
+func hasEvent(span *recordedSpan, name string) bool {
+	for _, event := range span.Events() {
+		if event == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TestStartSpan_Propagation tests that the trace context is injected into gRPC metadata
+func TestStartSpan_Propagation(t *testing.T) {
+	tp, _ := newTracerProvider(t)
+	c := &Client{Target: "device"}
+	WithTracing(TracerProvider(tp), Propagator(propagation.TraceContext{}))(c)
+
+	// Trace context of an enclosing operation is replaced, other metadata kept
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", "stale", "x-custom", "1")
+	ctx, span := c.startSpan(ctx, "Get")
+	defer span.End()
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	traceparent := md.Get("traceparent")
+	if len(traceparent) != 1 || traceparent[0] == "stale" {
+		t.Fatalf("traceparent = %v, want one injected value", traceparent)
+	}
+	want := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(trace.ContextWithSpan(context.Background(), span), want)
+	if traceparent[0] != want.Get("traceparent") {
+		t.Errorf("traceparent = %q, want %q", traceparent[0], want.Get("traceparent"))
+	}
+	if v := md.Get("x-custom"); len(v) != 1 {
+		t.Errorf("x-custom = %v, want preserved", v)
+	}
+
+	// Without tracing, ctx is returned unchanged
+	plain := context.Background()
+	if ctx, span := (&Client{}).startSpan(plain, "Get"); ctx != plain || span != nil {
+		t.Errorf("startSpan() without tracing = %v, %v, want unchanged ctx and nil span", ctx, span)
+	}
+}
+
+// TestIntegration_Tracing tests operation spans against the fake server
+func TestIntegration_Tracing(t *testing.T) {
+	tp, rec := newTracerProvider(t)
+	client, srv := newIntegrationClient(t, MaxRetries(2), WithTracing(TracerProvider(tp)))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "workflow")
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	t.Run("get with retry", func(t *testing.T) {
+		srv.FailNext(gnmitest.RPCGet, codes.Unavailable, 1)
+		if _, err := client.Get(ctx, []string{"/system/config", "/system/config/hostname"}); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+
+		span := findSpan(t, rec, "gnmi.Get")
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("parent = %v, want workflow span", span.Parent().SpanID())
+		}
+		if span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("kind = %v, want client", span.SpanKind())
+		}
+		if v := spanAttribute(span, attrPaths); v.AsInt64() != 2 {
+			t.Errorf("gnmi.paths = %v, want 2", v.Emit())
+		}
+		if v := spanAttribute(span, attrEncoding); v.AsString() != EncodingJSONIETF {
+			t.Errorf("gnmi.encoding = %q, want %q", v.AsString(), EncodingJSONIETF)
+		}
+		if v := spanAttribute(span, attrTarget); v.AsString() != client.Target {
+			t.Errorf("gnmi.target = %q, want %q", v.AsString(), client.Target)
+		}
+		if v := spanAttribute(span, attrGRPCCode); v.Type() != attribute.INT64 || v.AsInt64() != int64(codes.OK) {
+			t.Errorf("rpc.grpc.status_code = %v, want 0", v.Emit())
+		}
+		if !hasEvent(span, "retry") {
+			t.Errorf("events = %v, want retry", span.Events())
+		}
+	})
+
+	t.Run("failed set", func(t *testing.T) {
+		srv.FailNext(gnmitest.RPCSet, codes.InvalidArgument, 1)
+		if _, err := client.Set(ctx, []SetOperation{Delete("/system/config/hostname")}); err == nil {
+			t.Fatalf("Set() expected error")
+		}
+
+		span := findSpan(t, rec, "gnmi.Set")
+		if span.Status() != otelcodes.Error {
+			t.Errorf("status = %v, want error", span.Status())
+		}
+		if v := spanAttribute(span, attrGRPCCode); v.AsInt64() != int64(codes.InvalidArgument) {
+			t.Errorf("rpc.grpc.status_code = %v, want %d", v.Emit(), codes.InvalidArgument)
+		}
+		if v := spanAttribute(span, attrOperations); v.AsInt64() != 1 {
+			t.Errorf("gnmi.operations = %v, want 1", v.Emit())
+		}
+	})
+
+	t.Run("resubscribe", func(t *testing.T) {
+		subCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := client.Subscribe(subCtx, []Subscription{OnChange("/system/config/hostname")})
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		defer stream.Close()
+		waitForSync(t, stream)
+
+		srv.DropConnections()
+		if res := nextResponse(t, stream); !res.Reconnected {
+			t.Fatalf("response = %+v, want Reconnected", res)
+		}
+
+		subscribe := findSpan(t, rec, "gnmi.Subscribe")
+		if v := spanAttribute(subscribe, attrMode); v.AsString() != string(SubscribeModeStream) {
+			t.Errorf("gnmi.mode = %q, want %q", v.AsString(), SubscribeModeStream)
+		}
+		resubscribe := findSpan(t, rec, "gnmi.Resubscribe")
+		if resubscribe.Parent().SpanID() != subscribe.SpanContext().SpanID() {
+			t.Errorf("resubscribe parent = %v, want Subscribe span", resubscribe.Parent().SpanID())
+		}
+		if !hasEvent(resubscribe, "retry") || !hasEvent(resubscribe, "reconnect") {
+			t.Errorf("events = %v, want retry and reconnect", resubscribe.Events())
+		}
+	})
+
+	parent.End()
+}
+
+// TestTracing_Disabled tests that the caller's span is not modified without tracing
+func TestTracing_Disabled(t *testing.T) {
+	tp, rec := newTracerProvider(t)
+	client, srv := newIntegrationClient(t, MaxRetries(1))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "workflow")
+	srv.FailNext(gnmitest.RPCGet, codes.Unavailable, 1)
+	if _, err := client.Get(ctx, []string{"/system/config"}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	parent.End()
+
+	span := findSpan(t, rec, "workflow")
+	if len(span.Attributes()) != 0 || len(span.Events()) != 0 || len(rec.Ended()) != 1 {
+		t.Errorf("caller span modified: attributes %v, events %v", span.Attributes(), span.Events())
+	}
+}
@@ -0,0 +1,362 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+)
+
+// Tree merges the updates of all notifications into a single JSON tree rooted
+// at the requested path.
+//
+// Each update is placed at its full path (prefix + path) and JSON values are
+// merged recursively, so responses split into many notifications (one per
+// list entry, or one per leaf) yield the same document as a single update.
+// List entries selected by path keys become JSON arrays of objects carrying
+// their key leafs, following RFC 7951. Unqualified path elements match
+// module-qualified JSON members ("interfaces" matches
+// "openconfig-interfaces:interfaces"). Non-object values, including JSON
+// arrays, are replaced by later updates.
+//
+// The tree is rooted at the path of the Get request (the common part of all
+// requested paths, up to the first wildcard), so it can be fed back into Set:
+//
+//	res, err := client.Get(ctx, []string{"/system/config"}, gnmi.GetDataType(gnmi.DataTypeConfig))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	backup, err := res.MergedJSON()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	// Later: restore the backup
+//	_, err = client.Set(ctx, []gnmi.SetOperation{gnmi.Replace("/system/config", backup)})
+//
+// For a GetRes not returned by Client.Get, the tree is rooted at the common
+// path of all updates.
+//
+// Value types are the same as for NotificationUpdate.Value.
+//
+// Returns the merged tree (nil if there are no updates at the root path) or
+// an error if a JSON payload is invalid or updates conflict (e.g., a leaf
+// value where a container is expected).
+func (r GetRes) Tree() (any, error) {
+	root, paths, err := r.mergeUpdates()
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	if r.request != nil && len(r.request.GetPath()) > 0 {
+		paths = paths[:0]
+		for _, p := range r.request.GetPath() {
+			paths = append(paths, joinPathElems(r.request.GetPrefix(), p))
+		}
+	}
+
+	tree, _ := subtree(root, commonPathElems(paths))
+	return tree, nil
+}
+
+// mergeUpdates merges all updates into a tree rooted at the absolute root
+//
+// Returns the tree and the full path of every update.
+func (r GetRes) mergeUpdates() (map[string]any, [][]*gnmipb.PathElem, error) {
+	root := map[string]any{}
+	var paths [][]*gnmipb.PathElem
+
+	for _, n := range r.Notifications {
+		for _, u := range n.GetUpdate() {
+			elems := joinPathElems(n.GetPrefix(), u.GetPath())
+			paths = append(paths, elems)
+
+			value, err := treeValue(u.GetVal())
+			if err != nil {
+				return nil, nil, fmt.Errorf("update %s: %w", pathToString(n.GetPrefix(), u.GetPath()), err)
+			}
+			if err := mergeTreeAt(root, elems, value); err != nil {
+				return nil, nil, fmt.Errorf("update %s: %w", pathToString(n.GetPrefix(), u.GetPath()), err)
+			}
+		}
+	}
+
+	return root, paths, nil
+}
+
+// MergedJSON returns the merged tree of all updates as a JSON document
+//
+// See Tree for how updates are merged and where the document is rooted.
+// Object members are sorted by name, so the output is stable and can be
+// stored and diffed. Use json.Indent for a human-readable form.
+//
+// Example:
+//
+//	res, err := client.Get(ctx, []string{"/interfaces"}, gnmi.GetDataType(gnmi.DataTypeConfig))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	doc, err := res.MergedJSON()
+//
+// Returns the JSON document ("null" if there are no updates) or an error if
+// the updates cannot be merged.
+func (r GetRes) MergedJSON() (string, error) {
+	tree, err := r.Tree()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// joinPathElems returns the elements of prefix followed by those of path
+func joinPathElems(prefix, path *gnmipb.Path) []*gnmipb.PathElem {
+	elems := make([]*gnmipb.PathElem, 0, len(prefix.GetElem())+len(path.GetElem()))
+	elems = append(elems, prefix.GetElem()...)
+	return append(elems, path.GetElem()...)
+}
+
+// commonPathElems returns the longest common leading elements of all paths,
+// stopping before the first element that contains a wildcard
+func commonPathElems(paths [][]*gnmipb.PathElem) []*gnmipb.PathElem {
+	common := paths[0]
+	for _, p := range paths[1:] {
+		n := 0
+		for n < len(common) && n < len(p) && proto.Equal(common[n], p[n]) {
+			n++
+		}
+		common = common[:n]
+	}
+
+	for i, elem := range common {
+		if isWildcardElem(elem) {
+			return common[:i]
+		}
+	}
+	return common
+}
+
+// isWildcardElem reports whether a path element contains a wildcard
+func isWildcardElem(elem *gnmipb.PathElem) bool {
+	if elem.GetName() == "*" || elem.GetName() == "..." {
+		return true
+	}
+	for _, v := range elem.GetKey() {
+		if v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// treeValue decodes a TypedValue for merging into a tree
+//
+// Unlike decodeTypedValue, invalid JSON payloads are reported as errors.
+func treeValue(tv *gnmipb.TypedValue) (any, error) {
+	var data []byte
+	switch v := tv.GetValue().(type) {
+	case *gnmipb.TypedValue_JsonIetfVal:
+		data = v.JsonIetfVal
+	case *gnmipb.TypedValue_JsonVal:
+		data = v.JsonVal
+	default:
+		return decodeTypedValue(tv), nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON value: %w", err)
+	}
+	return value, nil
+}
+
+// mergeTreeAt merges value into the tree at the given path, creating missing
+// containers and list entries
+func mergeTreeAt(root map[string]any, elems []*gnmipb.PathElem, value any) error {
+	if len(elems) == 0 {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("root value must be a JSON object")
+		}
+		mergeTreeObjects(root, obj)
+		return nil
+	}
+
+	parent := root
+	for i, elem := range elems {
+		last := i == len(elems)-1
+		name := treeMember(parent, elem.GetName())
+
+		if len(elem.GetKey()) == 0 {
+			if last {
+				parent[name] = mergeTreeValues(parent[name], value)
+				return nil
+			}
+			child, ok := parent[name].(map[string]any)
+			if !ok {
+				if parent[name] != nil {
+					return fmt.Errorf("%s is not a container", elem.GetName())
+				}
+				child = map[string]any{}
+				parent[name] = child
+			}
+			parent = child
+			continue
+		}
+
+		// List entry: locate or create the entry carrying the keys
+		list, ok := parent[name].([]any)
+		if !ok && parent[name] != nil {
+			return fmt.Errorf("%s is not a list", elem.GetName())
+		}
+		entry := findTreeEntry(list, elem.GetKey())
+		if entry == nil {
+			entry = make(map[string]any, len(elem.GetKey()))
+			for k, v := range elem.GetKey() {
+				entry[k] = v
+			}
+			parent[name] = append(list, entry)
+		}
+
+		if last {
+			obj, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("list entry %s value must be a JSON object", elem.GetName())
+			}
+			mergeTreeObjects(entry, obj)
+			return nil
+		}
+		parent = entry
+	}
+
+	return nil
+}
+
+// subtree returns the node at the given path
+//
+// Returns false if any element along the path does not exist.
+func subtree(root map[string]any, elems []*gnmipb.PathElem) (any, bool) {
+	var node any = root
+	for _, elem := range elems {
+		obj, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		name, ok := lookupTreeMember(obj, elem.GetName())
+		if !ok {
+			return nil, false
+		}
+		node = obj[name]
+
+		if len(elem.GetKey()) > 0 {
+			list, _ := node.([]any)
+			entry := findTreeEntry(list, elem.GetKey())
+			if entry == nil {
+				return nil, false
+			}
+			node = entry
+		}
+	}
+	return node, true
+}
+
+// mergeTreeObjects recursively merges src into dst
+func mergeTreeObjects(dst, src map[string]any) {
+	for k, v := range src {
+		name := treeMember(dst, k)
+		dst[name] = mergeTreeValues(dst[name], v)
+	}
+}
+
+// mergeTreeValues merges value into existing if both are objects, otherwise
+// value replaces existing
+func mergeTreeValues(existing, value any) any {
+	dst, ok := existing.(map[string]any)
+	src, isObj := value.(map[string]any)
+	if ok && isObj {
+		mergeTreeObjects(dst, src)
+		return dst
+	}
+	return value
+}
+
+// findTreeEntry returns the list entry matching all keys, or nil
+//
+// Key values are compared in their string form since gNMI keys are strings.
+func findTreeEntry(list []any, keys map[string]string) map[string]any {
+	for _, item := range list {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		match := true
+		for k, v := range keys {
+			name, ok := lookupTreeMember(entry, k)
+			if !ok || fmt.Sprint(entry[name]) != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return entry
+		}
+	}
+	return nil
+}
+
+// lookupTreeMember resolves a name to an existing member of obj
+//
+// Exact matches win; otherwise a member with the same local name (without
+// module prefix) is returned.
+func lookupTreeMember(obj map[string]any, name string) (string, bool) {
+	if _, ok := obj[name]; ok {
+		return name, true
+	}
+	local := localName(name)
+	for member := range obj {
+		if localName(member) == local {
+			return member, true
+		}
+	}
+	return "", false
+}
+
+// treeMember resolves the member name to use when writing name into obj
+//
+// An existing member with the same local name is reused. If name is
+// module-qualified and the existing member is not, the member is renamed so
+// the qualified form is kept.
+func treeMember(obj map[string]any, name string) string {
+	member, ok := lookupTreeMember(obj, name)
+	if !ok {
+		return name
+	}
+	if member != name && strings.Contains(name, ":") && !strings.Contains(member, ":") {
+		obj[name] = obj[member]
+		delete(obj, member)
+		return name
+	}
+	return member
+}
+
+// localName strips the module prefix from a JSON member or path element name
+func localName(name string) string {
+	if idx := strings.IndexByte(name, ':'); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"strings"
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// mustProtoPath parses a path string for test fixtures
+func mustProtoPath(t *testing.T, path string) *gnmipb.Path {
+	t.Helper()
+	p, err := ParsePath(path)
+	if err != nil {
+		t.Fatalf("ParsePath(%q) error = %v", path, err)
+	}
+	return p.Proto()
+}
+
+func jsonIetf(value string) *gnmipb.TypedValue {
+	return &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(value)}}
+}
+
+// TestGetRes_MergedJSON tests merging of Get notifications into one document
+func TestGetRes_MergedJSON(t *testing.T) {
+	type update struct {
+		prefix string
+		path   string
+		val    *gnmipb.TypedValue
+	}
+
+	tests := []struct {
+		name    string
+		request []string
+		updates []update
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "single update at requested path",
+			request: []string{"/system/config"},
+			updates: []update{{path: "/system/config", val: jsonIetf(`{"hostname": "router1"}`)}},
+			want:    `{"hostname":"router1"}`,
+		},
+		{
+			name:    "leaf updates with prefix",
+			request: []string{"/system/config"},
+			updates: []update{
+				{prefix: "/system/config", path: "/hostname", val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_StringVal{StringVal: "router1"}}},
+				{prefix: "/system/config", path: "/login-banner", val: jsonIetf(`"welcome"`)},
+			},
+			want: `{"hostname":"router1","login-banner":"welcome"}`,
+		},
+		{
+			name:    "list entries from separate notifications",
+			request: []string{"/interfaces"},
+			updates: []update{
+				{prefix: "/interfaces/interface[name=eth0]", path: "/config", val: jsonIetf(`{"mtu": 1500}`)},
+				{prefix: "/interfaces/interface[name=eth1]", path: "/config", val: jsonIetf(`{"mtu": 9000}`)},
+				{prefix: "/interfaces/interface[name=eth0]", path: "/state", val: jsonIetf(`{"oper-status": "UP"}`)},
+			},
+			want: `{"interface":[{"config":{"mtu":1500},"name":"eth0","state":{"oper-status":"UP"}},{"config":{"mtu":9000},"name":"eth1"}]}`,
+		},
+		{
+			name:    "module-qualified members",
+			request: []string{"/"},
+			updates: []update{
+				{path: "/", val: jsonIetf(`{"openconfig-system:system": {"config": {"hostname": "r1"}}}`)},
+				{path: "/system/state", val: jsonIetf(`{"boot-time": 1}`)},
+			},
+			want: `{"openconfig-system:system":{"config":{"hostname":"r1"},"state":{"boot-time":1}}}`,
+		},
+		{
+			name:    "qualified path element renames member",
+			request: []string{"/"},
+			updates: []update{
+				{path: "/system/config", val: jsonIetf(`{"hostname": "r1"}`)},
+				{path: "/openconfig-system:system/state", val: jsonIetf(`{"boot-time": 1}`)},
+			},
+			want: `{"openconfig-system:system":{"config":{"hostname":"r1"},"state":{"boot-time":1}}}`,
+		},
+		{
+			name:    "rooted before wildcard",
+			request: []string{"/interfaces/interface[name=*]/config"},
+			updates: []update{
+				{path: "/interfaces/interface[name=eth0]/config", val: jsonIetf(`{"mtu": 1500}`)},
+			},
+			want: `{"interface":[{"config":{"mtu":1500},"name":"eth0"}]}`,
+		},
+		{
+			name:    "multiple requested paths rooted at common path",
+			request: []string{"/system/config", "/system/state"},
+			updates: []update{
+				{path: "/system/config", val: jsonIetf(`{"hostname": "r1"}`)},
+				{path: "/system/state", val: jsonIetf(`{"hostname": "r1"}`)},
+			},
+			want: `{"config":{"hostname":"r1"},"state":{"hostname":"r1"}}`,
+		},
+		{
+			name: "no request rooted at common update path",
+			updates: []update{
+				{path: "/system/config/hostname", val: jsonIetf(`"r1"`)},
+			},
+			want: `"r1"`,
+		},
+		{
+			name:    "no updates",
+			request: []string{"/system"},
+			want:    `null`,
+		},
+		{
+			name:    "invalid JSON",
+			request: []string{"/system"},
+			updates: []update{{path: "/system", val: jsonIetf(`{broken`)}},
+			wantErr: "invalid JSON value",
+		},
+		{
+			name:    "leaf where container expected",
+			request: []string{"/system"},
+			updates: []update{
+				{path: "/system/config", val: jsonIetf(`"leaf"`)},
+				{path: "/system/config/hostname", val: jsonIetf(`"r1"`)},
+			},
+			wantErr: "is not a container",
+		},
+		{
+			name:    "list entry value not an object",
+			request: []string{"/interfaces"},
+			updates: []update{{path: "/interfaces/interface[name=eth0]", val: jsonIetf(`1`)}},
+			wantErr: "must be a JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := GetRes{OK: true}
+			for _, u := range tt.updates {
+				n := &gnmipb.Notification{Update: []*gnmipb.Update{{Path: mustProtoPath(t, u.path), Val: u.val}}}
+				if u.prefix != "" {
+					n.Prefix = mustProtoPath(t, u.prefix)
+				}
+				res.Notifications = append(res.Notifications, n)
+			}
+			if tt.request != nil {
+				res.request = &gnmipb.GetRequest{}
+				for _, p := range tt.request {
+					res.request.Path = append(res.request.Path, mustProtoPath(t, p))
+				}
+			}
+
+			got, err := res.MergedJSON()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("MergedJSON() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergedJSON() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MergedJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
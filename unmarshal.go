@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// UnmarshalOptions controls how data is decoded by Unmarshal
+//
+// Options are set via functional options passed to GetRes.Unmarshal and
+// Notification.Unmarshal.
+type UnmarshalOptions struct {
+	// KeepModulePrefixes keeps module prefixes on JSON member names
+	//
+	// By default, module prefixes are stripped so struct tags use plain
+	// names (json:"interface"). When true, member names are passed through
+	// unchanged and struct tags must match them exactly
+	// (json:"openconfig-interfaces:interfaces").
+	KeepModulePrefixes bool
+
+	// DisallowUnknownFields rejects members that do not map to a struct field
+	DisallowUnknownFields bool
+}
+
+// KeepModulePrefixes keeps module prefixes on JSON member names when unmarshaling
+//
+// Use this when struct tags are written with module-qualified names, or when
+// a subtree contains members from different modules that share a local name.
+//
+// Example:
+//
+//	type Root struct {
+//	    Interfaces Interfaces `json:"openconfig-interfaces:interfaces"`
+//	}
+//	var root Root
+//	err := res.Unmarshal("/", &root, gnmi.KeepModulePrefixes(true))
+func KeepModulePrefixes(enabled bool) func(*UnmarshalOptions) {
+	return func(o *UnmarshalOptions) {
+		o.KeepModulePrefixes = enabled
+	}
+}
+
+// DisallowUnknownFields makes Unmarshal fail on members without a matching struct field
+//
+// Example:
+//
+//	err := res.Unmarshal("/system/config", &cfg, gnmi.DisallowUnknownFields(true))
+func DisallowUnknownFields(enabled bool) func(*UnmarshalOptions) {
+	return func(o *UnmarshalOptions) {
+		o.DisallowUnknownFields = enabled
+	}
+}
+
+// Unmarshal decodes the data at path into v
+//
+// All updates are merged into a single tree (see Tree) and the node at path
+// is decoded into v using encoding/json, so v is typically a pointer to a
+// struct with json tags. The path is absolute (not relative to the requested
+// path) and may select list entries by key. Unqualified path elements match
+// module-qualified members.
+//
+// By default, module prefixes are stripped from member names before
+// decoding, so struct tags use plain names. Use KeepModulePrefixes to decode
+// with the names sent by the target.
+//
+// Example:
+//
+//	type InterfaceConfig struct {
+//	    Name        string `json:"name"`
+//	    Description string `json:"description"`
+//	    MTU         uint16 `json:"mtu"`
+//	}
+//
+//	res, err := client.Get(ctx, []string{"/interfaces/interface[name=eth0]/config"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	var cfg InterfaceConfig
+//	if err := res.Unmarshal("/interfaces/interface[name=eth0]/config", &cfg); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Returns an error if the path is invalid, there is no data at path, the
+// updates cannot be merged, or the data does not fit v.
+func (r GetRes) Unmarshal(path string, v any, opts ...func(*UnmarshalOptions)) error {
+	root, _, err := r.mergeUpdates()
+	if err != nil {
+		return err
+	}
+	return unmarshalTree(root, path, v, opts)
+}
+
+// Unmarshal decodes the data at path within the notification into v
+//
+// The updates of the notification are merged into a single tree and the
+// node at path is decoded like GetRes.Unmarshal. The path is absolute
+// (including the notification prefix).
+//
+// Example:
+//
+//	for res := range stream.Responses() {
+//	    var counters Counters
+//	    err := res.Notification.Unmarshal("/interfaces/interface[name=eth0]/state/counters", &counters)
+//	    if err != nil {
+//	        continue
+//	    }
+//	}
+//
+// Returns an error if the path is invalid, there is no data at path, the
+// updates cannot be merged, or the data does not fit v.
+func (n Notification) Unmarshal(path string, v any, opts ...func(*UnmarshalOptions)) error {
+	root := map[string]any{}
+	for _, u := range n.Updates {
+		p, err := ParsePath(u.Path)
+		if err != nil {
+			return fmt.Errorf("update %s: %w", u.Path, err)
+		}
+		if err := mergeTreeAt(root, p.elems, copyTreeValue(u.Value)); err != nil {
+			return fmt.Errorf("update %s: %w", u.Path, err)
+		}
+	}
+	return unmarshalTree(root, path, v, opts)
+}
+
+// unmarshalTree decodes the node at path within root into v
+func unmarshalTree(root map[string]any, path string, v any, opts []func(*UnmarshalOptions)) error {
+	options := UnmarshalOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	p, err := ParsePath(path)
+	if err != nil {
+		return err
+	}
+
+	node, ok := subtree(root, p.elems)
+	if !ok {
+		return fmt.Errorf("no data at path %s", path)
+	}
+
+	if !options.KeepModulePrefixes {
+		node, err = stripModulePrefixes(node)
+		if err != nil {
+			return fmt.Errorf("path %s: %w", path, err)
+		}
+	}
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("path %s: %w", path, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if options.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("path %s: %w", path, err)
+	}
+	return nil
+}
+
+// stripModulePrefixes returns a copy of value with module prefixes removed
+// from all object member names
+//
+// Returns an error if two members of the same object share a local name.
+func stripModulePrefixes(value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for member, child := range v {
+			name := localName(member)
+			if _, exists := obj[name]; exists {
+				return nil, fmt.Errorf("ambiguous member %s without module prefix", name)
+			}
+			stripped, err := stripModulePrefixes(child)
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = stripped
+		}
+		return obj, nil
+	case []any:
+		list := make([]any, len(v))
+		for i, child := range v {
+			stripped, err := stripModulePrefixes(child)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = stripped
+		}
+		return list, nil
+	default:
+		return value, nil
+	}
+}
+
+// copyTreeValue returns a deep copy of the objects and arrays in value
+//
+// mergeTreeAt modifies objects in place, so values owned by the caller are
+// copied before merging.
+func copyTreeValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for member, child := range v {
+			obj[member] = copyTreeValue(child)
+		}
+		return obj
+	case []any:
+		list := make([]any, len(v))
+		for i, child := range v {
+			list[i] = copyTreeValue(child)
+		}
+		return list
+	default:
+		return value
+	}
+}
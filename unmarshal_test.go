@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2025 Daniel Schmidt
+
+package gnmi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+type testInterfaceConfig struct {
+	Name    string `json:"name"`
+	MTU     uint16 `json:"mtu"`
+	Enabled bool   `json:"enabled"`
+}
+
+type testInterface struct {
+	Name   string              `json:"name"`
+	Config testInterfaceConfig `json:"config"`
+}
+
+type testInterfaces struct {
+	Interface []testInterface `json:"interface"`
+}
+
+type testQualifiedRoot struct {
+	Interfaces testInterfaces `json:"openconfig-interfaces:interfaces"`
+}
+
+// newTestGetRes builds a GetRes with one notification per path/value pair
+func newTestGetRes(t *testing.T, updates ...string) GetRes {
+	t.Helper()
+	res := GetRes{OK: true}
+	for i := 0; i < len(updates); i += 2 {
+		res.Notifications = append(res.Notifications, &gnmipb.Notification{
+			Update: []*gnmipb.Update{{Path: mustProtoPath(t, updates[i]), Val: jsonIetf(updates[i+1])}},
+		})
+	}
+	return res
+}
+
+// TestGetRes_Unmarshal tests decoding of merged Get data into structs
+func TestGetRes_Unmarshal(t *testing.T) {
+	res := newTestGetRes(t,
+		"/", `{"openconfig-interfaces:interfaces": {"interface": [{"name": "eth0", "config": {"name": "eth0", "mtu": 1500}}]}}`,
+		"/interfaces/interface[name=eth1]/config", `{"name": "eth1", "mtu": 9000, "enabled": true}`,
+	)
+
+	t.Run("list", func(t *testing.T) {
+		var got testInterfaces
+		if err := res.Unmarshal("/interfaces", &got); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		want := testInterfaces{Interface: []testInterface{
+			{Name: "eth0", Config: testInterfaceConfig{Name: "eth0", MTU: 1500}},
+			{Name: "eth1", Config: testInterfaceConfig{Name: "eth1", MTU: 9000, Enabled: true}},
+		}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Unmarshal() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("list entry by key", func(t *testing.T) {
+		var got testInterfaceConfig
+		if err := res.Unmarshal("/interfaces/interface[name=eth1]/config", &got); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		want := testInterfaceConfig{Name: "eth1", MTU: 9000, Enabled: true}
+		if got != want {
+			t.Errorf("Unmarshal() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("leaf", func(t *testing.T) {
+		var got uint16
+		if err := res.Unmarshal("/interfaces/interface[name=eth0]/config/mtu", &got); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if got != 1500 {
+			t.Errorf("Unmarshal() = %d, want 1500", got)
+		}
+	})
+
+	t.Run("keep module prefixes", func(t *testing.T) {
+		var got testQualifiedRoot
+		if err := res.Unmarshal("/", &got, KeepModulePrefixes(true)); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if ifs := got.Interfaces.Interface; len(ifs) != 2 {
+			t.Errorf("Unmarshal() interfaces = %+v, want 2 entries", ifs)
+		}
+	})
+
+	errTests := []struct {
+		name    string
+		path    string
+		v       any
+		opts    []func(*UnmarshalOptions)
+		wantErr string
+	}{
+		{name: "missing path", path: "/system", v: &testInterfaces{}, wantErr: "no data at path"},
+		{name: "missing list entry", path: "/interfaces/interface[name=eth9]", v: &testInterface{}, wantErr: "no data at path"},
+		{name: "invalid path", path: "/interfaces[name=x", v: &testInterfaces{}, wantErr: "unterminated key"},
+		{name: "type mismatch", path: "/interfaces/interface[name=eth0]/config/name", v: new(int), wantErr: "cannot unmarshal"},
+		{
+			name:    "unknown fields",
+			path:    "/interfaces/interface[name=eth0]",
+			v:       &struct{ Name string }{},
+			opts:    []func(*UnmarshalOptions){DisallowUnknownFields(true)},
+			wantErr: "unknown field",
+		},
+	}
+
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := res.Unmarshal(tt.path, tt.v, tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Unmarshal() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGetRes_Unmarshal_AmbiguousPrefixes tests that colliding local names are rejected
+func TestGetRes_Unmarshal_AmbiguousPrefixes(t *testing.T) {
+	res := newTestGetRes(t, "/system", `{"vendor-a:ntp": {"enabled": true}, "vendor-b:ntp": {"enabled": false}}`)
+
+	var got map[string]any
+	if err := res.Unmarshal("/system", &got); err == nil || !strings.Contains(err.Error(), "ambiguous member ntp") {
+		t.Errorf("Unmarshal() error = %v, want ambiguous member", err)
+	}
+	if err := res.Unmarshal("/system", &got, KeepModulePrefixes(true)); err != nil {
+		t.Errorf("Unmarshal() with KeepModulePrefixes error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("Unmarshal() = %v, want both members", got)
+	}
+}
+
+// TestNotification_Unmarshal tests decoding of subscription updates into structs
+func TestNotification_Unmarshal(t *testing.T) {
+	n := newNotification(&gnmipb.Notification{
+		Prefix: mustProtoPath(t, "/interfaces/interface[name=eth0]"),
+		Update: []*gnmipb.Update{
+			{Path: mustProtoPath(t, "/config"), Val: jsonIetf(`{"openconfig-interfaces:name": "eth0"}`)},
+			{Path: mustProtoPath(t, "/config/mtu"), Val: &gnmipb.TypedValue{Value: &gnmipb.TypedValue_UintVal{UintVal: 1500}}},
+		},
+	})
+
+	var got testInterface
+	if err := n.Unmarshal("/interfaces/interface[name=eth0]", &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := testInterface{Name: "eth0", Config: testInterfaceConfig{Name: "eth0", MTU: 1500}}
+	if got != want {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, want)
+	}
+
+	// Merging must not modify the decoded update values
+	config, _ := n.Updates[0].Value.(map[string]any)
+	if _, ok := config["mtu"]; ok {
+		t.Errorf("Unmarshal() modified update value: %v", config)
+	}
+
+	if err := n.Unmarshal("/interfaces/interface[name=eth0]/state", &got); err == nil {
+		t.Errorf("Unmarshal() expected error for missing path")
+	}
+}